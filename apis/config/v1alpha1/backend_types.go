@@ -20,10 +20,20 @@ type BackendSpec struct {
 	// established.
 	// +optional
 	CheckTimeout *metav1.Duration `json:"checkTimeout,omitempty"`
+	// HTTPCheck enables HTTP health checks of the servers.
+	// +optional
+	HTTPCheck *HTTPCheck `json:"httpCheck,omitempty"`
 	// Servers defines the backend servers and its configuration.
 	Servers []Server `json:"servers,omitempty"`
 	// ServerTemplates defines the backend server templates and its configuration.
 	ServerTemplates []ServerTemplate `json:"serverTemplates,omitempty"`
+	// ServiceRef discovers the backend servers from the EndpointSlices of a Service.
+	// +optional
+	ServiceRef *ServiceReference `json:"serviceRef,omitempty"`
+	// ServiceRefs discovers the backend servers from the EndpointSlices of several Services, e.g. to split the
+	// traffic between them by weight. The server names are prefixed with the name of the Service.
+	// +optional
+	ServiceRefs []ServiceReference `json:"serviceRefs,omitempty"`
 	// Balance defines the load balancing algorithm to be used in a backend.
 	// +optional
 	Balance *Balance `json:"balance,omitempty"`
@@ -36,12 +46,38 @@ type BackendSpec struct {
 	// Redispatch enable or disable session redistribution in case of connection failure
 	// +optional
 	Redispatch *bool `json:"redispatch,omitempty"`
+	// RedispatchInterval redispatches the session to another server on every Nth retry, or only on the Nth last
+	// retry if negative. Defaults to 3.
+	// +optional
+	RedispatchInterval *int64 `json:"redispatchInterval,omitempty"`
+	// Retries is the number of retries after a connection failure to a server.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retries *int64 `json:"retries,omitempty"`
+	// RetryOn lists the failures triggering a retry. Defaults to conn-failure.
+	// +optional
+	RetryOn []RetryOnCondition `json:"retryOn,omitempty"`
+	// HTTPReuse declares how idle connections to the servers are shared between requests.
+	// +kubebuilder:validation:Enum=never;safe;aggressive;always
+	// +optional
+	HTTPReuse string `json:"httpReuse,omitempty"`
+	// HTTPRestrictReqHdrNames declares how request header names containing characters other than letters, digits
+	// and hyphens are handled.
+	// +kubebuilder:validation:Enum=preserve;delete;reject
+	// +optional
+	HTTPRestrictReqHdrNames string `json:"httpRestrictReqHdrNames,omitempty"`
+	// HTTPBufferRequest waits for the request body before the server is selected, e.g. to balance on its content.
+	// +optional
+	HTTPBufferRequest *bool `json:"httpBufferRequest,omitempty"`
 	// HashType specifies a method to use for mapping hashes to servers
 	// +optional
 	HashType *HashType `json:"hashType,omitempty"`
 	// Cookie enables cookie-based persistence in a backend.
 	// +optional
 	Cookie *Cookie `json:"cookie,omitempty"`
+	// StickTable declares a stick table in the backend.
+	// +optional
+	StickTable *StickTable `json:"stickTable,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -91,6 +127,10 @@ func (b *Backend) Model() (models.Backend, error) {
 		}
 	}
 
+	if b.Spec.HTTPCheck != nil {
+		model.AdvCheck = models.BackendAdvCheckHttpchk
+	}
+
 	if b.Spec.HTTPPretendKeepalive != nil && *b.Spec.HTTPPretendKeepalive {
 		model.HTTPPretendKeepalive = models.BackendHTTPPretendKeepaliveEnabled
 	}
@@ -98,7 +138,26 @@ func (b *Backend) Model() (models.Backend, error) {
 	if b.Spec.Redispatch != nil && *b.Spec.Redispatch {
 		model.Redispatch = &models.Redispatch{
 			Enabled:  pointer.String(models.RedispatchEnabledEnabled),
-			Interval: 3,
+			Interval: pointer.Int64Deref(b.Spec.RedispatchInterval, 3),
+		}
+	}
+
+	model.Retries = b.Spec.Retries
+	model.HTTPReuse = b.Spec.HTTPReuse
+	model.HTTPRestrictReqHdrNames = b.Spec.HTTPRestrictReqHdrNames
+
+	if len(b.Spec.RetryOn) > 0 {
+		conditions := make([]string, len(b.Spec.RetryOn))
+		for idx, condition := range b.Spec.RetryOn {
+			conditions[idx] = string(condition)
+		}
+		model.RetryOn = strings.Join(conditions, " ")
+	}
+
+	if b.Spec.HTTPBufferRequest != nil {
+		model.HTTPBufferRequest = models.BackendHTTPBufferRequestDisabled
+		if *b.Spec.HTTPBufferRequest {
+			model.HTTPBufferRequest = models.BackendHTTPBufferRequestEnabled
 		}
 	}
 
@@ -159,6 +218,12 @@ func (b *Backend) Model() (models.Backend, error) {
 		}
 	}
 
+	stickTable, err := b.Spec.StickTableModel(b.Spec.StickTable)
+	if err != nil {
+		return model, err
+	}
+	model.StickTable = stickTable
+
 	for name, timeout := range b.Spec.Timeouts {
 		switch name {
 		case "check":
@@ -211,14 +276,27 @@ func (b *Backend) AddToParser(p parser.Parser) error {
 		return err
 	}
 
-	for idx, server := range b.Spec.Servers {
-		model, err := server.Model()
+	if b.Spec.HTTPCheck != nil {
+		checks, err := b.Spec.HTTPCheck.Model()
+		if err != nil {
+			return err
+		}
 
-		if server.SSL != nil && server.SSL.Verify == "required" {
-			model.Verify = server.SSL.Verify
-			model.Alpn = strings.Join(server.SSL.Alpn, ",")
+		for idx, check := range checks {
+			data, err := configuration.SerializeHTTPCheck(*check)
+			if err != nil {
+				return err
+			}
+
+			err = p.Insert(parser.Backends, b.Name, "http-check", data, idx)
+			if err != nil {
+				return err
+			}
 		}
+	}
 
+	for idx, server := range b.Spec.Servers {
+		model, err := server.Model()
 		if err != nil {
 			return err
 		}
@@ -241,6 +319,42 @@ func (b *Backend) AddToParser(p parser.Parser) error {
 		}
 	}
 
+	idx := len(b.Spec.Servers)
+	if b.Spec.ServiceRef != nil {
+		servers, err := b.Spec.ServiceRef.Model()
+		if err != nil {
+			return err
+		}
+
+		for _, model := range servers {
+			err = p.Insert(parser.Backends, b.Name, "server", configuration.SerializeServer(model), idx)
+			if err != nil {
+				return err
+			}
+			idx++
+		}
+	}
+
+	for _, ref := range b.Spec.ServiceRefs {
+		prefix := ref.Name + "-"
+		if ref.Namespace != "" {
+			prefix = ref.Namespace + "-" + prefix
+		}
+
+		servers, err := ref.model(prefix)
+		if err != nil {
+			return err
+		}
+
+		for _, model := range servers {
+			err = p.Insert(parser.Backends, b.Name, "server", configuration.SerializeServer(model), idx)
+			if err != nil {
+				return err
+			}
+			idx++
+		}
+	}
+
 	return nil
 }
 
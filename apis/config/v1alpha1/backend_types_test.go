@@ -1,12 +1,15 @@
 package v1alpha1_test
 
 import (
+	"encoding/json"
 	"time"
 
 	parser "github.com/haproxytech/config-parser/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
@@ -138,6 +141,340 @@ var _ = Describe("Backend", Label("type"), func() {
 			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
 			Ω(p.String()).Should(ContainSubstring("http-request deny deny_status 404 if { var(my-ip) -m ip 127.0.0.0/8 10.0.0.0/8 }\n"))
 		})
+		It("should decode the single deny rule of existing resources", func() {
+			var rules configv1alpha1.HTTPRequestRules
+			Ω(json.Unmarshal([]byte(`{"deny":{"enabled":true,"conditionType":"if","condition":"{ path_beg /admin }"},"denyStatus":404}`), &rules)).ShouldNot(HaveOccurred())
+			Ω(rules.Deny).Should(Equal(&configv1alpha1.Deny{Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ path_beg /admin }"}, Enabled: true}))
+		})
+		It("should set multiple http-request deny rules", func() {
+			var notFound int64 = 404
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "openshift_default"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						HTTPRequest: &configv1alpha1.HTTPRequestRules{
+							Deny: &configv1alpha1.Deny{
+								Rule:    configv1alpha1.Rule{ConditionType: "if", Condition: "{ src 192.0.2.0/24 }"},
+								Enabled: true,
+							},
+							DenyRules: []configv1alpha1.HTTPDenyRule{
+								{
+									Rule: configv1alpha1.Rule{
+										ConditionType: "if",
+										Condition:     "{ var(my-ip) -m ip 127.0.0.0/8 10.0.0.0/8 }",
+									},
+								},
+								{
+									Rule:   configv1alpha1.Rule{ConditionType: "if", Condition: "{ path_beg /admin }"},
+									Status: pointer.Int64(403),
+								},
+							},
+							DenyStatus: &notFound,
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring("http-request deny deny_status 404 if { src 192.0.2.0/24 }\n" +
+				"  http-request deny deny_status 404 if { var(my-ip) -m ip 127.0.0.0/8 10.0.0.0/8 }\n" +
+				"  http-request deny deny_status 403 if { path_beg /admin }\n"))
+		})
+		It("should set the http-request actions grouped in their order", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						HTTPRequest: &configv1alpha1.HTTPRequestRules{
+							UseService:    []configv1alpha1.HTTPNameRule{{Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ path /metrics }"}, Name: "prometheus-exporter"}},
+							SilentDrop:    []configv1alpha1.Rule{{ConditionType: "if", Condition: "{ src 192.0.2.0/24 }"}},
+							Tarpit:        []configv1alpha1.HTTPDenyRule{{Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ path_beg /wp- }"}}},
+							ReplaceURI:    []configv1alpha1.HTTPReplaceRule{{Match: "^/old/(.*)", Format: "/new/\\1"}},
+							SetURI:        []configv1alpha1.HTTPFormatRule{{Format: "/%[var(txn.uri)]"}},
+							SetMethod:     []configv1alpha1.HTTPFormatRule{{Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "METH_HEAD"}, Format: "GET"}},
+							SetQuery:      []configv1alpha1.HTTPFormatRule{{Format: "%[query,regsub(%3D,=,g)]"}},
+							ReplacePath:   []configv1alpha1.HTTPReplaceRule{{Match: "^/api/(.*)", Format: "/\\1"}},
+							ReplaceHeader: []configv1alpha1.HTTPReplaceHeaderRule{{Name: "Cookie", Match: "(.*)(SESSID=[^;]*;?)(.*)", Format: "\\1\\3"}},
+							DelHeader:     []configv1alpha1.HTTPDelHeaderRule{{Name: "X-Forwarded-", Method: "beg"}},
+							NormalizeURI:  []configv1alpha1.HTTPNormalizeURIRule{{Normalizer: "path-merge-slashes"}, {Normalizer: "percent-to-uppercase", Strict: true}},
+							SetSrc:        []configv1alpha1.HTTPExpressionRule{{Expression: "hdr(x-real-ip)"}},
+							UnsetVar:      []configv1alpha1.HTTPUnsetVarRule{{Scope: "txn", Name: "uri"}},
+							SetVar:        []configv1alpha1.HTTPSetVarRule{{Scope: "txn", Name: "uri", Expression: "path"}},
+							SetLogLevel:   []configv1alpha1.HTTPSetLogLevelRule{{Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ path /healthz }"}, Level: "silent"}},
+							Capture:       []configv1alpha1.HTTPCaptureRule{{Sample: "req.hdr(host)", Length: pointer.Int64(32)}},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring(`  http-request capture req.hdr(host) len 32
+  http-request set-log-level silent if { path /healthz }
+  http-request set-var(txn.uri) path
+  http-request unset-var(txn.uri)
+  http-request set-src hdr(x-real-ip)
+  http-request normalize-uri path-merge-slashes
+  http-request normalize-uri percent-to-uppercase strict
+  http-request del-header X-Forwarded- -m beg
+  http-request replace-header Cookie (.*)(SESSID=[^;]*;?)(.*) \1\3
+  http-request replace-path ^/api/(.*) /\1
+  http-request set-query %[query,regsub(%3D,=,g)]
+  http-request set-method GET if METH_HEAD
+  http-request set-uri /%[var(txn.uri)]
+  http-request replace-uri ^/old/(.*) /new/\1
+  http-request tarpit if { path_beg /wp- }
+  http-request silent-drop if { src 192.0.2.0/24 }
+  http-request use-service prometheus-exporter if { path /metrics }
+`))
+		})
+		It("should set the http-request rules in the declared order after the grouped rules", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						HTTPRequest: &configv1alpha1.HTTPRequestRules{
+							DelHeader: []configv1alpha1.HTTPDelHeaderRule{{Name: "X-Debug"}},
+							Rules: []configv1alpha1.HTTPRequestRule{
+								{Redirect: &configv1alpha1.Redirect{
+									Rule:  configv1alpha1.Rule{ConditionType: "unless", Condition: "{ ssl_fc }"},
+									Type:  configv1alpha1.RedirectType{Scheme: true},
+									Value: "https",
+								}},
+								{SetHeader: &configv1alpha1.HTTPHeaderRule{Name: "X-Forwarded-Proto", Value: configv1alpha1.HTTPHeaderValue{Str: pointer.String("https")}}},
+								{Return: &configv1alpha1.HTTPReturnRule{
+									Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ path /healthz }"},
+									HTTPReturn: configv1alpha1.HTTPReturn{
+										Status:  pointer.Int64(200),
+										Content: configv1alpha1.HTTPReturnContent{Type: "text/plain", Format: "string", Value: "OK"},
+									},
+								}},
+								{Deny: &configv1alpha1.HTTPDenyRule{Status: pointer.Int64(404)}},
+							},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring(`  http-request del-header X-Debug
+  http-request redirect scheme https unless { ssl_fc }
+  http-request set-header X-Forwarded-Proto https
+  http-request return status 200 content-type text/plain string "OK" if { path /healthz }
+  http-request deny deny_status 404
+`))
+		})
+		It("should fall back to the deny status for the deny and tarpit rules", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						HTTPRequest: &configv1alpha1.HTTPRequestRules{
+							DenyStatus: pointer.Int64(403),
+							Rules: []configv1alpha1.HTTPRequestRule{
+								{Deny: &configv1alpha1.HTTPDenyRule{Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ path_beg /admin }"}}},
+								{Tarpit: &configv1alpha1.HTTPDenyRule{Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ path_beg /wp- }"}}},
+								{Deny: &configv1alpha1.HTTPDenyRule{Status: pointer.Int64(404)}},
+							},
+						},
+						HTTPResponse: &configv1alpha1.HTTPResponseRules{
+							DenyStatus: pointer.Int64(502),
+							Rules: []configv1alpha1.HTTPResponseRule{
+								{Deny: &configv1alpha1.HTTPDenyRule{Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ status 500 }"}}},
+							},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring(`  http-request deny deny_status 403 if { path_beg /admin }
+  http-request tarpit deny_status 403 if { path_beg /wp- }
+  http-request deny deny_status 404
+`))
+			Ω(p.String()).Should(ContainSubstring("  http-response deny deny_status 502 if { status 500 }\n"))
+		})
+		It("should set the http-response rules in the declared order after the grouped rules", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						HTTPResponse: &configv1alpha1.HTTPResponseRules{
+							HTTPAfterResponseRules: configv1alpha1.HTTPAfterResponseRules{
+								DelHeader: []configv1alpha1.HTTPDelHeaderRule{{Name: "Server"}},
+							},
+							Rules: []configv1alpha1.HTTPResponseRule{
+								{SetStatus: &configv1alpha1.HTTPSetStatusRule{
+									Rule:   configv1alpha1.Rule{ConditionType: "if", Condition: "{ status 500 }"},
+									Status: 503,
+								}},
+								{SetHeader: &configv1alpha1.HTTPHeaderRule{Name: "Cache-Control", Value: configv1alpha1.HTTPHeaderValue{Str: pointer.String("no-store")}}},
+								{Deny: &configv1alpha1.HTTPDenyRule{Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ status 418 }"}}},
+							},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring(`  http-response del-header Server
+  http-response set-status 503 if { status 500 }
+  http-response set-header Cache-Control no-store
+  http-response deny if { status 418 }
+`))
+
+			backend.Spec.HTTPResponse.Rules = []configv1alpha1.HTTPResponseRule{{}}
+			_, err := backend.Spec.HTTPResponse.Model()
+			Ω(err).Should(MatchError("http-response rule must set exactly one action, found 0"))
+		})
+		It("should reject http-request rules not setting exactly one action", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						HTTPRequest: &configv1alpha1.HTTPRequestRules{
+							Rules: []configv1alpha1.HTTPRequestRule{{}},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).Should(MatchError("http-request rule must set exactly one action, found 0"))
+
+			backend.Spec.HTTPRequest.Rules = []configv1alpha1.HTTPRequestRule{{
+				SilentDrop: &configv1alpha1.Rule{},
+				Deny:       &configv1alpha1.HTTPDenyRule{},
+			}}
+			_, err := backend.Spec.HTTPRequest.Model()
+			Ω(err).Should(MatchError("http-request rule must set exactly one action, found 2"))
+		})
+		It("should set http-check rules", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{Mode: "http"},
+					HTTPCheck: &configv1alpha1.HTTPCheck{
+						Connect: &configv1alpha1.HTTPCheckConnect{Port: pointer.Int64(8443), SSL: true, SNI: "app.local", ALPN: []string{"h2", "http/1.1"}},
+						Method:  "GET",
+						URI:     "/healthz",
+						Version: "HTTP/1.1",
+						Host:    "app.local",
+						Headers: []configv1alpha1.HTTPCheckHeader{{Name: "User-Agent", Value: "haproxy health check"}},
+						Expect: []configv1alpha1.HTTPCheckExpect{
+							{Status: "200-399"},
+							{Header: &configv1alpha1.HTTPCheckExpectHeader{Name: "Content-Type", Value: "application/json"}},
+							{String: "DOWN", Inverted: true},
+						},
+					},
+					Servers: []configv1alpha1.Server{
+						{
+							Name:    "app",
+							Port:    8080,
+							Address: "10.0.0.1",
+							ServerParams: configv1alpha1.ServerParams{
+								Check: &configv1alpha1.Check{
+									Enabled:   true,
+									Fastinter: &metav1.Duration{Duration: 500 * time.Millisecond},
+									Downinter: &metav1.Duration{Duration: 10 * time.Second},
+									Port:      pointer.Int64(8443),
+									Address:   "10.0.0.2",
+								},
+							},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring("  option httpchk\n"))
+			Ω(p.String()).Should(ContainSubstring(`  http-check connect port 8443 ssl sni app.local alpn h2,http/1.1
+  http-check send meth GET uri /healthz ver HTTP/1.1 hdr Host app.local hdr User-Agent "haproxy health check"
+  http-check expect status 200-399
+  http-check expect hdr name Content-Type value application/json
+  http-check expect ! string DOWN
+`))
+			Ω(p.String()).Should(ContainSubstring("server app 10.0.0.1:8080 check fastinter 500 downinter 10000 addr 10.0.0.2 port 8443"))
+		})
+		It("should set retries and connection reuse", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						Mode:     "http",
+						Timeouts: map[string]metav1.Duration{"queue": {Duration: 5 * time.Second}},
+					},
+					Redispatch:              pointer.Bool(true),
+					RedispatchInterval:      pointer.Int64(-1),
+					Retries:                 pointer.Int64(2),
+					RetryOn:                 []configv1alpha1.RetryOnCondition{"conn-failure", "empty-response", "503"},
+					HTTPReuse:               "safe",
+					HTTPRestrictReqHdrNames: "delete",
+					HTTPBufferRequest:       pointer.Bool(true),
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			for _, line := range []string{
+				"  option redispatch -1\n",
+				"  retries 2\n",
+				"  retry-on conn-failure empty-response 503\n",
+				"  http-reuse safe\n",
+				"  option http-restrict-req-hdr-names delete\n",
+				"  option http-buffer-request\n",
+				"  timeout queue 5000\n",
+			} {
+				Ω(p.String()).Should(ContainSubstring(line))
+			}
+		})
+		It("should set the same tuning parameters on servers and server templates", func() {
+			params := configv1alpha1.ServerParams{
+				Maxconn:      pointer.Int64(100),
+				Maxqueue:     pointer.Int64(10),
+				Backup:       true,
+				Slowstart:    &metav1.Duration{Duration: 30 * time.Second},
+				OnMarkedDown: "shutdown-sessions",
+				OnMarkedUp:   "shutdown-backup-sessions",
+				Observe: &configv1alpha1.Observe{
+					Mode:       "layer7",
+					ErrorLimit: pointer.Int64(10),
+					OnError:    "mark-down",
+				},
+				AgentCheck: &configv1alpha1.AgentCheck{
+					Port:  9999,
+					Inter: &metav1.Duration{Duration: 5 * time.Second},
+					Send:  "ping agent",
+				},
+				Source:         "10.0.0.10",
+				PoolMaxConn:    pointer.Int64(20),
+				PoolPurgeDelay: &metav1.Duration{Duration: 10 * time.Second},
+				Disabled:       true,
+			}
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: configv1alpha1.BackendSpec{
+					Servers:         []configv1alpha1.Server{{ServerParams: params, Name: "app", Address: "10.0.0.1", Port: 8080}},
+					ServerTemplates: []configv1alpha1.ServerTemplate{{ServerParams: params, Prefix: "srv", Num: 3, FQDN: "app.local", Port: 8080}},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+
+			options := `agent-check backup disabled agent-send "ping agent" agent-inter 5000 agent-port 9999 error-limit 10 maxconn 100 maxqueue 10 observe layer7 on-error mark-down on-marked-down shutdown-sessions on-marked-up shutdown-backup-sessions pool-max-conn 20 pool-purge-delay 10000 slowstart 30000 source 10.0.0.10`
+			Ω(p.String()).Should(ContainSubstring("server app 10.0.0.1:8080 " + options + "\n"))
+			Ω(p.String()).Should(ContainSubstring("server-template srv 3 app.local:8080 " + options + "\n"))
+		})
+		It("should reject http-check expect rules not setting exactly one match", func() {
+			check := &configv1alpha1.HTTPCheck{Expect: []configv1alpha1.HTTPCheckExpect{{Status: "200", String: "OK"}}}
+			_, err := check.Model()
+			Ω(err).Should(HaveOccurred())
+
+			check.Expect = []configv1alpha1.HTTPCheckExpect{{}}
+			_, err = check.Model()
+			Ω(err).Should(HaveOccurred())
+		})
+		It("should reject a capture without length and id", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						HTTPRequest: &configv1alpha1.HTTPRequestRules{
+							Capture: []configv1alpha1.HTTPCaptureRule{{Sample: "req.hdr(host)"}},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).Should(HaveOccurred())
+		})
 
 		It("should set option http-pretend-keepalive", func() {
 			backend := &configv1alpha1.Backend{
@@ -242,6 +579,32 @@ var _ = Describe("Backend", Label("type"), func() {
 			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
 			Ω(p.String()).Should(ContainSubstring("ssl alpn h2,http/1.0 ca-file /usr/local/etc/haproxy/test-ca.crt cookie 1c3c2192e2912699ccd31119b162666a inter 5000 verify required verifyhost routername.namespace.svc weight 256"))
 		})
+		It("should reject the bind only ssl parameters on servers", func() {
+			for name, ssl := range map[string]*configv1alpha1.SSL{
+				"ocsp":       {Enabled: true, OCSP: &configv1alpha1.OCSP{Update: true}},
+				"mutual-tls": {Enabled: true, MutualTLS: &configv1alpha1.MutualTLS{}},
+			} {
+				backend := &configv1alpha1.Backend{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec: configv1alpha1.BackendSpec{
+						Servers: []configv1alpha1.Server{
+							{
+								Name:         "server",
+								Port:         443,
+								Address:      "localhost",
+								ServerParams: configv1alpha1.ServerParams{SSL: ssl},
+							},
+						},
+					},
+				}
+				Ω(backend.AddToParser(p)).Should(MatchError(ContainSubstring("only supported on binds")))
+
+				backend.Name += "-refs"
+				backend.Spec.Servers = nil
+				backend.Spec.ServiceRefs = []configv1alpha1.ServiceReference{{Name: "app", ServerParams: configv1alpha1.ServerParams{SSL: ssl}}}
+				Ω(backend.AddToParser(p)).Should(MatchError(ContainSubstring("only supported on binds")))
+			}
+		})
 		It("should set option http-request redirect location", func() {
 			backend := &configv1alpha1.Backend{
 				ObjectMeta: metav1.ObjectMeta{Name: "openshift_default"},
@@ -533,5 +896,195 @@ var _ = Describe("Backend", Label("type"), func() {
 			}
 			Ω(backend.AddToParser(p)).Should(HaveOccurred())
 		})
+		It("should create servers from service endpoints", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.BackendSpec{
+					ServiceRef: &configv1alpha1.ServiceReference{
+						Name: "app",
+						Port: "http",
+						ServerParams: configv1alpha1.ServerParams{
+							Check: &configv1alpha1.Check{Enabled: true},
+						},
+						EndpointSlices: []discoveryv1.EndpointSlice{
+							{
+								AddressType: discoveryv1.AddressTypeIPv4,
+								Ports: []discoveryv1.EndpointPort{
+									{Name: pointer.String("metrics"), Port: pointer.Int32(9090)},
+									{Name: pointer.String("http"), Port: pointer.Int32(8080)},
+								},
+								Endpoints: []discoveryv1.Endpoint{
+									{
+										Addresses:  []string{"10.0.0.2"},
+										TargetRef:  &corev1.ObjectReference{Name: "app-b"},
+										Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(false), Serving: pointer.Bool(true), Terminating: pointer.Bool(true)},
+									},
+									{
+										Addresses:  []string{"10.0.0.1"},
+										TargetRef:  &corev1.ObjectReference{Name: "app-a"},
+										Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+									},
+									{
+										Addresses:  []string{"10.0.0.3"},
+										TargetRef:  &corev1.ObjectReference{Name: "app-c"},
+										Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(false)},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring("server app-a 10.0.0.1:8080 check\n"))
+			Ω(p.String()).Should(ContainSubstring("server app-b 10.0.0.2:8080 check weight 0\n"))
+			Ω(p.String()).Should(ContainSubstring("server app-c 10.0.0.3:8080 check disabled\n"))
+		})
+		It("should skip service endpoints without matching port", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.BackendSpec{
+					ServiceRef: &configv1alpha1.ServiceReference{
+						Name: "app",
+						Port: "https",
+						EndpointSlices: []discoveryv1.EndpointSlice{
+							{
+								AddressType: discoveryv1.AddressTypeIPv4,
+								Ports:       []discoveryv1.EndpointPort{{Name: pointer.String("http"), Port: pointer.Int32(8080)}},
+								Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}}},
+							},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).ShouldNot(ContainSubstring("server"))
+		})
+		It("should render weighted servers of multiple services", func() {
+			slice := func(address, pod string) []discoveryv1.EndpointSlice {
+				return []discoveryv1.EndpointSlice{
+					{
+						AddressType: discoveryv1.AddressTypeIPv4,
+						Ports:       []discoveryv1.EndpointPort{{Name: pointer.String(""), Port: pointer.Int32(8080)}},
+						Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{address}, TargetRef: &corev1.ObjectReference{Name: pod}}},
+					},
+				}
+			}
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.BackendSpec{
+					ServiceRefs: []configv1alpha1.ServiceReference{
+						{
+							Name:           "stable",
+							ServerParams:   configv1alpha1.ServerParams{Weight: pointer.Int64(90)},
+							EndpointSlices: slice("10.0.0.1", "app-a"),
+						},
+						{
+							Name:           "canary",
+							ServerParams:   configv1alpha1.ServerParams{Weight: pointer.Int64(10)},
+							EndpointSlices: slice("10.0.0.2", "app-a"),
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring("server stable-app-a 10.0.0.1:8080 weight 90\n"))
+			Ω(p.String()).Should(ContainSubstring("server canary-app-a 10.0.0.2:8080 weight 10\n"))
+		})
+		It("should set http-response and http-after-response rules", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						HTTPResponse: &configv1alpha1.HTTPResponseRules{
+							HTTPAfterResponseRules: configv1alpha1.HTTPAfterResponseRules{
+								SetVar: []configv1alpha1.HTTPSetVarRule{{Scope: "txn", Name: "status", Expression: "status"}},
+								DelHeader: []configv1alpha1.HTTPDelHeaderRule{
+									{Name: "Server"},
+									{Name: "X-Powered", Method: "beg"},
+								},
+								SetHeader: []configv1alpha1.HTTPHeaderRule{
+									{Name: "Strict-Transport-Security", Value: configv1alpha1.HTTPHeaderValue{Str: pointer.String("max-age=31536000")}},
+								},
+								ReplaceHeader: []configv1alpha1.HTTPReplaceHeaderRule{
+									{Name: "Location", Match: "^http://(.*)$", Format: "https://\\1"},
+								},
+								ReplaceValue: []configv1alpha1.HTTPReplaceHeaderRule{
+									{Name: "Cache-Control", Match: "^public$", Format: "private"},
+								},
+								SetStatus: []configv1alpha1.HTTPSetStatusRule{
+									{Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ status 404 }"}, Status: 503, Reason: "Unavailable"},
+								},
+							},
+							Deny: &configv1alpha1.Deny{
+								Rule:    configv1alpha1.Rule{ConditionType: "if", Condition: "{ status 500 }"},
+								Enabled: true,
+							},
+							DenyStatus: pointer.Int64(502),
+						},
+						HTTPAfterResponse: &configv1alpha1.HTTPAfterResponseRules{
+							AddHeader: []configv1alpha1.HTTPHeaderRule{
+								{Name: "X-Frame-Options", Value: configv1alpha1.HTTPHeaderValue{Str: pointer.String("DENY")}},
+							},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(Equal("\nbackend foo\n" +
+				"  http-response set-var(txn.status) status\n" +
+				"  http-response del-header Server\n" +
+				"  http-response del-header X-Powered -m beg\n" +
+				"  http-response set-header Strict-Transport-Security max-age=31536000\n" +
+				"  http-response replace-header Location ^http://(.*)$ https://\\1\n" +
+				"  http-response replace-value Cache-Control ^public$ private\n" +
+				"  http-response set-status 503 reason Unavailable if { status 404 }\n" +
+				"  http-response deny deny_status 502 if { status 500 }\n" +
+				"  http-after-response add-header X-Frame-Options DENY\n"))
+		})
+		It("should set http-response return and redirect", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						HTTPResponse: &configv1alpha1.HTTPResponseRules{
+							Redirect: []configv1alpha1.Redirect{
+								{
+									Rule:  configv1alpha1.Rule{ConditionType: "if", Condition: "{ status 401 }"},
+									Code:  pointer.Int64(302),
+									Type:  configv1alpha1.RedirectType{Location: true},
+									Value: "/login",
+								},
+							},
+							Return: &configv1alpha1.HTTPReturn{
+								Status:  pointer.Int64(200),
+								Content: configv1alpha1.HTTPReturnContent{Type: "text/plain", Format: "string", Value: "OK"},
+							},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring("  http-response redirect location /login code 302 if { status 401 }\n" +
+				"  http-response return status 200 content-type text/plain string \"OK\"\n"))
+		})
+		It("should limit tcp connections with content rules", func() {
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						Mode: "tcp",
+						RateLimit: &configv1alpha1.RateLimit{
+							StickTable: configv1alpha1.StickTable{Type: "ip", Size: 1000, Store: []string{"conn_cur"}},
+							Thresholds: []configv1alpha1.RateLimitThreshold{{Counter: "conn_cur", Limit: 10}},
+						},
+					},
+				},
+			}
+			Ω(backend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring("  tcp-request content track-sc0 src\n" +
+				"  tcp-request content reject if { sc_conn_cur(0) gt 10 }\n"))
+			Ω(p.String()).ShouldNot(ContainSubstring("tcp-request connection"))
+		})
 	})
 })
@@ -2,6 +2,9 @@ package v1alpha1
 
 import (
 	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/six-group/haproxy-operator/pkg/defaults"
 	"github.com/six-group/haproxy-operator/pkg/hash"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,6 +39,7 @@ type BaseSpec struct {
 	// +optional
 	HTTPRequest *HTTPRequestRules `json:"httpRequest,omitempty"`
 	// TCPRequest rules perform an action on an incoming connection depending on a layer 4 condition.
+	// The rules are evaluated in the order they are declared, after the rules of RateLimit.
 	// +optional
 	TCPRequest []TCPRequestRule `json:"tcpRequest,omitempty"`
 	// ACL (Access Control Lists) provides a flexible solution to perform
@@ -56,6 +61,16 @@ type BaseSpec struct {
 	// HTTPPretendKeepalive will keep the connection alive. It is recommended not to enable this option by default.
 	// +optional
 	HTTPPretendKeepalive *bool `json:"httpPretendKeepalive,omitempty"`
+	// RateLimit tracks the clients in a stick table and rejects their requests once a threshold is exceeded.
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// HTTPResponse rules define a set of rules which apply to the responses of the servers.
+	// +optional
+	HTTPResponse *HTTPResponseRules `json:"httpResponse,omitempty"`
+	// HTTPAfterResponse rules apply to all responses, including the ones HAProxy generates itself, e.g. on deny or
+	// errors.
+	// +optional
+	HTTPAfterResponse *HTTPAfterResponseRules `json:"httpAfterResponse,omitempty"`
 }
 
 func (b *BaseSpec) AddToParser(p parser.Parser, sectionType parser.Section, sectionName string) error {
@@ -71,12 +86,35 @@ func (b *BaseSpec) AddToParser(p parser.Parser, sectionType parser.Section, sect
 		}
 	}
 
-	for idx, rule := range b.TCPRequest {
+	// the rate limit rules are evaluated before the rules of the user
+	var tcpRequest []models.TCPRequestRule
+	var httpRequest models.HTTPRequestRules
+	if b.RateLimit != nil {
+		var err error
+		if b.Mode == models.FrontendModeTCP {
+			// backends cannot act on connections, the content rules are evaluated as soon as a backend is selected
+			ruleType := models.TCPRequestRuleTypeConnection
+			if sectionType == parser.Backends {
+				ruleType = models.TCPRequestRuleTypeContent
+			}
+			tcpRequest, err = b.RateLimit.TCPRequestRules(ruleType)
+		} else {
+			httpRequest, err = b.RateLimit.HTTPRequestRules()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range b.TCPRequest {
 		model, err := rule.Model()
 		if err != nil {
 			return err
 		}
+		tcpRequest = append(tcpRequest, model)
+	}
 
+	for idx, model := range tcpRequest {
 		data, err := configuration.SerializeTCPRequestRule(model)
 		if err != nil {
 			return err
@@ -93,16 +131,52 @@ func (b *BaseSpec) AddToParser(p parser.Parser, sectionType parser.Section, sect
 		if err != nil {
 			return err
 		}
+		httpRequest = append(httpRequest, rules...)
+	}
+
+	for idx, rule := range httpRequest {
+		if rule != nil {
+			data, err := configuration.SerializeHTTPRequestRule(*rule)
+			if err != nil {
+				return err
+			}
+			err = p.Insert(sectionType, sectionName, "http-request", data, idx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if b.HTTPResponse != nil {
+		rules, err := b.HTTPResponse.Model()
+		if err != nil {
+			return err
+		}
+		for idx, rule := range rules {
+			data, err := configuration.SerializeHTTPResponseRule(*rule)
+			if err != nil {
+				return err
+			}
+			err = p.Insert(sectionType, sectionName, "http-response", data, idx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if b.HTTPAfterResponse != nil {
+		rules, err := b.HTTPAfterResponse.Model()
+		if err != nil {
+			return err
+		}
 		for idx, rule := range rules {
-			if rule != nil {
-				data, err := configuration.SerializeHTTPRequestRule(*rule)
-				if err != nil {
-					return err
-				}
-				err = p.Insert(sectionType, sectionName, "http-request", data, idx)
-				if err != nil {
-					return err
-				}
+			data, err := configuration.SerializeHTTPAfterRule(*rule)
+			if err != nil {
+				return err
+			}
+			err = p.Insert(sectionType, sectionName, "http-after-response", data, idx)
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -110,6 +184,28 @@ func (b *BaseSpec) AddToParser(p parser.Parser, sectionType parser.Section, sect
 	return nil
 }
 
+// StickTableModel returns the stick table of the section, which is declared either by the given table or by the rate
+// limit.
+func (b *BaseSpec) StickTableModel(table *StickTable) (*models.ConfigStickTable, error) {
+	if b.RateLimit != nil {
+		if table != nil {
+			return nil, fmt.Errorf("stickTable cannot be combined with rateLimit, which declares the stick table of the section")
+		}
+		table = &b.RateLimit.StickTable
+	}
+
+	if table == nil {
+		return nil, nil
+	}
+
+	model, err := table.Model()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model, nil
+}
+
 type HashType struct {
 	// +kubebuilder:validation:Enum=map-based;consistent
 	// +optional
@@ -204,6 +300,8 @@ func (a *ACL) Model() (models.ACL, error) {
 	return model, model.Validate(strfmt.Default)
 }
 
+// FilePath returns the path of the file with the values. The name contains a hash of the values, so any change of the
+// values renames the file and reloads HAProxy.
 func (a *ACL) FilePath() string {
 	return fmt.Sprintf("/usr/local/etc/haproxy/acl-%s-%s.txt", a.Name, hash.GetMD5Hash(strings.Join(a.Values, "\n")))
 }
@@ -282,6 +380,24 @@ type Bind struct {
 	AcceptProxy *bool `json:"acceptProxy,omitempty"`
 }
 
+// OCSPUpdate returns true if HAProxy updates the OCSP responses of the certificates of the bind.
+func (b *Bind) OCSPUpdate() bool {
+	return b.SSL != nil && b.SSL.OCSP != nil && b.SSL.OCSP.Update
+}
+
+// CertificateListPath returns the path of the crt-list of the bind, or an empty string if the bind has none. With
+// OCSP updates, the certificate of a bind without SSLCertificateList is loaded through a crt-list of its own.
+func (b *Bind) CertificateListPath() string {
+	if b.SSLCertificateList != nil {
+		return b.SSLCertificateList.FilePath()
+	}
+	if b.OCSPUpdate() && b.SSL.Certificate != nil {
+		return strings.TrimSuffix(b.SSL.Certificate.FilePath(), filepath.Ext(b.SSL.Certificate.FilePath())) + ".crt-list"
+	}
+
+	return ""
+}
+
 func (b *Bind) Model() (models.Bind, error) {
 	model := models.Bind{
 		Address:      b.Address,
@@ -298,11 +414,9 @@ func (b *Bind) Model() (models.Bind, error) {
 		model.Ssl = b.SSL.Enabled
 		model.Verify = b.SSL.Verify
 
-		if b.SSLCertificateList != nil {
-			model.CrtList = b.SSLCertificateList.FilePath()
-		}
+		model.CrtList = b.CertificateListPath()
 
-		if b.SSL.Certificate != nil {
+		if b.SSL.Certificate != nil && !b.OCSPUpdate() {
 			model.SslCertificate = b.SSL.Certificate.FilePath()
 		}
 
@@ -313,6 +427,26 @@ func (b *Bind) Model() (models.Bind, error) {
 		if b.SSL.MinVersion != "" {
 			model.SslMinVer = b.SSL.MinVersion
 		}
+
+		if mtls := b.SSL.MutualTLS; mtls != nil {
+			model.Verify = mtls.GetVerify()
+
+			if mtls.CAVerifyCertificate != nil {
+				model.CaVerifyFile = mtls.CAVerifyCertificate.FilePath()
+			}
+			if b.SSL.CACertificate == nil && mtls.CAVerifyCertificate == nil {
+				return model, fmt.Errorf("bind %s: mutual TLS requires a CA certificate", b.Name)
+			}
+
+			if mtls.CRL != nil {
+				model.CrlFile = mtls.CRL.FilePath()
+			}
+
+			if mtls.IgnoreErrors != nil {
+				model.CaIgnoreErr = strings.Join(mtls.IgnoreErrors.CA, ",")
+				model.CrtIgnoreErr = strings.Join(mtls.IgnoreErrors.Certificate, ",")
+			}
+		}
 	}
 
 	return model, model.Validate(strfmt.Default)
@@ -357,41 +491,92 @@ type ServerParams struct {
 	// Cookie sets the cookie value assigned to the server.
 	// +optional
 	Cookie bool `json:"cookie,omitempty"`
+	// Maxconn is the maximum number of concurrent connections sent to the server. Further connections are queued.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Maxconn *int64 `json:"maxconn,omitempty"`
+	// Maxqueue is the maximum number of connections waiting in the queue of the server.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Maxqueue *int64 `json:"maxqueue,omitempty"`
+	// Backup only sends traffic to the server when all other servers are unavailable.
+	// +optional
+	Backup bool `json:"backup,omitempty"`
+	// Slowstart progressively increases the weight of the server over this duration after it comes back up.
+	// +optional
+	Slowstart *metav1.Duration `json:"slowstart,omitempty"`
+	// OnMarkedDown closes the sessions of the server once it is marked down.
+	// +kubebuilder:validation:Enum=shutdown-sessions
+	// +optional
+	OnMarkedDown string `json:"onMarkedDown,omitempty"`
+	// OnMarkedUp closes the sessions of the backup servers once the server is marked up.
+	// +kubebuilder:validation:Enum=shutdown-backup-sessions
+	// +optional
+	OnMarkedUp string `json:"onMarkedUp,omitempty"`
+	// Observe enables the health checks based on the observed traffic.
+	// +optional
+	Observe *Observe `json:"observe,omitempty"`
+	// AgentCheck periodically asks an agent for the state and weight of the server.
+	// +optional
+	AgentCheck *AgentCheck `json:"agentCheck,omitempty"`
+	// Source sets the source address of the connections to the server.
+	// +optional
+	Source string `json:"source,omitempty"`
+	// PoolMaxConn is the maximum number of idle connections kept for reuse. -1 means unlimited.
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	PoolMaxConn *int64 `json:"poolMaxConn,omitempty"`
+	// PoolPurgeDelay sets the interval at which the idle connections are purged.
+	// +optional
+	PoolPurgeDelay *metav1.Duration `json:"poolPurgeDelay,omitempty"`
+	// Disabled puts the server into maintenance mode.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
 }
 
-type ServerTemplate struct {
-	ServerParams `json:",inline"`
-	// Prefix for the server names to be built.
-	// +kubebuilder:validation:Pattern=^[^\s]+$
-	Prefix string `json:"prefix"`
-	// NumMin is the min number of servers as server name suffixes this template initializes.
+type Observe struct {
+	// Mode selects the traffic analyzed, either the connections or the HTTP responses.
+	// +kubebuilder:validation:Enum=layer4;layer7
+	Mode string `json:"mode"`
+	// ErrorLimit is the number of consecutive errors triggering the OnError action.
+	// +kubebuilder:validation:Minimum=1
 	// +optional
-	NumMin *int64 `json:"numMin,omitempty"`
-	// Num is the max number of servers as server name suffixes this template initializes.
-	Num int64 `json:"num"`
-	// FQDN for all the servers this template initializes.
-	FQDN string `json:"fqdn"`
-	// Port
+	ErrorLimit *int64 `json:"errorLimit,omitempty"`
+	// OnError is the action taken when the error limit is reached.
+	// +kubebuilder:validation:Enum=fastinter;fail-check;sudden-death;mark-down
+	// +optional
+	OnError string `json:"onError,omitempty"`
+}
+
+type AgentCheck struct {
+	// Port of the agent.
 	// +kubebuilder:validation:Maximum=65535
 	// +kubebuilder:validation:Minimum=1
 	Port int64 `json:"port"`
+	// Address of the agent. Defaults to the address of the server.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	// +optional
+	Address string `json:"address,omitempty"`
+	// Inter sets the interval between two consecutive agent checks. Defaults to 2s.
+	// +optional
+	Inter *metav1.Duration `json:"inter,omitempty"`
+	// Send is the string sent to the agent on connection.
+	// +optional
+	Send string `json:"send,omitempty"`
 }
 
-func (s *ServerTemplate) Model() (models.ServerTemplate, error) {
-	model := models.ServerTemplate{
-		ServerParams: models.ServerParams{
-			Weight:   s.Weight,
-			InitAddr: s.InitAddr,
-		},
-		Fqdn:   s.FQDN,
-		Port:   pointer.Int64(s.Port),
-		Prefix: s.Prefix,
-	}
-
-	if s.NumMin != nil {
-		model.NumOrRange = fmt.Sprintf("%d-%d", *s.NumMin, s.Num)
-	} else {
-		model.NumOrRange = strconv.Itoa(int(s.Num))
+// Model returns the parameters shared by servers and server templates.
+func (s *ServerParams) Model() (models.ServerParams, error) {
+	model := models.ServerParams{
+		Weight:       s.Weight,
+		InitAddr:     s.InitAddr,
+		Verifyhost:   s.VerifyHost,
+		Maxconn:      s.Maxconn,
+		Maxqueue:     s.Maxqueue,
+		OnMarkedDown: s.OnMarkedDown,
+		OnMarkedUp:   s.OnMarkedUp,
+		Source:       s.Source,
+		PoolMaxConn:  s.PoolMaxConn,
 	}
 
 	if pointer.BoolDeref(s.SendProxy, false) {
@@ -403,7 +588,7 @@ func (s *ServerTemplate) Model() (models.ServerTemplate, error) {
 			model.SendProxy = models.ServerParamsSendProxyEnabled
 		} else if !s.SendProxyV2.V1 && s.SendProxyV2.V2 != nil && s.SendProxyV2.V2.Enabled && !s.SendProxyV2.V2SSL && !s.SendProxyV2.V2SSLCN {
 			model.SendProxyV2 = models.ServerParamsSendProxyV2Enabled
-			setProxyProtocolV2ServerTemplate(&model, s)
+			model.ProxyV2Options = s.SendProxyV2.V2.Options.list()
 		} else if !s.SendProxyV2.V1 && s.SendProxyV2.V2 == nil && s.SendProxyV2.V2SSL && !s.SendProxyV2.V2SSLCN {
 			model.SendProxyV2Ssl = models.ServerParamsSendProxyV2SslEnabled
 		} else if !s.SendProxyV2.V1 && s.SendProxyV2.V2 == nil && !s.SendProxyV2.V2SSL && s.SendProxyV2.V2SSLCN {
@@ -413,6 +598,14 @@ func (s *ServerTemplate) Model() (models.ServerTemplate, error) {
 		}
 	}
 
+	if s.SSL != nil && s.SSL.OCSP != nil {
+		return model, fmt.Errorf("ocsp is only supported on binds")
+	}
+
+	if s.SSL != nil && s.SSL.MutualTLS != nil {
+		return model, fmt.Errorf("mutualTLS is only supported on binds")
+	}
+
 	if s.SSL != nil && s.SSL.Enabled {
 		model.Ssl = models.ServerParamsSslEnabled
 
@@ -435,6 +628,11 @@ func (s *ServerTemplate) Model() (models.ServerTemplate, error) {
 		}
 	}
 
+	if s.SSL != nil && s.SSL.Verify == "required" {
+		model.Verify = s.SSL.Verify
+		model.Alpn = strings.Join(s.SSL.Alpn, ",")
+	}
+
 	if s.Check != nil && s.Check.Enabled {
 		model.Check = models.ServerParamsCheckEnabled
 
@@ -442,46 +640,98 @@ func (s *ServerTemplate) Model() (models.ServerTemplate, error) {
 			model.Inter = pointer.Int64(s.Check.Inter.Milliseconds())
 		}
 
+		if s.Check.Fastinter != nil {
+			model.Fastinter = pointer.Int64(s.Check.Fastinter.Milliseconds())
+		}
+
+		if s.Check.Downinter != nil {
+			model.Downinter = pointer.Int64(s.Check.Downinter.Milliseconds())
+		}
+
 		model.Rise = s.Check.Rise
 		model.Fall = s.Check.Fall
+		model.HealthCheckPort = s.Check.Port
+		model.HealthCheckAddress = s.Check.Address
 	}
 
 	if s.Resolvers != nil {
 		model.Resolvers = s.Resolvers.Name
 	}
 
-	return model, model.Validate(strfmt.Default)
-}
+	if s.Backup {
+		model.Backup = models.ServerParamsBackupEnabled
+	}
 
-func setProxyProtocolV2ServerTemplate(model *models.ServerTemplate, s *ServerTemplate) {
-	if s.SendProxyV2.V2.Options != nil {
-		var v2Options []string
-		if s.SendProxyV2.V2.Options.Ssl {
-			v2Options = append(v2Options, "ssl")
-		}
-		if s.SendProxyV2.V2.Options.CertCn {
-			v2Options = append(v2Options, "cert-cn")
-		}
-		if s.SendProxyV2.V2.Options.SslCipher {
-			v2Options = append(v2Options, "ssl-cipher")
-		}
-		if s.SendProxyV2.V2.Options.CertSig {
-			v2Options = append(v2Options, "cert-sig")
-		}
-		if s.SendProxyV2.V2.Options.CertKey {
-			v2Options = append(v2Options, "cert-key")
-		}
-		if s.SendProxyV2.V2.Options.Authority {
-			v2Options = append(v2Options, "authority")
-		}
-		if s.SendProxyV2.V2.Options.Crc32c {
-			v2Options = append(v2Options, "crc32c")
-		}
-		if s.SendProxyV2.V2.Options.UniqueID {
-			v2Options = append(v2Options, "unique-id")
+	if s.Slowstart != nil {
+		model.Slowstart = pointer.Int64(s.Slowstart.Milliseconds())
+	}
+
+	if s.Observe != nil {
+		model.Observe = s.Observe.Mode
+		model.ErrorLimit = pointer.Int64Deref(s.Observe.ErrorLimit, 0)
+		model.OnError = s.Observe.OnError
+	}
+
+	if s.AgentCheck != nil {
+		model.AgentCheck = models.ServerParamsAgentCheckEnabled
+		model.AgentPort = pointer.Int64(s.AgentCheck.Port)
+		model.AgentAddr = s.AgentCheck.Address
+		model.AgentSend = checkArg(s.AgentCheck.Send)
+
+		if s.AgentCheck.Inter != nil {
+			model.AgentInter = pointer.Int64(s.AgentCheck.Inter.Milliseconds())
 		}
-		model.ProxyV2Options = v2Options
 	}
+
+	if s.PoolPurgeDelay != nil {
+		model.PoolPurgeDelay = pointer.Int64(s.PoolPurgeDelay.Milliseconds())
+	}
+
+	if s.Disabled {
+		model.Maintenance = models.ServerParamsMaintenanceEnabled
+	}
+
+	return model, nil
+}
+
+type ServerTemplate struct {
+	ServerParams `json:",inline"`
+	// Prefix for the server names to be built.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	Prefix string `json:"prefix"`
+	// NumMin is the min number of servers as server name suffixes this template initializes.
+	// +optional
+	NumMin *int64 `json:"numMin,omitempty"`
+	// Num is the max number of servers as server name suffixes this template initializes.
+	Num int64 `json:"num"`
+	// FQDN for all the servers this template initializes.
+	FQDN string `json:"fqdn"`
+	// Port
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:Minimum=1
+	Port int64 `json:"port"`
+}
+
+func (s *ServerTemplate) Model() (models.ServerTemplate, error) {
+	params, err := s.ServerParams.Model()
+	if err != nil {
+		return models.ServerTemplate{}, err
+	}
+
+	model := models.ServerTemplate{
+		ServerParams: params,
+		Fqdn:         s.FQDN,
+		Port:         pointer.Int64(s.Port),
+		Prefix:       s.Prefix,
+	}
+
+	if s.NumMin != nil {
+		model.NumOrRange = fmt.Sprintf("%d-%d", *s.NumMin, s.Num)
+	} else {
+		model.NumOrRange = strconv.Itoa(int(s.Num))
+	}
+
+	return model, model.Validate(strfmt.Default)
 }
 
 type Server struct {
@@ -498,109 +748,128 @@ type Server struct {
 }
 
 func (s *Server) Model() (models.Server, error) {
+	params, err := s.ServerParams.Model()
+	if err != nil {
+		return models.Server{}, err
+	}
+
 	model := models.Server{
-		ServerParams: models.ServerParams{
-			Weight:     s.Weight,
-			InitAddr:   s.InitAddr,
-			Verifyhost: s.VerifyHost,
-		},
-		Name:    s.Name,
-		Address: s.Address,
-		Port:    pointer.Int64(s.Port),
+		ServerParams: params,
+		Name:         s.Name,
+		Address:      s.Address,
+		Port:         pointer.Int64(s.Port),
 	}
 
 	if s.Cookie {
 		model.ServerParams.Cookie = hash.GetMD5Hash(model.Address + ":" + strconv.Itoa(int(*model.Port)))
 	}
 
-	if pointer.BoolDeref(s.SendProxy, false) {
-		model.SendProxy = models.ServerParamsSendProxyEnabled
-	}
+	return model, model.Validate(strfmt.Default)
+}
 
-	if s.SendProxyV2 != nil {
-		if s.SendProxyV2.V1 && s.SendProxyV2.V2 == nil && !s.SendProxyV2.V2SSL && !s.SendProxyV2.V2SSLCN {
-			model.SendProxy = models.ServerParamsSendProxyEnabled
-		} else if !s.SendProxyV2.V1 && s.SendProxyV2.V2 != nil && s.SendProxyV2.V2.Enabled && !s.SendProxyV2.V2SSL && !s.SendProxyV2.V2SSLCN {
-			model.SendProxyV2 = models.ServerParamsSendProxyV2Enabled
-			setProxyProtocolV2Server(&model, s)
-		} else if !s.SendProxyV2.V1 && s.SendProxyV2.V2 == nil && s.SendProxyV2.V2SSL && !s.SendProxyV2.V2SSLCN {
-			model.SendProxyV2Ssl = models.ServerParamsSendProxyV2SslEnabled
-		} else if !s.SendProxyV2.V1 && s.SendProxyV2.V2 == nil && !s.SendProxyV2.V2SSL && s.SendProxyV2.V2SSLCN {
-			model.SendProxyV2SslCn = models.ServerParamsSendProxyV2SslCnEnabled
-		} else {
-			return model, fmt.Errorf("you can only select one proxy protocol")
-		}
+// ServiceReference discovers the servers of a backend from the EndpointSlices of a Service. Each endpoint becomes a
+// server with the ServerParams, named after the Pod or the address of the endpoint and listening on the target port
+// of the Service port.
+type ServiceReference struct {
+	ServerParams `json:",inline"`
+	// Name of the Service whose EndpointSlices are used to discover the servers.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	Name string `json:"name"`
+	// Namespace of the Service, defaults to the namespace of the object. Services in other namespaces are only
+	// resolved for the Backends the gateway controller generates for routes, after checking the ReferenceGrants of
+	// the routes.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Port is the name of the Service port. It can be omitted if the Service exposes a single unnamed port.
+	// +optional
+	Port string `json:"port,omitempty"`
+	// EndpointSlices of the referenced Service, resolved by the controller before the configuration is rendered.
+	EndpointSlices []discoveryv1.EndpointSlice `json:"-"`
+}
+
+// Model returns one server per discovered endpoint. Ready endpoints are active, serving but terminating endpoints
+// are drained and all other endpoints are put into maintenance.
+func (s *ServiceReference) Model() ([]models.Server, error) {
+	return s.model("")
+}
+
+func (s *ServiceReference) model(prefix string) ([]models.Server, error) {
+	// reject invalid parameters before any endpoint is discovered
+	if _, err := s.ServerParams.Model(); err != nil {
+		return nil, err
 	}
 
-	if s.SSL != nil && s.SSL.Enabled {
-		model.Ssl = models.ServerParamsSslEnabled
+	var servers []models.Server
 
-		if s.SSL.Certificate != nil {
-			model.SslCertificate = s.SSL.Certificate.FilePath()
+	names := map[string]bool{}
+	for _, slice := range s.EndpointSlices {
+		if slice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
 		}
 
-		if s.SSL.CACertificate == nil {
-			model.Verify = "none"
-		} else {
-			model.SslCafile = s.SSL.CACertificate.FilePath()
+		var port *int32
+		for _, p := range slice.Ports {
+			if pointer.StringDeref(p.Name, "") == s.Port {
+				port = p.Port
+				break
+			}
 		}
-
-		if s.SSL.MinVersion != "" {
-			model.SslMinVer = s.SSL.MinVersion
+		if port == nil {
+			continue
 		}
 
-		if s.SSL.SNI != "" {
-			model.Sni = s.SSL.SNI
-		}
-	}
+		for _, endpoint := range slice.Endpoints {
+			if len(endpoint.Addresses) == 0 {
+				continue
+			}
 
-	if s.Check != nil && s.Check.Enabled {
-		model.Check = models.ServerParamsCheckEnabled
+			address := endpoint.Addresses[0]
+			name := address
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Name != "" {
+				name = endpoint.TargetRef.Name
+			}
+			if names[name] {
+				continue
+			}
+			names[name] = true
 
-		if s.Check.Inter != nil {
-			model.Inter = pointer.Int64(s.Check.Inter.Milliseconds())
-		}
+			if slice.AddressType == discoveryv1.AddressTypeIPv6 {
+				address = fmt.Sprintf("[%s]", address)
+			}
 
-		model.Rise = s.Check.Rise
-		model.Fall = s.Check.Fall
-	}
+			server := Server{
+				ServerParams: s.ServerParams,
+				Name:         prefix + name,
+				Address:      address,
+				Port:         int64(*port),
+			}
 
-	if s.Resolvers != nil {
-		model.Resolvers = s.Resolvers.Name
-	}
+			model, err := server.Model()
+			if err != nil {
+				return nil, err
+			}
 
-	return model, model.Validate(strfmt.Default)
-}
+			ready := pointer.BoolDeref(endpoint.Conditions.Ready, true)
+			serving := pointer.BoolDeref(endpoint.Conditions.Serving, ready)
+			terminating := pointer.BoolDeref(endpoint.Conditions.Terminating, false)
 
-func setProxyProtocolV2Server(model *models.Server, s *Server) {
-	if s.SendProxyV2.V2.Options != nil {
-		var v2Options []string
-		if s.SendProxyV2.V2.Options.Ssl {
-			v2Options = append(v2Options, "ssl")
-		}
-		if s.SendProxyV2.V2.Options.CertCn {
-			v2Options = append(v2Options, "cert-cn")
-		}
-		if s.SendProxyV2.V2.Options.SslCipher {
-			v2Options = append(v2Options, "ssl-cipher")
-		}
-		if s.SendProxyV2.V2.Options.CertSig {
-			v2Options = append(v2Options, "cert-sig")
-		}
-		if s.SendProxyV2.V2.Options.CertKey {
-			v2Options = append(v2Options, "cert-key")
-		}
-		if s.SendProxyV2.V2.Options.Authority {
-			v2Options = append(v2Options, "authority")
-		}
-		if s.SendProxyV2.V2.Options.Crc32c {
-			v2Options = append(v2Options, "crc32c")
-		}
-		if s.SendProxyV2.V2.Options.UniqueID {
-			v2Options = append(v2Options, "unique-id")
+			switch {
+			case ready && !terminating:
+			case serving && terminating:
+				model.Weight = pointer.Int64(0)
+			default:
+				model.Maintenance = models.ServerParamsMaintenanceEnabled
+			}
+
+			servers = append(servers, model)
 		}
-		model.ProxyV2Options = v2Options
 	}
+
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Name < servers[j].Name
+	})
+
+	return servers, nil
 }
 
 type Check struct {
@@ -618,14 +887,220 @@ type Check struct {
 	// This value defaults to 3 if unspecified.
 	// +optional
 	Fall *int64 `json:"fall,omitempty"`
+	// Fastinter sets the interval between two consecutive health checks while the server is transitioning between
+	// up and down. Defaults to Inter.
+	// +optional
+	Fastinter *metav1.Duration `json:"fastinter,omitempty"`
+	// Downinter sets the interval between two consecutive health checks while the server is down. Defaults to Inter.
+	// +optional
+	Downinter *metav1.Duration `json:"downinter,omitempty"`
+	// Port sends the health checks to this port instead of the port of the server.
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Port *int64 `json:"port,omitempty"`
+	// Address sends the health checks to this address instead of the address of the server.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	// +optional
+	Address string `json:"address,omitempty"`
+}
+
+// HTTPCheck replaces the connect checks of the servers by HTTP requests. The servers must enable their checks.
+type HTTPCheck struct {
+	// Connect configures the connection of the checks.
+	// +optional
+	Connect *HTTPCheckConnect `json:"connect,omitempty"`
+	// Method of the request.
+	// +kubebuilder:validation:Enum=GET;HEAD;OPTIONS;POST;PUT;PATCH
+	// +kubebuilder:default=GET
+	// +optional
+	Method string `json:"method,omitempty"`
+	// URI of the request.
+	// +kubebuilder:default=/
+	// +optional
+	URI string `json:"uri,omitempty"`
+	// Version of the HTTP protocol of the request, e.g. HTTP/1.1.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Host sets the Host header of the request.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Headers adds header fields to the request.
+	// +optional
+	Headers []HTTPCheckHeader `json:"headers,omitempty"`
+	// Body of the request.
+	// +optional
+	Body string `json:"body,omitempty"`
+	// Expect rules are evaluated in order and must all match the response. Without any rule, responses with status
+	// 2xx and 3xx are considered valid.
+	// +optional
+	Expect []HTTPCheckExpect `json:"expect,omitempty"`
+}
+
+type HTTPCheckConnect struct {
+	// Port to connect to instead of the port of the server.
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Port *int64 `json:"port,omitempty"`
+	// Address to connect to instead of the address of the server.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	// +optional
+	Address string `json:"address,omitempty"`
+	// SSL enables SSL for the checks.
+	// +optional
+	SSL bool `json:"ssl,omitempty"`
+	// SNI sets the server name sent in the SSL handshake.
+	// +optional
+	SNI string `json:"sni,omitempty"`
+	// ALPN negotiates the protocols in the SSL handshake, e.g. h2 and http/1.1.
+	// +optional
+	ALPN []string `json:"alpn,omitempty"`
+}
+
+type HTTPCheckHeader struct {
+	// Name of the header field.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	Name string `json:"name"`
+	// Value of the header field.
+	Value string `json:"value"`
+}
+
+// HTTPCheckExpect matches the response of a check. Exactly one of Status, StatusRegex, String, Regex and Header must
+// be set.
+type HTTPCheckExpect struct {
+	// Status matches the status code against a comma-separated list of codes and ranges, e.g. 200-399.
+	// +kubebuilder:validation:Pattern="^[0-9,-]+$"
+	// +optional
+	Status string `json:"status,omitempty"`
+	// StatusRegex matches the status code against a regular expression.
+	// +optional
+	StatusRegex string `json:"statusRegex,omitempty"`
+	// String must be contained in the body of the response.
+	// +optional
+	String string `json:"string,omitempty"`
+	// Regex matches the body of the response against a regular expression.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+	// Header must be present in the response.
+	// +optional
+	Header *HTTPCheckExpectHeader `json:"header,omitempty"`
+	// Inverted inverts the match.
+	// +optional
+	Inverted bool `json:"inverted,omitempty"`
+}
+
+type HTTPCheckExpectHeader struct {
+	// Name of the header field.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	Name string `json:"name"`
+	// Value of the header field. If not set, only the presence of the header is checked.
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+func (e *HTTPCheckExpect) matchAndPattern() (string, string, error) {
+	var matches []string
+	var pattern string
+	if e.Status != "" {
+		matches, pattern = append(matches, "status"), e.Status
+	}
+	if e.StatusRegex != "" {
+		matches, pattern = append(matches, "rstatus"), checkArg(e.StatusRegex)
+	}
+	if e.String != "" {
+		matches, pattern = append(matches, "string"), checkArg(e.String)
+	}
+	if e.Regex != "" {
+		matches, pattern = append(matches, "rstring"), checkArg(e.Regex)
+	}
+	if e.Header != nil {
+		matches, pattern = append(matches, "hdr"), "name "+checkArg(e.Header.Name)
+		if e.Header.Value != "" {
+			pattern += " value " + checkArg(e.Header.Value)
+		}
+	}
+
+	if len(matches) != 1 {
+		return "", "", fmt.Errorf("http-check expect must set exactly one of status, statusRegex, string, regex and header")
+	}
+
+	return matches[0], pattern, nil
+}
+
+func (h *HTTPCheck) Model() (models.HTTPChecks, error) {
+	model := models.HTTPChecks{}
+
+	if h.Connect != nil {
+		model = append(model, &models.HTTPCheck{
+			Type: models.HTTPCheckTypeConnect,
+			Port: h.Connect.Port,
+			Addr: h.Connect.Address,
+			Ssl:  h.Connect.SSL,
+			Sni:  h.Connect.SNI,
+			Alpn: strings.Join(h.Connect.ALPN, ","),
+		})
+	}
+
+	send := &models.HTTPCheck{
+		Type:    models.HTTPCheckTypeSend,
+		Method:  h.Method,
+		URI:     h.URI,
+		Version: h.Version,
+		Body:    checkArg(h.Body),
+	}
+	headers := h.Headers
+	if h.Host != "" {
+		headers = append([]HTTPCheckHeader{{Name: "Host", Value: h.Host}}, headers...)
+	}
+	for _, header := range headers {
+		send.CheckHeaders = append(send.CheckHeaders, &models.ReturnHeader{
+			Name: pointer.String(header.Name),
+			Fmt:  pointer.String(checkArg(header.Value)),
+		})
+	}
+	model = append(model, send)
+
+	for _, expect := range h.Expect {
+		match, pattern, err := expect.matchAndPattern()
+		if err != nil {
+			return nil, err
+		}
+
+		model = append(model, &models.HTTPCheck{
+			Type:            models.HTTPCheckTypeExpect,
+			Match:           match,
+			Pattern:         pattern,
+			ExclamationMark: expect.Inverted,
+		})
+	}
+
+	for i := 0; i < len(model); i++ {
+		model[i].Index = pointer.Int64(int64(i))
+	}
+
+	return model, model.Validate(strfmt.Default)
+}
+
+// checkArg quotes arguments of http-check rules containing whitespace.
+func checkArg(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return fmt.Sprintf("\"%s\"", strings.ReplaceAll(value, "\"", "\\\""))
+	}
+
+	return value
+}
+
+// RetryOnCondition is a failure triggering a retry.
+// +kubebuilder:validation:Enum=none;conn-failure;empty-response;junk-response;response-timeout;"0rtt-rejected";"404";"408";"425";"500";"501";"502";"503";"504";all-retryable-errors;all
+type RetryOnCondition string
+
+type Balance struct {
+	// Algorithm is the algorithm used to select a server when doing load balancing. This only applies when no persistence information is available, or when a connection is redispatched to another server.
+	// +kubebuilder:validation:Enum=roundrobin;static-rr;leastconn;first;source;uri;hdr;random;rdp-cookie
+	Algorithm string `json:"algorithm"`
 }
 
-type Balance struct {
-	// Algorithm is the algorithm used to select a server when doing load balancing. This only applies when no persistence information is available, or when a connection is redispatched to another server.
-	// +kubebuilder:validation:Enum=roundrobin;static-rr;leastconn;first;source;uri;hdr;random;rdp-cookie
-	Algorithm string `json:"algorithm"`
-}
-
 func (b *Balance) Model() (models.Balance, error) {
 	model := models.Balance{
 		Algorithm: pointer.String(b.Algorithm),
@@ -634,6 +1109,205 @@ func (b *Balance) Model() (models.Balance, error) {
 	return model, model.Validate(strfmt.Default)
 }
 
+type StickTable struct {
+	// Type of the keys stored in the table.
+	// +kubebuilder:validation:Enum=ip;ipv6;integer;string;binary
+	Type string `json:"type"`
+	// Size is the maximum number of entries the table can store.
+	// +kubebuilder:validation:Minimum=1
+	Size int64 `json:"size"`
+	// Expire removes entries which have not been updated for the given duration.
+	// +optional
+	Expire *metav1.Duration `json:"expire,omitempty"`
+	// KeyLength is the maximum length of string and binary keys.
+	// +optional
+	KeyLength *int64 `json:"keyLength,omitempty"`
+	// NoPurge prevents the removal of the oldest entries when the table is full.
+	// +optional
+	NoPurge *bool `json:"noPurge,omitempty"`
+	// Store lists the data types stored per entry, e.g. gpc0, conn_cur or http_req_rate(10s).
+	// +optional
+	Store []string `json:"store,omitempty"`
+	// Peers is the name of the Peers object the entries of the table are replicated with.
+	// +optional
+	Peers string `json:"peers,omitempty"`
+}
+
+func (s *StickTable) Model() (models.ConfigStickTable, error) {
+	model := models.ConfigStickTable{
+		Type:    s.Type,
+		Size:    pointer.Int64(s.Size),
+		Keylen:  s.KeyLength,
+		Nopurge: pointer.BoolDeref(s.NoPurge, false),
+		Peers:   s.Peers,
+		Store:   strings.Join(s.Store, ","),
+	}
+
+	if s.Expire != nil {
+		model.Expire = pointer.Int64(s.Expire.Milliseconds())
+	}
+
+	return model, model.Validate(strfmt.Default)
+}
+
+// stores returns true if the data type is stored in the table.
+func (s *StickTable) stores(dataType string) bool {
+	for _, store := range s.Store {
+		if store == dataType || strings.HasPrefix(store, dataType+"(") {
+			return true
+		}
+	}
+
+	return false
+}
+
+type RateLimit struct {
+	// StickTable declares the stick table of the section which stores the tracked keys. The counters of the thresholds
+	// must be stored, e.g. http_req_rate(10s).
+	StickTable StickTable `json:"stickTable"`
+	// Key is the sample expression identifying a client, e.g. src or req.hdr(x-api-key).
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	// +kubebuilder:default=src
+	// +optional
+	Key string `json:"key,omitempty"`
+	// StickCounter is the number of the sticky counter which tracks the key (track-sc0, track-sc1 or track-sc2).
+	// Frontends and backends limiting the same requests must use different sticky counters.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=2
+	// +optional
+	StickCounter int64 `json:"stickCounter,omitempty"`
+	// Thresholds define the limits of the counters. The action is taken as soon as one of them is exceeded.
+	// +kubebuilder:validation:MinItems=1
+	Thresholds []RateLimitThreshold `json:"thresholds"`
+	// Action taken when a threshold is exceeded. In mode tcp, deny rejects the connection and tarpit is not supported.
+	// +kubebuilder:validation:Enum=deny;tarpit;silent-drop
+	// +kubebuilder:default=deny
+	// +optional
+	Action string `json:"action,omitempty"`
+	// DenyStatus is the HTTP status code returned by deny and tarpit. Defaults to 429.
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	DenyStatus *int64 `json:"denyStatus,omitempty"`
+}
+
+type RateLimitThreshold struct {
+	// Counter is the stick table data type compared against the limit.
+	// +kubebuilder:validation:Enum=conn_cnt;conn_cur;conn_rate;sess_cnt;sess_rate;http_req_cnt;http_req_rate;http_err_cnt;http_err_rate;bytes_in_rate;bytes_out_rate
+	Counter string `json:"counter"`
+	// Limit is the highest accepted value of the counter.
+	// +kubebuilder:validation:Minimum=0
+	Limit int64 `json:"limit"`
+}
+
+func (r *RateLimit) HTTPRequestRules() (models.HTTPRequestRules, error) {
+	condition, err := r.condition()
+	if err != nil {
+		return nil, err
+	}
+
+	track := &models.HTTPRequestRule{
+		Type:  fmt.Sprintf("track-sc%d", r.StickCounter),
+		Index: pointer.Int64(0),
+	}
+	switch r.StickCounter {
+	case 0:
+		track.TrackSc0Key = r.key()
+	case 1:
+		track.TrackSc1Key = r.key()
+	case 2:
+		track.TrackSc2Key = r.key()
+	}
+
+	limit := &models.HTTPRequestRule{
+		Type:     r.action(),
+		Index:    pointer.Int64(1),
+		Cond:     "if",
+		CondTest: condition,
+	}
+	if limit.Type != "silent-drop" {
+		limit.DenyStatus = pointer.Int64(pointer.Int64Deref(r.DenyStatus, 429))
+	}
+
+	rules := models.HTTPRequestRules{track, limit}
+
+	return rules, rules.Validate(strfmt.Default)
+}
+
+// TCPRequestRules returns the tcp-request rules of the given type, connection in frontends and content in backends.
+func (r *RateLimit) TCPRequestRules(ruleType string) ([]models.TCPRequestRule, error) {
+	condition, err := r.condition()
+	if err != nil {
+		return nil, err
+	}
+
+	limit := models.TCPRequestRule{
+		Type:     ruleType,
+		Index:    pointer.Int64(1),
+		Cond:     "if",
+		CondTest: condition,
+	}
+	switch r.action() {
+	case "deny":
+		limit.Action = models.TCPRequestRuleActionReject
+	case "silent-drop":
+		limit.Action = models.TCPRequestRuleActionSilentDashDrop
+	default:
+		return nil, fmt.Errorf("rate limit action %s is not supported in mode tcp", r.action())
+	}
+
+	rules := []models.TCPRequestRule{
+		{
+			Type:     ruleType,
+			Index:    pointer.Int64(0),
+			Action:   fmt.Sprintf("track-sc%d", r.StickCounter),
+			TrackKey: r.key(),
+		},
+		limit,
+	}
+
+	for _, rule := range rules {
+		if err := rule.Validate(strfmt.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
+
+// condition returns an anonymous ACL matching if any threshold is exceeded.
+func (r *RateLimit) condition() (string, error) {
+	if len(r.Thresholds) == 0 {
+		return "", fmt.Errorf("rate limit requires at least one threshold")
+	}
+
+	var conditions []string
+	for _, threshold := range r.Thresholds {
+		if !r.StickTable.stores(threshold.Counter) {
+			return "", fmt.Errorf("rate limit counter %s is not stored in the stick table", threshold.Counter)
+		}
+		conditions = append(conditions, fmt.Sprintf("{ sc_%s(%d) gt %d }", threshold.Counter, r.StickCounter, threshold.Limit))
+	}
+
+	return strings.Join(conditions, " || "), nil
+}
+
+func (r *RateLimit) key() string {
+	if r.Key == "" {
+		return "src"
+	}
+
+	return r.Key
+}
+
+func (r *RateLimit) action() string {
+	if r.Action == "" {
+		return "deny"
+	}
+
+	return r.Action
+}
+
 type SSL struct {
 	// Enabled enables SSL deciphering on connections instantiated from this listener. A
 	// certificate is necessary. All contents in the buffers will
@@ -672,18 +1346,244 @@ type SSL struct {
 	// list as supported on top of ALPN.
 	// +optional
 	Alpn []string `json:"alpn,omitempty"`
+	// OCSP configures OCSP stapling for the certificates of a bind. It is rejected on servers.
+	// +optional
+	OCSP *OCSP `json:"ocsp,omitempty"`
+	// MutualTLS configures the verification of client certificates on a bind. It is rejected on servers.
+	// +optional
+	MutualTLS *MutualTLS `json:"mutualTLS,omitempty"`
+}
+
+// MutualTLS is the client certificate policy of a bind. The verification depth cannot be limited: HAProxy has no
+// bind option for the depth of the client certificate chain and does not expose the depth of a verified chain to
+// ACLs, ssl_c_ca_err_depth only reports the depth of the first verification error. IgnoreErrors distinguishes the
+// errors of the client certificate at depth 0 from the ones of the CAs instead.
+type MutualTLS struct {
+	// Verify requests a client certificate and overrides the Verify of the SSL configuration. With 'required', the
+	// handshake is aborted if the client sends no valid certificate. With 'optional', such requests are handled by
+	// the Action.
+	// +kubebuilder:validation:Enum=optional;required
+	// +kubebuilder:default=required
+	// +optional
+	Verify string `json:"verify,omitempty"`
+	// CAVerifyCertificate holds CA certificates which are used to verify the client certificates like the
+	// CACertificate of the SSL configuration, but are not sent to the clients in the list of acceptable CAs.
+	// +optional
+	CAVerifyCertificate *SSLCertificate `json:"caVerifyCertificate,omitempty"`
+	// CRL holds the PEM encoded certificate revocation lists of the CAs, which are checked for all certificates in
+	// the chain of the client.
+	// +optional
+	CRL *SSLCertificate `json:"crl,omitempty"`
+	// IgnoreErrors lists the verification errors which do not abort the handshake, by their depth in the chain.
+	// +optional
+	IgnoreErrors *VerifyIgnoreErrors `json:"ignoreErrors,omitempty"`
+	// ForwardHeaders set request headers with the details of the client certificate for the backends. The headers
+	// are removed from all requests without a client certificate, so that clients cannot forge them.
+	// +optional
+	ForwardHeaders []ClientCertificateHeader `json:"forwardHeaders,omitempty"`
+	// Action is applied to the requests received on the bind without a valid client certificate, which only reach
+	// HAProxy if Verify is 'optional' or errors are ignored.
+	// +optional
+	Action *MutualTLSAction `json:"action,omitempty"`
+}
+
+// VerifyIgnoreErrors lists the verification errors to ignore by their depth in the certificate chain, either as
+// numeric OpenSSL error IDs, e.g. 10 for an expired certificate, or as 'all'.
+type VerifyIgnoreErrors struct {
+	// CA lists the errors ignored for the CA certificates of the chain at depth > 0 (ca-ignore-err).
+	// +optional
+	CA []string `json:"ca,omitempty"`
+	// Certificate lists the errors ignored for the client certificate at depth 0 (crt-ignore-err).
+	// +optional
+	Certificate []string `json:"certificate,omitempty"`
+}
+
+// ClientCertificateField is a detail of a client certificate which can be forwarded in a header.
+// +kubebuilder:validation:Enum=SubjectDN;IssuerDN;Serial;SHA1;NotBefore;NotAfter;VerifyResult;Certificate
+type ClientCertificateField string
+
+const (
+	ClientCertificateFieldSubjectDN    ClientCertificateField = "SubjectDN"
+	ClientCertificateFieldIssuerDN     ClientCertificateField = "IssuerDN"
+	ClientCertificateFieldSerial       ClientCertificateField = "Serial"
+	ClientCertificateFieldSHA1         ClientCertificateField = "SHA1"
+	ClientCertificateFieldNotBefore    ClientCertificateField = "NotBefore"
+	ClientCertificateFieldNotAfter     ClientCertificateField = "NotAfter"
+	ClientCertificateFieldVerifyResult ClientCertificateField = "VerifyResult"
+	ClientCertificateFieldCertificate  ClientCertificateField = "Certificate"
+)
+
+// clientCertificateFieldFormats maps the fields to the log formats of the header values.
+var clientCertificateFieldFormats = map[ClientCertificateField]string{
+	ClientCertificateFieldSubjectDN:    "%[ssl_c_s_dn]",
+	ClientCertificateFieldIssuerDN:     "%[ssl_c_i_dn]",
+	ClientCertificateFieldSerial:       "%[ssl_c_serial,hex]",
+	ClientCertificateFieldSHA1:         "%[ssl_c_sha1,hex]",
+	ClientCertificateFieldNotBefore:    "%[ssl_c_notbefore]",
+	ClientCertificateFieldNotAfter:     "%[ssl_c_notafter]",
+	ClientCertificateFieldVerifyResult: "%[ssl_c_verify]",
+	ClientCertificateFieldCertificate:  "%[ssl_c_der,base64]",
+}
+
+type ClientCertificateHeader struct {
+	// Name of the request header.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	Name string `json:"name"`
+	// Field of the client certificate set as header value. SubjectDN and IssuerDN are the distinguished names,
+	// Serial and SHA1 are hex encoded, NotBefore and NotAfter are formatted as YYMMDDhhmmss[Z], VerifyResult is the
+	// OpenSSL error ID of the verification and Certificate is the base64 encoded DER certificate.
+	Field ClientCertificateField `json:"field"`
+}
+
+// MutualTLSAction is the action applied to the requests without a valid client certificate. Exactly one of Deny and
+// Redirect must be set.
+type MutualTLSAction struct {
+	// Deny rejects the requests.
+	// +optional
+	Deny *MutualTLSDeny `json:"deny,omitempty"`
+	// Redirect redirects the requests, e.g. to a page explaining how to obtain a client certificate.
+	// +optional
+	Redirect *MutualTLSRedirect `json:"redirect,omitempty"`
+}
+
+type MutualTLSDeny struct {
+	// Status is the HTTP status code of the response. Defaults to 403.
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	Status *int64 `json:"status,omitempty"`
+}
+
+type MutualTLSRedirect struct {
+	// Location is the URL the requests are redirected to.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	Location string `json:"location"`
+	// Code indicates which type of HTTP redirection is desired. Defaults to 302.
+	// +kubebuilder:validation:Enum=301;302;303;307;308
+	// +optional
+	Code *int64 `json:"code,omitempty"`
+}
+
+// GetVerify returns the verify mode of the bind, which defaults to 'required'.
+func (m *MutualTLS) GetVerify() string {
+	if m.Verify == "" {
+		return "required"
+	}
+
+	return m.Verify
+}
+
+// actionRule returns the rule applying the action to the requests received on the named bind without a valid client
+// certificate, or nil if no action is set.
+func (m *MutualTLS) actionRule(bind string) (*models.HTTPRequestRule, error) {
+	if m.Action == nil {
+		return nil, nil
+	}
+
+	// no certificate or a certificate with ignored verification errors
+	invalid := fmt.Sprintf("{ so_name %s } !{ ssl_c_used } || { so_name %s } !{ ssl_c_verify 0 }", bind, bind)
+
+	switch {
+	case m.Action.Deny != nil && m.Action.Redirect == nil:
+		return &models.HTTPRequestRule{
+			Index:      pointer.Int64(0),
+			Type:       "deny",
+			DenyStatus: m.Action.Deny.Status,
+			Cond:       "if",
+			CondTest:   invalid,
+		}, nil
+	case m.Action.Redirect != nil && m.Action.Deny == nil:
+		return &models.HTTPRequestRule{
+			Index:      pointer.Int64(0),
+			Type:       "redirect",
+			RedirType:  models.HTTPRequestRuleRedirTypeLocation,
+			RedirValue: m.Action.Redirect.Location,
+			RedirCode:  m.Action.Redirect.Code,
+			Cond:       "if",
+			CondTest:   invalid,
+		}, nil
+	default:
+		return nil, fmt.Errorf("mutual TLS action must set either deny or redirect")
+	}
+}
+
+// headerRule returns the rule setting the header to the field of the client certificate for the requests received
+// on the named bind with a client certificate.
+func (h *ClientCertificateHeader) headerRule(bind string) (*models.HTTPRequestRule, error) {
+	format, ok := clientCertificateFieldFormats[h.Field]
+	if !ok {
+		return nil, fmt.Errorf("unknown client certificate field %s", h.Field)
+	}
+
+	return &models.HTTPRequestRule{
+		Index:     pointer.Int64(0),
+		Type:      "set-header",
+		HdrName:   h.Name,
+		HdrFormat: format,
+		Cond:      "if",
+		CondTest:  fmt.Sprintf("{ so_name %s } { ssl_c_used }", bind),
+	}, nil
+}
+
+// OCSP configures how the OCSP responses stapled to the TLS handshakes of a bind are obtained.
+type OCSP struct {
+	// Update lets HAProxy fetch and refresh the OCSP responses of the bind certificates from the responder in their
+	// AIA extension (ocsp-update), which requires HAProxy 2.8 or newer and the issuer certificate in the certificate
+	// chain. As the option is only supported in crt-list lines, the Certificate of the bind is loaded through its
+	// crt-list, or through a crt-list named after the certificate if SSLCertificateList is not set.
+	// +optional
+	Update bool `json:"update,omitempty"`
+	// Response selects a DER encoded OCSP response for the Certificate of the bind in a Secret, e.g. for HAProxy
+	// images without ocsp-update. It is written as <certificate>.ocsp next to the certificate and replaced through
+	// the runtime API on changes if runtime sync is enabled.
+	// +optional
+	Response *corev1.SecretKeySelector `json:"response,omitempty"`
 }
 
 type SSLCertificate struct {
 	Name      string                    `json:"name"`
 	Value     *string                   `json:"value,omitempty"`
 	ValueFrom []SSLCertificateValueFrom `json:"valueFrom,omitempty"`
+	// CertificateRef references a whole Secret of type kubernetes.io/tls or a cert-manager Certificate. The
+	// certificate, the private key and the CA of the Secret are bundled into one PEM file, which is updated through
+	// the runtime API on renewal if runtime sync is enabled.
+	// +optional
+	CertificateRef *CertificateRef `json:"certificateRef,omitempty"`
 }
 
+// FilePath returns the path of the certificate file. Certificates referenced by a CertificateRef are stored as .pem
+// files to tell them apart from the certificates which require a reload on changes.
 func (s *SSLCertificate) FilePath() string {
+	if s.CertificateRef != nil {
+		return fmt.Sprintf("/usr/local/etc/haproxy/%s.pem", strings.TrimSuffix(strings.TrimSuffix(s.Name, ".crt"), ".pem"))
+	}
+
 	return fmt.Sprintf("/usr/local/etc/haproxy/%s.crt", strings.TrimSuffix(s.Name, ".crt"))
 }
 
+// OCSPFilePath returns the path of the OCSP response of the certificate, which HAProxy loads together with the
+// certificate.
+func (s *SSLCertificate) OCSPFilePath() string {
+	return s.FilePath() + ".ocsp"
+}
+
+// CertificateRef references the Secret holding a certificate and its private key.
+type CertificateRef struct {
+	// Kind of the referenced object, either a Secret of type kubernetes.io/tls or a cert-manager Certificate whose
+	// Secret is used.
+	// +kubebuilder:validation:Enum=Secret;Certificate
+	// +kubebuilder:default=Secret
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name of the referenced object in the namespace of the instance.
+	Name string `json:"name"`
+}
+
+const (
+	CertificateRefKindSecret      = "Secret"
+	CertificateRefKindCertificate = "Certificate"
+)
+
 type SSLCertificateValueFrom struct {
 	// ConfigMapKeyRef selects a key of a ConfigMap
 	// +optional
@@ -714,61 +1614,588 @@ type CertificateList struct {
 	LabelSelector *metav1.LabelSelector `json:"selector,omitempty"`
 }
 
-func (r *CertificateList) FilePath() string {
-	return fmt.Sprintf("/usr/local/etc/haproxy/%s.map", strings.TrimSuffix(r.Name, ".map"))
+func (r *CertificateList) FilePath() string {
+	return fmt.Sprintf("/usr/local/etc/haproxy/%s.map", strings.TrimSuffix(r.Name, ".map"))
+}
+
+// HTTPRequestRules are evaluated in the order of Rules. The fields per action are kept for compatibility and are
+// evaluated before Rules, grouped by action in the following order: auth, capture, set-log-level, set-var, unset-var,
+// set-src, normalize-uri, del-header, set-header, set-path, add-header, replace-header, replace-path, set-query,
+// set-method, set-uri, replace-uri, deny, tarpit, silent-drop, redirect, cache-use, use-service and return.
+type HTTPRequestRules struct {
+	// Rules are evaluated in the order they are declared. Each rule sets exactly one action.
+	// +optional
+	Rules []HTTPRequestRule `json:"rules,omitempty"`
+	// SetHeader sets HTTP header fields
+	SetHeader []HTTPHeaderRule `json:"setHeader,omitempty"`
+	// SetPath sets request path
+	SetPath []HTTPPathRule `json:"setPath,omitempty"`
+	// AddHeader appends HTTP header fields
+	AddHeader []HTTPHeaderRule `json:"addHeader,omitempty"`
+	// DelHeader removes HTTP header fields.
+	// +optional
+	DelHeader []HTTPDelHeaderRule `json:"delHeader,omitempty"`
+	// ReplaceHeader replaces the matches of a regular expression in the values of HTTP header fields.
+	// +optional
+	ReplaceHeader []HTTPReplaceHeaderRule `json:"replaceHeader,omitempty"`
+	// ReplacePath replaces the matches of a regular expression in the path.
+	// +optional
+	ReplacePath []HTTPReplaceRule `json:"replacePath,omitempty"`
+	// ReplaceURI replaces the matches of a regular expression in the URI.
+	// +optional
+	ReplaceURI []HTTPReplaceRule `json:"replaceURI,omitempty"`
+	// SetQuery replaces the query string, excluding the question mark.
+	// +optional
+	SetQuery []HTTPFormatRule `json:"setQuery,omitempty"`
+	// SetURI rewrites the URI.
+	// +optional
+	SetURI []HTTPFormatRule `json:"setURI,omitempty"`
+	// SetMethod rewrites the request method.
+	// +optional
+	SetMethod []HTTPFormatRule `json:"setMethod,omitempty"`
+	// SetVar sets variables to the result of sample expressions.
+	// +optional
+	SetVar []HTTPSetVarRule `json:"setVar,omitempty"`
+	// UnsetVar removes variables.
+	// +optional
+	UnsetVar []HTTPUnsetVarRule `json:"unsetVar,omitempty"`
+	// SetLogLevel changes the log level of the current request.
+	// +optional
+	SetLogLevel []HTTPSetLogLevelRule `json:"setLogLevel,omitempty"`
+	// Capture captures a sample expression into the logs.
+	// +optional
+	Capture []HTTPCaptureRule `json:"capture,omitempty"`
+	// SetSrc replaces the source address by the result of a sample expression.
+	// +optional
+	SetSrc []HTTPExpressionRule `json:"setSrc,omitempty"`
+	// NormalizeURI normalizes the URI.
+	// +optional
+	NormalizeURI []HTTPNormalizeURIRule `json:"normalizeURI,omitempty"`
+	// Redirect performs an HTTP redirection based on a redirect rule.
+	// +optional
+	Redirect []Redirect `json:"redirect,omitempty"`
+	// Deny stops the evaluation of the rules and immediately rejects the request and emits an HTTP 403 error.
+	// Optionally the status code specified as an argument to deny_status.
+	// +optional
+	Deny *Deny `json:"deny,omitempty"`
+	// DenyRules stops the evaluation of the rules and immediately rejects the request and emits an HTTP 403 error, or
+	// the status code of the rule. The rules are rendered after Deny.
+	// +optional
+	DenyRules []HTTPDenyRule `json:"denyRules,omitempty"`
+	// DenyStatus is the HTTP status code of Deny and of the deny and tarpit rules, including the ones of Rules, not
+	// specifying a status code.
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	DenyStatus *int64 `json:"denyStatus,omitempty"`
+	// Tarpit stops the evaluation of the rules and keeps the connection open without any response until the tarpit
+	// timeout expires, then emits an HTTP 500 error, or the status code of the rule.
+	// +optional
+	Tarpit []HTTPDenyRule `json:"tarpit,omitempty"`
+	// SilentDrop stops the evaluation of the rules and closes the connection without notifying the client.
+	// +optional
+	SilentDrop []Rule `json:"silentDrop,omitempty"`
+	// UseService passes the request to an HAProxy service like 'prometheus-exporter'.
+	// +optional
+	UseService []HTTPNameRule `json:"useService,omitempty"`
+	// CacheUse looks up the response in a cache, which must be declared in the additional parameters of the instance.
+	// +optional
+	CacheUse []HTTPNameRule `json:"cacheUse,omitempty"`
+	// Return stops the evaluation of the rules and immediately returns a response.
+	Return *HTTPReturn `json:"return,omitempty"`
+	// Auth requests basic authentication from clients which are not authenticated by a userlist. The auth rules are
+	// evaluated before all other rules.
+	// +optional
+	Auth []HTTPAuthRule `json:"auth,omitempty"`
+}
+
+func (h *HTTPRequestRules) Model() (models.HTTPRequestRules, error) {
+	model := models.HTTPRequestRules{}
+
+	for _, rule := range append(h.groupedRules(), h.rules()...) {
+		ruleModel, err := rule.Model()
+		if err != nil {
+			return models.HTTPRequestRules{}, err
+		}
+		model = append(model, ruleModel)
+	}
+
+	for i := 0; i < len(model); i++ {
+		model[i].Index = pointer.Int64(int64(i))
+	}
+
+	return model, validateHTTPRequestRules(model)
+}
+
+// groupedRules converts the action fields into ordered rules.
+func (h *HTTPRequestRules) groupedRules() []HTTPRequestRule {
+	var rules []HTTPRequestRule
+
+	for i := range h.Auth {
+		rules = append(rules, HTTPRequestRule{Auth: &h.Auth[i]})
+	}
+	for i := range h.Capture {
+		rules = append(rules, HTTPRequestRule{Capture: &h.Capture[i]})
+	}
+	for i := range h.SetLogLevel {
+		rules = append(rules, HTTPRequestRule{SetLogLevel: &h.SetLogLevel[i]})
+	}
+	for i := range h.SetVar {
+		rules = append(rules, HTTPRequestRule{SetVar: &h.SetVar[i]})
+	}
+	for i := range h.UnsetVar {
+		rules = append(rules, HTTPRequestRule{UnsetVar: &h.UnsetVar[i]})
+	}
+	for i := range h.SetSrc {
+		rules = append(rules, HTTPRequestRule{SetSrc: &h.SetSrc[i]})
+	}
+	for i := range h.NormalizeURI {
+		rules = append(rules, HTTPRequestRule{NormalizeURI: &h.NormalizeURI[i]})
+	}
+	for i := range h.DelHeader {
+		rules = append(rules, HTTPRequestRule{DelHeader: &h.DelHeader[i]})
+	}
+	for i := range h.SetHeader {
+		rules = append(rules, HTTPRequestRule{SetHeader: &h.SetHeader[i]})
+	}
+	for i := range h.SetPath {
+		rules = append(rules, HTTPRequestRule{SetPath: &h.SetPath[i]})
+	}
+	for i := range h.AddHeader {
+		rules = append(rules, HTTPRequestRule{AddHeader: &h.AddHeader[i]})
+	}
+	for i := range h.ReplaceHeader {
+		rules = append(rules, HTTPRequestRule{ReplaceHeader: &h.ReplaceHeader[i]})
+	}
+	for i := range h.ReplacePath {
+		rules = append(rules, HTTPRequestRule{ReplacePath: &h.ReplacePath[i]})
+	}
+	for i := range h.SetQuery {
+		rules = append(rules, HTTPRequestRule{SetQuery: &h.SetQuery[i]})
+	}
+	for i := range h.SetMethod {
+		rules = append(rules, HTTPRequestRule{SetMethod: &h.SetMethod[i]})
+	}
+	for i := range h.SetURI {
+		rules = append(rules, HTTPRequestRule{SetURI: &h.SetURI[i]})
+	}
+	for i := range h.ReplaceURI {
+		rules = append(rules, HTTPRequestRule{ReplaceURI: &h.ReplaceURI[i]})
+	}
+	if h.Deny != nil && h.Deny.Enabled {
+		rules = append(rules, HTTPRequestRule{Deny: &HTTPDenyRule{Rule: h.Deny.Rule, Status: h.DenyStatus}})
+	}
+	for _, deny := range h.DenyRules {
+		rules = append(rules, HTTPRequestRule{Deny: &HTTPDenyRule{Rule: deny.Rule, Status: deny.status(h.DenyStatus)}})
+	}
+	for _, tarpit := range h.Tarpit {
+		rules = append(rules, HTTPRequestRule{Tarpit: &HTTPDenyRule{Rule: tarpit.Rule, Status: tarpit.status(h.DenyStatus)}})
+	}
+	for i := range h.SilentDrop {
+		rules = append(rules, HTTPRequestRule{SilentDrop: &h.SilentDrop[i]})
+	}
+	for i := range h.Redirect {
+		rules = append(rules, HTTPRequestRule{Redirect: &h.Redirect[i]})
+	}
+	for i := range h.CacheUse {
+		rules = append(rules, HTTPRequestRule{CacheUse: &h.CacheUse[i]})
+	}
+	for i := range h.UseService {
+		rules = append(rules, HTTPRequestRule{UseService: &h.UseService[i]})
+	}
+	if h.Return != nil {
+		rules = append(rules, HTTPRequestRule{Return: &HTTPReturnRule{HTTPReturn: *h.Return}})
+	}
+
+	return rules
+}
+
+// rules returns Rules with DenyStatus applied to the deny and tarpit rules not specifying a status code.
+func (h *HTTPRequestRules) rules() []HTTPRequestRule {
+	rules := make([]HTTPRequestRule, 0, len(h.Rules))
+	for _, rule := range h.Rules {
+		if rule.Deny != nil {
+			rule.Deny = &HTTPDenyRule{Rule: rule.Deny.Rule, Status: rule.Deny.status(h.DenyStatus)}
+		}
+		if rule.Tarpit != nil {
+			rule.Tarpit = &HTTPDenyRule{Rule: rule.Tarpit.Rule, Status: rule.Tarpit.status(h.DenyStatus)}
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// HTTPRequestRule is a single http-request rule. Exactly one action must be set.
+type HTTPRequestRule struct {
+	// SetHeader sets an HTTP header field.
+	// +optional
+	SetHeader *HTTPHeaderRule `json:"setHeader,omitempty"`
+	// AddHeader appends an HTTP header field.
+	// +optional
+	AddHeader *HTTPHeaderRule `json:"addHeader,omitempty"`
+	// DelHeader removes HTTP header fields.
+	// +optional
+	DelHeader *HTTPDelHeaderRule `json:"delHeader,omitempty"`
+	// ReplaceHeader replaces the matches of a regular expression in the values of HTTP header fields.
+	// +optional
+	ReplaceHeader *HTTPReplaceHeaderRule `json:"replaceHeader,omitempty"`
+	// SetPath sets the request path.
+	// +optional
+	SetPath *HTTPPathRule `json:"setPath,omitempty"`
+	// ReplacePath replaces the matches of a regular expression in the path.
+	// +optional
+	ReplacePath *HTTPReplaceRule `json:"replacePath,omitempty"`
+	// ReplaceURI replaces the matches of a regular expression in the URI.
+	// +optional
+	ReplaceURI *HTTPReplaceRule `json:"replaceURI,omitempty"`
+	// SetQuery replaces the query string, excluding the question mark.
+	// +optional
+	SetQuery *HTTPFormatRule `json:"setQuery,omitempty"`
+	// SetURI rewrites the URI.
+	// +optional
+	SetURI *HTTPFormatRule `json:"setURI,omitempty"`
+	// SetMethod rewrites the request method.
+	// +optional
+	SetMethod *HTTPFormatRule `json:"setMethod,omitempty"`
+	// SetVar sets a variable to the result of a sample expression.
+	// +optional
+	SetVar *HTTPSetVarRule `json:"setVar,omitempty"`
+	// UnsetVar removes a variable.
+	// +optional
+	UnsetVar *HTTPUnsetVarRule `json:"unsetVar,omitempty"`
+	// SetLogLevel changes the log level of the current request.
+	// +optional
+	SetLogLevel *HTTPSetLogLevelRule `json:"setLogLevel,omitempty"`
+	// Capture captures a sample expression into the logs.
+	// +optional
+	Capture *HTTPCaptureRule `json:"capture,omitempty"`
+	// SetSrc replaces the source address by the result of a sample expression.
+	// +optional
+	SetSrc *HTTPExpressionRule `json:"setSrc,omitempty"`
+	// NormalizeURI normalizes the URI.
+	// +optional
+	NormalizeURI *HTTPNormalizeURIRule `json:"normalizeURI,omitempty"`
+	// Redirect performs an HTTP redirection.
+	// +optional
+	Redirect *Redirect `json:"redirect,omitempty"`
+	// Deny rejects the request with an HTTP 403 error, or the status code of the rule.
+	// +optional
+	Deny *HTTPDenyRule `json:"deny,omitempty"`
+	// Tarpit keeps the connection open until the tarpit timeout expires, then emits an HTTP 500 error, or the status
+	// code of the rule.
+	// +optional
+	Tarpit *HTTPDenyRule `json:"tarpit,omitempty"`
+	// SilentDrop closes the connection without notifying the client.
+	// +optional
+	SilentDrop *Rule `json:"silentDrop,omitempty"`
+	// UseService passes the request to an HAProxy service like 'prometheus-exporter'.
+	// +optional
+	UseService *HTTPNameRule `json:"useService,omitempty"`
+	// CacheUse looks up the response in a cache, which must be declared in the additional parameters of the instance.
+	// +optional
+	CacheUse *HTTPNameRule `json:"cacheUse,omitempty"`
+	// Return immediately returns a response.
+	// +optional
+	Return *HTTPReturnRule `json:"return,omitempty"`
+	// Auth requests basic authentication from clients which are not authenticated by a userlist.
+	// +optional
+	Auth *HTTPAuthRule `json:"auth,omitempty"`
+}
+
+func (r *HTTPRequestRule) Model() (*models.HTTPRequestRule, error) {
+	if count := countActions(r); count != 1 {
+		return nil, fmt.Errorf("http-request rule must set exactly one action, found %d", count)
+	}
+
+	switch {
+	case r.SetHeader != nil:
+		return &models.HTTPRequestRule{
+			Type:      "set-header",
+			HdrName:   r.SetHeader.Name,
+			HdrFormat: r.SetHeader.Value.String(),
+			Cond:      r.SetHeader.ConditionType,
+			CondTest:  r.SetHeader.Condition,
+		}, nil
+	case r.AddHeader != nil:
+		return &models.HTTPRequestRule{
+			Type:      "add-header",
+			HdrName:   r.AddHeader.Name,
+			HdrFormat: r.AddHeader.Value.String(),
+			Cond:      r.AddHeader.ConditionType,
+			CondTest:  r.AddHeader.Condition,
+		}, nil
+	case r.DelHeader != nil:
+		return &models.HTTPRequestRule{
+			Type:      "del-header",
+			HdrName:   r.DelHeader.Name,
+			HdrMethod: r.DelHeader.Method,
+			Cond:      r.DelHeader.ConditionType,
+			CondTest:  r.DelHeader.Condition,
+		}, nil
+	case r.ReplaceHeader != nil:
+		return &models.HTTPRequestRule{
+			Type:      "replace-header",
+			HdrName:   r.ReplaceHeader.Name,
+			HdrMatch:  r.ReplaceHeader.Match,
+			HdrFormat: r.ReplaceHeader.Format,
+			Cond:      r.ReplaceHeader.ConditionType,
+			CondTest:  r.ReplaceHeader.Condition,
+		}, nil
+	case r.SetPath != nil:
+		return &models.HTTPRequestRule{
+			Type:     "set-path",
+			PathFmt:  r.SetPath.Value,
+			Cond:     r.SetPath.ConditionType,
+			CondTest: r.SetPath.Condition,
+		}, nil
+	case r.ReplacePath != nil:
+		return &models.HTTPRequestRule{
+			Type:      "replace-path",
+			PathMatch: r.ReplacePath.Match,
+			PathFmt:   r.ReplacePath.Format,
+			Cond:      r.ReplacePath.ConditionType,
+			CondTest:  r.ReplacePath.Condition,
+		}, nil
+	case r.ReplaceURI != nil:
+		return &models.HTTPRequestRule{
+			Type:     "replace-uri",
+			URIMatch: r.ReplaceURI.Match,
+			URIFmt:   r.ReplaceURI.Format,
+			Cond:     r.ReplaceURI.ConditionType,
+			CondTest: r.ReplaceURI.Condition,
+		}, nil
+	// set-query and set-uri are serialized from the header format
+	case r.SetQuery != nil:
+		return &models.HTTPRequestRule{
+			Type:      "set-query",
+			HdrFormat: r.SetQuery.Format,
+			Cond:      r.SetQuery.ConditionType,
+			CondTest:  r.SetQuery.Condition,
+		}, nil
+	case r.SetURI != nil:
+		return &models.HTTPRequestRule{
+			Type:      "set-uri",
+			HdrFormat: r.SetURI.Format,
+			Cond:      r.SetURI.ConditionType,
+			CondTest:  r.SetURI.Condition,
+		}, nil
+	case r.SetMethod != nil:
+		return &models.HTTPRequestRule{
+			Type:      "set-method",
+			MethodFmt: r.SetMethod.Format,
+			Cond:      r.SetMethod.ConditionType,
+			CondTest:  r.SetMethod.Condition,
+		}, nil
+	case r.SetVar != nil:
+		return &models.HTTPRequestRule{
+			Type:     "set-var",
+			VarScope: r.SetVar.Scope,
+			VarName:  r.SetVar.Name,
+			VarExpr:  r.SetVar.Expression,
+			Cond:     r.SetVar.ConditionType,
+			CondTest: r.SetVar.Condition,
+		}, nil
+	case r.UnsetVar != nil:
+		return &models.HTTPRequestRule{
+			Type:     "unset-var",
+			VarScope: r.UnsetVar.Scope,
+			VarName:  r.UnsetVar.Name,
+			Cond:     r.UnsetVar.ConditionType,
+			CondTest: r.UnsetVar.Condition,
+		}, nil
+	case r.SetLogLevel != nil:
+		return &models.HTTPRequestRule{
+			Type:     "set-log-level",
+			LogLevel: r.SetLogLevel.Level,
+			Cond:     r.SetLogLevel.ConditionType,
+			CondTest: r.SetLogLevel.Condition,
+		}, nil
+	case r.Capture != nil:
+		return &models.HTTPRequestRule{
+			Type:          "capture",
+			CaptureSample: r.Capture.Sample,
+			CaptureLen:    pointer.Int64Deref(r.Capture.Length, 0),
+			CaptureID:     r.Capture.ID,
+			Cond:          r.Capture.ConditionType,
+			CondTest:      r.Capture.Condition,
+		}, nil
+	case r.SetSrc != nil:
+		return &models.HTTPRequestRule{
+			Type:     "set-src",
+			Expr:     r.SetSrc.Expression,
+			Cond:     r.SetSrc.ConditionType,
+			CondTest: r.SetSrc.Condition,
+		}, nil
+	case r.NormalizeURI != nil:
+		return &models.HTTPRequestRule{
+			Type:             "normalize-uri",
+			Normalizer:       r.NormalizeURI.Normalizer,
+			NormalizerFull:   r.NormalizeURI.Full,
+			NormalizerStrict: r.NormalizeURI.Strict,
+			Cond:             r.NormalizeURI.ConditionType,
+			CondTest:         r.NormalizeURI.Condition,
+		}, nil
+	case r.Redirect != nil:
+		redirType, redirOption, err := r.Redirect.typeAndOption()
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.HTTPRequestRule{
+			Type:        "redirect",
+			RedirCode:   r.Redirect.Code,
+			RedirValue:  r.Redirect.Value,
+			RedirType:   redirType,
+			RedirOption: redirOption,
+			Cond:        r.Redirect.ConditionType,
+			CondTest:    r.Redirect.Condition,
+		}, nil
+	case r.Deny != nil:
+		return &models.HTTPRequestRule{
+			Type:       "deny",
+			DenyStatus: r.Deny.Status,
+			Cond:       r.Deny.ConditionType,
+			CondTest:   r.Deny.Condition,
+		}, nil
+	case r.Tarpit != nil:
+		return &models.HTTPRequestRule{
+			Type:       "tarpit",
+			DenyStatus: r.Tarpit.Status,
+			Cond:       r.Tarpit.ConditionType,
+			CondTest:   r.Tarpit.Condition,
+		}, nil
+	case r.SilentDrop != nil:
+		return &models.HTTPRequestRule{
+			Type:     "silent-drop",
+			Cond:     r.SilentDrop.ConditionType,
+			CondTest: r.SilentDrop.Condition,
+		}, nil
+	case r.UseService != nil:
+		return &models.HTTPRequestRule{
+			Type:        "use-service",
+			ServiceName: r.UseService.Name,
+			Cond:        r.UseService.ConditionType,
+			CondTest:    r.UseService.Condition,
+		}, nil
+	case r.CacheUse != nil:
+		return &models.HTTPRequestRule{
+			Type:      "cache-use",
+			CacheName: r.CacheUse.Name,
+			Cond:      r.CacheUse.ConditionType,
+			CondTest:  r.CacheUse.Condition,
+		}, nil
+	case r.Return != nil:
+		return &models.HTTPRequestRule{
+			Type:                "return",
+			ReturnStatusCode:    r.Return.Status,
+			ReturnContentType:   pointer.String(r.Return.Content.Type),
+			ReturnContentFormat: r.Return.Content.Format,
+			ReturnContent:       r.Return.Content.value(),
+			Cond:                r.Return.ConditionType,
+			CondTest:            r.Return.Condition,
+		}, nil
+	default:
+		cond, condTest := r.Auth.condition()
+		return &models.HTTPRequestRule{
+			Type:      "auth",
+			AuthRealm: r.Auth.Realm,
+			Cond:      cond,
+			CondTest:  condTest,
+		}, nil
+	}
+}
+
+// countActions returns the number of optional action fields set in a rule.
+func countActions(rule interface{}) int {
+	count := 0
+	value := reflect.Indirect(reflect.ValueOf(rule))
+	for i := 0; i < value.NumField(); i++ {
+		if field := value.Field(i); field.Kind() == reflect.Pointer && !field.IsNil() {
+			count++
+		}
+	}
+
+	return count
 }
 
-type HTTPRequestRules struct {
+// validateHTTPRequestRules validates the rules against a copy in which the normalizer percent-to-uppercase is replaced
+// by its misspelling in the enum of client-native.
+func validateHTTPRequestRules(rules models.HTTPRequestRules) error {
+	validation := models.HTTPRequestRules{}
+	for _, rule := range rules {
+		if rule.Normalizer == "percent-to-uppercase" {
+			copied := *rule
+			copied.Normalizer = models.HTTPRequestRuleNormalizerPercentDashToDashUpercase
+			rule = &copied
+		}
+		validation = append(validation, rule)
+	}
+
+	return validation.Validate(strfmt.Default)
+}
+
+type HTTPAfterResponseRules struct {
+	// SetVar sets variables to the result of sample expressions.
+	// +optional
+	SetVar []HTTPSetVarRule `json:"setVar,omitempty"`
+	// DelHeader removes HTTP header fields.
+	// +optional
+	DelHeader []HTTPDelHeaderRule `json:"delHeader,omitempty"`
 	// SetHeader sets HTTP header fields
+	// +optional
 	SetHeader []HTTPHeaderRule `json:"setHeader,omitempty"`
-	// SetPath sets request path
-	SetPath []HTTPPathRule `json:"setPath,omitempty"`
 	// AddHeader appends HTTP header fields
+	// +optional
 	AddHeader []HTTPHeaderRule `json:"addHeader,omitempty"`
-	// Redirect performs an HTTP redirection based on a redirect rule.
+	// ReplaceHeader replaces the matches of a regular expression in the values of HTTP header fields.
 	// +optional
-	Redirect []Redirect `json:"redirect,omitempty"`
-	// Deny stops the evaluation of the rules and immediately rejects the request and emits an HTTP 403 error.
-	// Optionally the status code specified as an argument to deny_status.
+	ReplaceHeader []HTTPReplaceHeaderRule `json:"replaceHeader,omitempty"`
+	// ReplaceValue replaces the matches of a regular expression in each comma-delimited value of HTTP header fields.
 	// +optional
-	Deny *Deny `json:"deny,omitempty"`
-	// DenyStatus is the HTTP status code.
-	// +kubebuilder:validation:Minimum=200
-	// +kubebuilder:validation:Maximum=599
+	ReplaceValue []HTTPReplaceHeaderRule `json:"replaceValue,omitempty"`
+	// SetStatus replaces the status code of the response.
 	// +optional
-	DenyStatus *int64 `json:"denyStatus,omitempty"`
-	// Return stops the evaluation of the rules and immediately returns a response.
-	Return *HTTPReturn `json:"return,omitempty"`
+	SetStatus []HTTPSetStatusRule `json:"setStatus,omitempty"`
 }
 
-func (h *HTTPRequestRules) Model() (models.HTTPRequestRules, error) {
-	model := models.HTTPRequestRules{}
+func (h *HTTPAfterResponseRules) Model() (models.HTTPAfterResponseRules, error) {
+	model := models.HTTPAfterResponseRules{}
+
+	for _, variable := range h.SetVar {
+		model = append(model, &models.HTTPAfterResponseRule{
+			Type:     "set-var",
+			VarScope: variable.Scope,
+			VarName:  variable.Name,
+			VarExpr:  variable.Expression,
+			Cond:     variable.ConditionType,
+			CondTest: variable.Condition,
+		})
+	}
 
-	for idx, header := range h.SetHeader {
-		model = append(model, &models.HTTPRequestRule{
-			Type:      "set-header",
-			Index:     pointer.Int64(int64(idx)),
+	for _, header := range h.DelHeader {
+		model = append(model, &models.HTTPAfterResponseRule{
+			Type:      "del-header",
 			HdrName:   header.Name,
-			HdrFormat: header.Value.String(),
+			HdrMethod: header.Method,
 			Cond:      header.ConditionType,
 			CondTest:  header.Condition,
 		})
 	}
 
-	for idx, path := range h.SetPath {
-		model = append(model, &models.HTTPRequestRule{
-			Type:     "set-path",
-			Index:    pointer.Int64(int64(idx)),
-			PathFmt:  path.Value,
-			Cond:     path.ConditionType,
-			CondTest: path.Condition,
+	for _, header := range h.SetHeader {
+		model = append(model, &models.HTTPAfterResponseRule{
+			Type:      "set-header",
+			HdrName:   header.Name,
+			HdrFormat: header.Value.String(),
+			Cond:      header.ConditionType,
+			CondTest:  header.Condition,
 		})
 	}
 
-	for idx, header := range h.AddHeader {
-		model = append(model, &models.HTTPRequestRule{
+	for _, header := range h.AddHeader {
+		model = append(model, &models.HTTPAfterResponseRule{
 			Type:      "add-header",
-			Index:     pointer.Int64(int64(idx)),
 			HdrName:   header.Name,
 			HdrFormat: header.Value.String(),
 			Cond:      header.ConditionType,
@@ -776,80 +2203,79 @@ func (h *HTTPRequestRules) Model() (models.HTTPRequestRules, error) {
 		})
 	}
 
-	if h.Deny != nil && h.Deny.Enabled {
-		model = append(model, &models.HTTPRequestRule{
-			DenyStatus: h.DenyStatus,
-			Index:      pointer.Int64(int64(0)),
-			Type:       "deny",
-			Cond:       h.Deny.ConditionType,
-			CondTest:   h.Deny.Condition,
+	for _, header := range h.ReplaceHeader {
+		model = append(model, &models.HTTPAfterResponseRule{
+			Type:      "replace-header",
+			HdrName:   header.Name,
+			HdrMatch:  header.Match,
+			HdrFormat: header.Format,
+			Cond:      header.ConditionType,
+			CondTest:  header.Condition,
 		})
 	}
 
-	for idx, redirect := range h.Redirect {
-		redirectRule := &models.HTTPRequestRule{
-			Cond:       redirect.ConditionType,
-			CondTest:   redirect.Condition,
-			Index:      pointer.Int64(int64(idx)),
-			RedirCode:  redirect.Code,
-			RedirValue: redirect.Value,
-			Type:       "redirect",
-		}
+	for _, header := range h.ReplaceValue {
+		model = append(model, &models.HTTPAfterResponseRule{
+			Type:      "replace-value",
+			HdrName:   header.Name,
+			HdrMatch:  header.Match,
+			HdrFormat: header.Format,
+			Cond:      header.ConditionType,
+			CondTest:  header.Condition,
+		})
+	}
 
-		switch redirect.Type {
-		case RedirectType{Location: true}:
-			redirectRule.RedirType = models.HTTPRequestRuleRedirTypeLocation
-		case RedirectType{Prefix: true}:
-			redirectRule.RedirType = models.HTTPRequestRuleRedirTypePrefix
-		case RedirectType{Scheme: true}:
-			redirectRule.RedirType = models.HTTPRequestRuleRedirTypeScheme
-		case RedirectType{}:
-			redirectRule.RedirType = ""
-		default:
-			return models.HTTPRequestRules{}, fmt.Errorf("you can only select one redirect type")
-		}
+	for _, status := range h.SetStatus {
+		model = append(model, &models.HTTPAfterResponseRule{
+			Type:         "set-status",
+			Status:       status.Status,
+			StatusReason: status.Reason,
+			Cond:         status.ConditionType,
+			CondTest:     status.Condition,
+		})
+	}
 
-		if redirect.Option != nil {
-			option := ""
-			if redirect.Option.DropQuery {
-				option = option + HTTPRequestRuleRedirectOptionDropQuery + " "
-			}
-			if redirect.Option.AppendSlash {
-				option = option + HTTPRequestRuleRedirectOptionAppendSlash + " "
-			}
-			if redirect.Option.SetCookie != nil {
-				option = option + HTTPRequestRuleRedirectOptionSetCookie + " " + strings.ToUpper(redirect.Option.SetCookie.Name)
-				if redirect.Option.SetCookie.Value != "" {
-					option = option + redirect.Option.SetCookie.Value + " "
-				} else {
-					option = option + " "
-				}
-			}
-			if redirect.Option.ClearCookie != nil {
-				option = option + HTTPRequestRuleRedirectOptionClearCookie + " " + strings.ToUpper(redirect.Option.ClearCookie.Name)
-				if redirect.Option.ClearCookie.Value == "=" {
-					option = option + redirect.Option.ClearCookie.Value
-				}
-			}
-			option = strings.TrimSuffix(option, " ")
-			redirectRule.RedirOption = option
-		}
-		model = append(model, redirectRule)
+	for i := 0; i < len(model); i++ {
+		model[i].Index = pointer.Int64(int64(i))
 	}
 
-	if h.Return != nil {
-		value := h.Return.Content.Value
-		if strings.Contains(h.Return.Content.Format, "string") {
-			value = fmt.Sprintf("\"%s\"", h.Return.Content.Value)
-		}
+	return model, model.Validate(strfmt.Default)
+}
 
-		model = append(model, &models.HTTPRequestRule{
-			Type:                "return",
-			ReturnStatusCode:    h.Return.Status,
-			ReturnContentType:   pointer.String(h.Return.Content.Type),
-			ReturnContentFormat: h.Return.Content.Format,
-			ReturnContent:       value,
-		})
+// HTTPResponseRules are evaluated in the order of Rules. The fields per action are kept for compatibility and are
+// evaluated before Rules, grouped by action in the following order: set-var, del-header, set-header, add-header,
+// replace-header, replace-value, set-status, deny, redirect and return.
+type HTTPResponseRules struct {
+	HTTPAfterResponseRules `json:",inline"`
+	// Rules are evaluated in the order they are declared. Each rule sets exactly one action.
+	// +optional
+	Rules []HTTPResponseRule `json:"rules,omitempty"`
+	// Deny stops the evaluation of the rules and immediately replaces the response by an HTTP 502 error.
+	// Optionally the status code specified as an argument to deny_status.
+	// +optional
+	Deny *Deny `json:"deny,omitempty"`
+	// DenyStatus is the HTTP status code of Deny and of the deny rules of Rules not specifying a status code.
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	DenyStatus *int64 `json:"denyStatus,omitempty"`
+	// Redirect replaces the response by an HTTP redirection based on a redirect rule.
+	// +optional
+	Redirect []Redirect `json:"redirect,omitempty"`
+	// Return stops the evaluation of the rules and immediately replaces the response.
+	// +optional
+	Return *HTTPReturn `json:"return,omitempty"`
+}
+
+func (h *HTTPResponseRules) Model() (models.HTTPResponseRules, error) {
+	model := models.HTTPResponseRules{}
+
+	for _, rule := range append(h.groupedRules(), h.rules()...) {
+		ruleModel, err := rule.Model()
+		if err != nil {
+			return models.HTTPResponseRules{}, err
+		}
+		model = append(model, ruleModel)
 	}
 
 	for i := 0; i < len(model); i++ {
@@ -859,6 +2285,196 @@ func (h *HTTPRequestRules) Model() (models.HTTPRequestRules, error) {
 	return model, model.Validate(strfmt.Default)
 }
 
+// groupedRules converts the action fields into ordered rules.
+func (h *HTTPResponseRules) groupedRules() []HTTPResponseRule {
+	var rules []HTTPResponseRule
+
+	for i := range h.SetVar {
+		rules = append(rules, HTTPResponseRule{SetVar: &h.SetVar[i]})
+	}
+	for i := range h.DelHeader {
+		rules = append(rules, HTTPResponseRule{DelHeader: &h.DelHeader[i]})
+	}
+	for i := range h.SetHeader {
+		rules = append(rules, HTTPResponseRule{SetHeader: &h.SetHeader[i]})
+	}
+	for i := range h.AddHeader {
+		rules = append(rules, HTTPResponseRule{AddHeader: &h.AddHeader[i]})
+	}
+	for i := range h.ReplaceHeader {
+		rules = append(rules, HTTPResponseRule{ReplaceHeader: &h.ReplaceHeader[i]})
+	}
+	for i := range h.ReplaceValue {
+		rules = append(rules, HTTPResponseRule{ReplaceValue: &h.ReplaceValue[i]})
+	}
+	for i := range h.SetStatus {
+		rules = append(rules, HTTPResponseRule{SetStatus: &h.SetStatus[i]})
+	}
+	if h.Deny != nil && h.Deny.Enabled {
+		rules = append(rules, HTTPResponseRule{Deny: &HTTPDenyRule{Rule: h.Deny.Rule, Status: h.DenyStatus}})
+	}
+	for i := range h.Redirect {
+		rules = append(rules, HTTPResponseRule{Redirect: &h.Redirect[i]})
+	}
+	if h.Return != nil {
+		rules = append(rules, HTTPResponseRule{Return: &HTTPReturnRule{HTTPReturn: *h.Return}})
+	}
+
+	return rules
+}
+
+// rules returns Rules with DenyStatus applied to the deny rules not specifying a status code.
+func (h *HTTPResponseRules) rules() []HTTPResponseRule {
+	rules := make([]HTTPResponseRule, 0, len(h.Rules))
+	for _, rule := range h.Rules {
+		if rule.Deny != nil {
+			rule.Deny = &HTTPDenyRule{Rule: rule.Deny.Rule, Status: rule.Deny.status(h.DenyStatus)}
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// HTTPResponseRule is a single http-response rule. Exactly one action must be set.
+type HTTPResponseRule struct {
+	// SetVar sets a variable to the result of a sample expression.
+	// +optional
+	SetVar *HTTPSetVarRule `json:"setVar,omitempty"`
+	// DelHeader removes HTTP header fields.
+	// +optional
+	DelHeader *HTTPDelHeaderRule `json:"delHeader,omitempty"`
+	// SetHeader sets an HTTP header field.
+	// +optional
+	SetHeader *HTTPHeaderRule `json:"setHeader,omitempty"`
+	// AddHeader appends an HTTP header field.
+	// +optional
+	AddHeader *HTTPHeaderRule `json:"addHeader,omitempty"`
+	// ReplaceHeader replaces the matches of a regular expression in the values of HTTP header fields.
+	// +optional
+	ReplaceHeader *HTTPReplaceHeaderRule `json:"replaceHeader,omitempty"`
+	// ReplaceValue replaces the matches of a regular expression in each comma-delimited value of HTTP header fields.
+	// +optional
+	ReplaceValue *HTTPReplaceHeaderRule `json:"replaceValue,omitempty"`
+	// SetStatus replaces the status code of the response.
+	// +optional
+	SetStatus *HTTPSetStatusRule `json:"setStatus,omitempty"`
+	// Deny replaces the response by an HTTP 502 error, or the status code of the rule.
+	// +optional
+	Deny *HTTPDenyRule `json:"deny,omitempty"`
+	// Redirect replaces the response by an HTTP redirection.
+	// +optional
+	Redirect *Redirect `json:"redirect,omitempty"`
+	// Return immediately replaces the response.
+	// +optional
+	Return *HTTPReturnRule `json:"return,omitempty"`
+}
+
+func (r *HTTPResponseRule) Model() (*models.HTTPResponseRule, error) {
+	if count := countActions(r); count != 1 {
+		return nil, fmt.Errorf("http-response rule must set exactly one action, found %d", count)
+	}
+
+	switch {
+	case r.SetVar != nil:
+		return &models.HTTPResponseRule{
+			Type:     "set-var",
+			VarScope: r.SetVar.Scope,
+			VarName:  r.SetVar.Name,
+			VarExpr:  r.SetVar.Expression,
+			Cond:     r.SetVar.ConditionType,
+			CondTest: r.SetVar.Condition,
+		}, nil
+	case r.DelHeader != nil:
+		return &models.HTTPResponseRule{
+			Type:      "del-header",
+			HdrName:   r.DelHeader.Name,
+			HdrMethod: r.DelHeader.Method,
+			Cond:      r.DelHeader.ConditionType,
+			CondTest:  r.DelHeader.Condition,
+		}, nil
+	case r.SetHeader != nil:
+		return &models.HTTPResponseRule{
+			Type:      "set-header",
+			HdrName:   r.SetHeader.Name,
+			HdrFormat: r.SetHeader.Value.String(),
+			Cond:      r.SetHeader.ConditionType,
+			CondTest:  r.SetHeader.Condition,
+		}, nil
+	case r.AddHeader != nil:
+		return &models.HTTPResponseRule{
+			Type:      "add-header",
+			HdrName:   r.AddHeader.Name,
+			HdrFormat: r.AddHeader.Value.String(),
+			Cond:      r.AddHeader.ConditionType,
+			CondTest:  r.AddHeader.Condition,
+		}, nil
+	case r.ReplaceHeader != nil:
+		return &models.HTTPResponseRule{
+			Type:      "replace-header",
+			HdrName:   r.ReplaceHeader.Name,
+			HdrMatch:  r.ReplaceHeader.Match,
+			HdrFormat: r.ReplaceHeader.Format,
+			Cond:      r.ReplaceHeader.ConditionType,
+			CondTest:  r.ReplaceHeader.Condition,
+		}, nil
+	case r.ReplaceValue != nil:
+		return &models.HTTPResponseRule{
+			Type:      "replace-value",
+			HdrName:   r.ReplaceValue.Name,
+			HdrMatch:  r.ReplaceValue.Match,
+			HdrFormat: r.ReplaceValue.Format,
+			Cond:      r.ReplaceValue.ConditionType,
+			CondTest:  r.ReplaceValue.Condition,
+		}, nil
+	case r.SetStatus != nil:
+		return &models.HTTPResponseRule{
+			Type:         "set-status",
+			Status:       r.SetStatus.Status,
+			StatusReason: r.SetStatus.Reason,
+			Cond:         r.SetStatus.ConditionType,
+			CondTest:     r.SetStatus.Condition,
+		}, nil
+	case r.Deny != nil:
+		return &models.HTTPResponseRule{
+			Type:       "deny",
+			DenyStatus: r.Deny.Status,
+			Cond:       r.Deny.ConditionType,
+			CondTest:   r.Deny.Condition,
+		}, nil
+	case r.Redirect != nil:
+		redirType, redirOption, err := r.Redirect.typeAndOption()
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.HTTPResponseRule{
+			Type:        "redirect",
+			RedirCode:   r.Redirect.Code,
+			RedirValue:  r.Redirect.Value,
+			RedirType:   redirType,
+			RedirOption: redirOption,
+			Cond:        r.Redirect.ConditionType,
+			CondTest:    r.Redirect.Condition,
+		}, nil
+	default:
+		return &models.HTTPResponseRule{
+			Type:                "return",
+			ReturnStatusCode:    r.Return.Status,
+			ReturnContentType:   pointer.String(r.Return.Content.Type),
+			ReturnContentFormat: r.Return.Content.Format,
+			ReturnContent:       r.Return.Content.value(),
+			Cond:                r.Return.ConditionType,
+			CondTest:            r.Return.Condition,
+		}, nil
+	}
+}
+
+type HTTPReturnRule struct {
+	Rule       `json:",inline"`
+	HTTPReturn `json:",inline"`
+}
+
 type HTTPReturn struct {
 	// Status can be optionally specified, the default status code used for the response is 200.
 	// +kubebuilder:default=200
@@ -877,6 +2493,74 @@ type HTTPReturnContent struct {
 	Value string `json:"value"`
 }
 
+func (h *HTTPReturnContent) value() string {
+	if strings.Contains(h.Format, "string") {
+		return fmt.Sprintf("\"%s\"", h.Value)
+	}
+
+	return h.Value
+}
+
+type HTTPAuthRule struct {
+	// Rule restricts the authentication to the requests matching the condition.
+	Rule `json:",inline"`
+	// Realm is presented to the client.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	// +optional
+	Realm string `json:"realm,omitempty"`
+	// Userlist references the Userlist authenticating the clients.
+	Userlist corev1.LocalObjectReference `json:"userlist"`
+	// Groups restricts the access to the users of the listed groups.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+}
+
+// condition returns the condition matching the requests which are not authenticated. For 'if' conditions the negated
+// authentication is added to each alternative of the condition of the rule.
+func (a *HTTPAuthRule) condition() (string, string) {
+	authenticated := fmt.Sprintf("{ http_auth(%s) }", a.Userlist.Name)
+	if len(a.Groups) > 0 {
+		authenticated = fmt.Sprintf("{ http_auth_group(%s) %s }", a.Userlist.Name, strings.Join(a.Groups, " "))
+	}
+
+	if a.Condition == "" {
+		return "if", "!" + authenticated
+	}
+
+	if a.ConditionType == "unless" {
+		return "unless", fmt.Sprintf("%s || %s", a.Condition, authenticated)
+	}
+
+	var terms []string
+	for _, term := range conditionTerms(a.Condition) {
+		terms = append(terms, fmt.Sprintf("%s !%s", term, authenticated))
+	}
+
+	return "if", strings.Join(terms, " || ")
+}
+
+// conditionTerms splits a condition into the alternatives joined by '||' or 'or'.
+func conditionTerms(condition string) []string {
+	var terms []string
+	var term []string
+	depth := 0
+	for _, field := range strings.Fields(condition) {
+		switch {
+		case field == "{":
+			depth++
+		case field == "}":
+			depth--
+		case depth == 0 && (field == "||" || field == "or"):
+			terms = append(terms, strings.Join(term, " "))
+			term = nil
+			continue
+		}
+		term = append(term, field)
+	}
+
+	return append(terms, strings.Join(term, " "))
+}
+
 type HTTPHeaderRule struct {
 	Rule `json:",inline"`
 	// Name specifies the header name
@@ -885,11 +2569,142 @@ type HTTPHeaderRule struct {
 	Value HTTPHeaderValue `json:"value"`
 }
 
+type HTTPDelHeaderRule struct {
+	Rule `json:",inline"`
+	// Name specifies the header name
+	Name string `json:"name"`
+	// Method is the matching method applied on the header name.
+	// +kubebuilder:validation:Enum=str;beg;end;sub;reg
+	// +optional
+	Method string `json:"method,omitempty"`
+}
+
+type HTTPReplaceHeaderRule struct {
+	Rule `json:",inline"`
+	// Name specifies the header name
+	Name string `json:"name"`
+	// Match is the regular expression matched against the header values.
+	Match string `json:"match"`
+	// Format replaces the matches and may reference the capture groups, e.g. \1.
+	Format string `json:"format"`
+}
+
+type HTTPSetStatusRule struct {
+	Rule `json:",inline"`
+	// Status is the new status code of the response.
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=999
+	Status int64 `json:"status"`
+	// Reason replaces the reason phrase of the status code.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+type HTTPSetVarRule struct {
+	Rule `json:",inline"`
+	// Scope of the variable.
+	// +kubebuilder:validation:Enum=proc;sess;txn;req;res
+	Scope string `json:"scope"`
+	// Name of the variable.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	Name string `json:"name"`
+	// Expression is the sample expression whose result is assigned to the variable.
+	Expression string `json:"expression"`
+}
+
 type HTTPPathRule struct {
 	Rule `json:",inline"`
 	// Value specifies the path value
 	Value string `json:"format,omitempty"`
 }
+
+type HTTPFormatRule struct {
+	Rule `json:",inline"`
+	// Format is the log-format string of the new value.
+	Format string `json:"format"`
+}
+
+type HTTPReplaceRule struct {
+	Rule `json:",inline"`
+	// Match is the regular expression matched against the value.
+	Match string `json:"match"`
+	// Format replaces the matches and may reference the capture groups, e.g. \1.
+	Format string `json:"format"`
+}
+
+type HTTPUnsetVarRule struct {
+	Rule `json:",inline"`
+	// Scope of the variable.
+	// +kubebuilder:validation:Enum=proc;sess;txn;req;res
+	Scope string `json:"scope"`
+	// Name of the variable.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	Name string `json:"name"`
+}
+
+type HTTPSetLogLevelRule struct {
+	Rule `json:",inline"`
+	// Level is the new log level.
+	// +kubebuilder:validation:Enum=emerg;alert;crit;err;warning;notice;info;debug;silent
+	Level string `json:"level"`
+}
+
+type HTTPCaptureRule struct {
+	Rule `json:",inline"`
+	// Sample is the sample expression to capture.
+	Sample string `json:"sample"`
+	// Length of the capture slot allocated in the frontend. Exclusive with ID.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Length *int64 `json:"length,omitempty"`
+	// ID of a capture slot declared by a 'declare capture' statement. Exclusive with Length.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ID *int64 `json:"id,omitempty"`
+}
+
+type HTTPExpressionRule struct {
+	Rule `json:",inline"`
+	// Expression is a sample expression.
+	Expression string `json:"expression"`
+}
+
+type HTTPNormalizeURIRule struct {
+	Rule `json:",inline"`
+	// Normalizer applied to the URI.
+	// +kubebuilder:validation:Enum=fragment-encode;fragment-strip;path-merge-slashes;path-strip-dot;path-strip-dotdot;percent-decode-unreserved;percent-to-uppercase;query-sort-by-name
+	Normalizer string `json:"normalizer"`
+	// Full removes the leading dot-dot segments of path-strip-dotdot.
+	// +optional
+	Full bool `json:"full,omitempty"`
+	// Strict rejects invalid percent-encodings with percent-decode-unreserved and percent-to-uppercase.
+	// +optional
+	Strict bool `json:"strict,omitempty"`
+}
+
+type HTTPDenyRule struct {
+	Rule `json:",inline"`
+	// Status is the HTTP status code of the response.
+	// +kubebuilder:validation:Minimum=200
+	// +kubebuilder:validation:Maximum=599
+	// +optional
+	Status *int64 `json:"status,omitempty"`
+}
+
+func (d *HTTPDenyRule) status(defaultStatus *int64) *int64 {
+	if d.Status != nil {
+		return d.Status
+	}
+
+	return defaultStatus
+}
+
+type HTTPNameRule struct {
+	Rule `json:",inline"`
+	// Name of the service or cache.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	Name string `json:"name"`
+}
 type HTTPHeaderValue struct {
 	// Env variable with the header value
 	Env *corev1.EnvVar `json:"env,omitempty"`
@@ -922,6 +2737,24 @@ type Status struct {
 	// Error shows the actual error message if Phase is 'Error'.
 	// +optional
 	Error string `json:"error,omitempty"`
+	// Certificates shows the certificates of the binds and servers.
+	// +optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+}
+
+// CertificateStatus describes the first certificate of a certificate file.
+type CertificateStatus struct {
+	// File is the path of the certificate file.
+	File string `json:"file"`
+	// Subject of the certificate.
+	Subject string `json:"subject"`
+	// SANs are the subject alternative names of the certificate.
+	// +optional
+	SANs []string `json:"sans,omitempty"`
+	// Issuer of the certificate.
+	Issuer string `json:"issuer"`
+	// NotAfter is the time the certificate expires.
+	NotAfter metav1.Time `json:"notAfter"`
 }
 
 // StatusPhase is a label for the phase of an object at the current time.
@@ -966,6 +2799,50 @@ type Redirect struct {
 	Option *RedirectOption `json:"option,omitempty"`
 }
 
+func (r *Redirect) typeAndOption() (string, string, error) {
+	var redirType string
+	switch r.Type {
+	case RedirectType{Location: true}:
+		redirType = models.HTTPRequestRuleRedirTypeLocation
+	case RedirectType{Prefix: true}:
+		redirType = models.HTTPRequestRuleRedirTypePrefix
+	case RedirectType{Scheme: true}:
+		redirType = models.HTTPRequestRuleRedirTypeScheme
+	case RedirectType{}:
+		redirType = ""
+	default:
+		return "", "", fmt.Errorf("you can only select one redirect type")
+	}
+
+	if r.Option == nil {
+		return redirType, "", nil
+	}
+
+	option := ""
+	if r.Option.DropQuery {
+		option = option + HTTPRequestRuleRedirectOptionDropQuery + " "
+	}
+	if r.Option.AppendSlash {
+		option = option + HTTPRequestRuleRedirectOptionAppendSlash + " "
+	}
+	if r.Option.SetCookie != nil {
+		option = option + HTTPRequestRuleRedirectOptionSetCookie + " " + strings.ToUpper(r.Option.SetCookie.Name)
+		if r.Option.SetCookie.Value != "" {
+			option = option + r.Option.SetCookie.Value + " "
+		} else {
+			option = option + " "
+		}
+	}
+	if r.Option.ClearCookie != nil {
+		option = option + HTTPRequestRuleRedirectOptionClearCookie + " " + strings.ToUpper(r.Option.ClearCookie.Name)
+		if r.Option.ClearCookie.Value == "=" {
+			option = option + r.Option.ClearCookie.Value
+		}
+	}
+
+	return redirType, strings.TrimSuffix(option, " "), nil
+}
+
 type RedirectType struct {
 	// Location replaces the entire location of a URL.
 	// +optional
@@ -1125,3 +3002,37 @@ type ProxyProtocolV2Options struct {
 	// +optional
 	UniqueID bool `json:"uniqueID"`
 }
+
+func (o *ProxyProtocolV2Options) list() []string {
+	if o == nil {
+		return nil
+	}
+
+	var options []string
+	if o.Ssl {
+		options = append(options, "ssl")
+	}
+	if o.CertCn {
+		options = append(options, "cert-cn")
+	}
+	if o.SslCipher {
+		options = append(options, "ssl-cipher")
+	}
+	if o.CertSig {
+		options = append(options, "cert-sig")
+	}
+	if o.CertKey {
+		options = append(options, "cert-key")
+	}
+	if o.Authority {
+		options = append(options, "authority")
+	}
+	if o.Crc32c {
+		options = append(options, "crc32c")
+	}
+	if o.UniqueID {
+		options = append(options, "unique-id")
+	}
+
+	return options
+}
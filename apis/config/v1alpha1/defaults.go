@@ -0,0 +1,103 @@
+package v1alpha1
+
+import (
+	"k8s.io/utils/pointer"
+)
+
+// Default sets the values which are otherwise assumed when the configuration is rendered.
+func (l *Listen) Default() {
+	defaultBinds(l.Spec.Binds)
+	defaultServers(l.Spec.Servers, l.Spec.ServerTemplates, l.Spec.ServiceRef)
+	for idx := range l.Spec.ServiceRefs {
+		defaultServerParams(&l.Spec.ServiceRefs[idx].ServerParams)
+	}
+	defaultCookie(l.Spec.Cookie)
+	defaultRateLimit(l.Spec.RateLimit)
+}
+
+// Default sets the values which are otherwise assumed when the configuration is rendered.
+func (f *Frontend) Default() {
+	defaultBinds(f.Spec.Binds)
+	defaultRateLimit(f.Spec.RateLimit)
+}
+
+// Default sets the values which are otherwise assumed when the configuration is rendered.
+func (b *Backend) Default() {
+	defaultServers(b.Spec.Servers, b.Spec.ServerTemplates, b.Spec.ServiceRef)
+	for idx := range b.Spec.ServiceRefs {
+		defaultServerParams(&b.Spec.ServiceRefs[idx].ServerParams)
+	}
+	defaultCookie(b.Spec.Cookie)
+	defaultRateLimit(b.Spec.RateLimit)
+}
+
+// Default sets the values which are otherwise assumed when the configuration is rendered.
+func (r *Resolver) Default() {
+	if r.Spec.ParseResolvConf == nil {
+		r.Spec.ParseResolvConf = pointer.Bool(false)
+	}
+	if r.Spec.ResolveRetries == nil {
+		r.Spec.ResolveRetries = pointer.Int64(3)
+	}
+}
+
+// Default sets the values which are otherwise assumed when the configuration is rendered.
+func (p *Peers) Default() {
+	if p.Spec.Port == 0 {
+		p.Spec.Port = 10000
+	}
+}
+
+// Default is a no-op, the userlist has no values which are assumed when the configuration is rendered.
+func (u *Userlist) Default() {}
+
+func defaultBinds(binds []Bind) {
+	for idx := range binds {
+		if binds[idx].AcceptProxy == nil {
+			binds[idx].AcceptProxy = pointer.Bool(false)
+		}
+	}
+}
+
+func defaultServers(servers []Server, templates []ServerTemplate, ref *ServiceReference) {
+	for idx := range servers {
+		defaultServerParams(&servers[idx].ServerParams)
+	}
+	for idx := range templates {
+		defaultServerParams(&templates[idx].ServerParams)
+	}
+	if ref != nil {
+		defaultServerParams(&ref.ServerParams)
+	}
+}
+
+func defaultServerParams(params *ServerParams) {
+	if params.SendProxy == nil {
+		params.SendProxy = pointer.Bool(false)
+	}
+}
+
+func defaultCookie(cookie *Cookie) {
+	if cookie == nil {
+		return
+	}
+
+	for _, field := range []**bool{&cookie.Indirect, &cookie.NoCache, &cookie.PostOnly, &cookie.Preserve, &cookie.HTTPOnly, &cookie.Secure, &cookie.Dynamic} {
+		if *field == nil {
+			*field = pointer.Bool(false)
+		}
+	}
+}
+
+func defaultRateLimit(rateLimit *RateLimit) {
+	if rateLimit == nil {
+		return
+	}
+
+	if rateLimit.Key == "" {
+		rateLimit.Key = "src"
+	}
+	if rateLimit.Action == "" {
+		rateLimit.Action = "deny"
+	}
+}
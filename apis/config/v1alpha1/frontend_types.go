@@ -8,6 +8,7 @@ import (
 	"github.com/haproxytech/client-native/v4/configuration"
 	"github.com/haproxytech/client-native/v4/models"
 	parser "github.com/haproxytech/config-parser/v4"
+	"github.com/haproxytech/config-parser/v4/params"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
@@ -24,6 +25,143 @@ type FrontendSpec struct {
 	BackendSwitching []BackendSwitchingRule `json:"backendSwitching,omitempty"`
 	// DefaultBackend to use when no 'use_backend' rule has been matched.
 	DefaultBackend corev1.LocalObjectReference `json:"defaultBackend"`
+	// StickTable declares a stick table in the frontend.
+	// +optional
+	StickTable *StickTable `json:"stickTable,omitempty"`
+	// TrafficSplit distributes the requests not matched by a backend switching rule between multiple
+	// backends by weight, e.g. for canary releases. Only supported in HTTP mode.
+	// +optional
+	TrafficSplit *TrafficSplit `json:"trafficSplit,omitempty"`
+}
+
+// trafficSplitSlots is the number of map entries the traffic is distributed on.
+const trafficSplitSlots = 100
+
+type TrafficSplit struct {
+	// Backends between which the traffic is split.
+	// +kubebuilder:validation:MinItems=1
+	Backends []WeightedBackend `json:"backends"`
+	// Pinning keeps requests sharing the same header or cookie value on the same backend.
+	// Requests without the header or cookie are distributed randomly.
+	// +optional
+	Pinning *TrafficSplitPinning `json:"pinning,omitempty"`
+}
+
+type WeightedBackend struct {
+	// Name of the backend.
+	Name string `json:"name"`
+	// Weight of the backend relative to the sum of all weights. With weights summing up to 100 the
+	// weight is the percentage of the requests sent to the backend.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Weight int64 `json:"weight"`
+}
+
+type TrafficSplitPinning struct {
+	// Header name whose value selects the backend.
+	// +kubebuilder:validation:Pattern=^[^\s()]+$
+	// +optional
+	Header string `json:"header,omitempty"`
+	// Cookie name whose value selects the backend.
+	// +kubebuilder:validation:Pattern=^[^\s()]+$
+	// +optional
+	Cookie string `json:"cookie,omitempty"`
+}
+
+// FilePath returns the path of the map file assigning the slots to the backends.
+func (t *TrafficSplit) FilePath(frontend string) string {
+	return fmt.Sprintf("/usr/local/etc/haproxy/%s-traffic-split.map", frontend)
+}
+
+// EffectiveWeights returns the percentage of slots assigned to each backend. The slots are distributed
+// proportionally to the weights, remaining slots go to the backends with the largest remainder.
+func (t *TrafficSplit) EffectiveWeights() ([]WeightedBackend, error) {
+	var total int64
+	for _, backend := range t.Backends {
+		total += backend.Weight
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("traffic split requires at least one backend with a weight greater than zero")
+	}
+
+	weights := make([]WeightedBackend, len(t.Backends))
+	remainders := make([]int64, len(t.Backends))
+	assigned := int64(0)
+	for i, backend := range t.Backends {
+		weights[i] = WeightedBackend{Name: backend.Name, Weight: backend.Weight * trafficSplitSlots / total}
+		remainders[i] = backend.Weight * trafficSplitSlots % total
+		assigned += weights[i].Weight
+	}
+
+	for ; assigned < trafficSplitSlots; assigned++ {
+		largest := 0
+		for i := range remainders {
+			if remainders[i] > remainders[largest] {
+				largest = i
+			}
+		}
+		weights[largest].Weight++
+		remainders[largest] = -1
+	}
+
+	return weights, nil
+}
+
+// Map returns the content of the map file assigning each slot to a backend.
+func (t *TrafficSplit) Map() (string, error) {
+	weights, err := t.EffectiveWeights()
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, backend := range weights {
+		for i := int64(0); i < backend.Weight; i++ {
+			lines = append(lines, fmt.Sprintf("%d %s", len(lines), backend.Name))
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (t *TrafficSplit) rules() ([]models.HTTPRequestRule, error) {
+	rules := []models.HTTPRequestRule{{
+		Index:    pointer.Int64(0),
+		Type:     models.HTTPRequestRuleTypeSetDashVar,
+		VarName:  "traffic_split",
+		VarScope: "txn",
+		VarExpr:  fmt.Sprintf("rand(%d)", trafficSplitSlots),
+	}}
+
+	if t.Pinning != nil {
+		var sample string
+		switch {
+		case t.Pinning.Header != "" && t.Pinning.Cookie == "":
+			sample = fmt.Sprintf("req.hdr(%s)", t.Pinning.Header)
+		case t.Pinning.Cookie != "" && t.Pinning.Header == "":
+			sample = fmt.Sprintf("req.cook(%s)", t.Pinning.Cookie)
+		default:
+			return nil, fmt.Errorf("traffic split pinning requires either a header or a cookie")
+		}
+
+		rules = append(rules, models.HTTPRequestRule{
+			Type:     models.HTTPRequestRuleTypeSetDashVar,
+			Index:    pointer.Int64(0),
+			VarName:  "traffic_split",
+			VarScope: "txn",
+			VarExpr:  fmt.Sprintf("%s,crc32,mod(%d)", sample, trafficSplitSlots),
+			Cond:     "if",
+			CondTest: fmt.Sprintf("{ %s -m found }", sample),
+		})
+	}
+
+	for i := range rules {
+		if err := rules[i].Validate(strfmt.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
 }
 
 type BackendSwitchingRule struct {
@@ -86,18 +224,26 @@ type Frontend struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   FrontendSpec `json:"spec,omitempty"`
-	Status Status       `json:"status,omitempty"`
+	Spec   FrontendSpec   `json:"spec,omitempty"`
+	Status FrontendStatus `json:"status,omitempty"`
+}
+
+// FrontendStatus defines the observed state of a Frontend
+type FrontendStatus struct {
+	Status `json:",inline"`
+	// TrafficSplit shows the effective share in percent of each backend of the traffic split.
+	// +optional
+	TrafficSplit []WeightedBackend `json:"trafficSplit,omitempty"`
 }
 
 var _ Object = &Frontend{}
 
 func (f *Frontend) SetStatus(status Status) {
-	f.Status = status
+	f.Status.Status = status
 }
 
 func (f *Frontend) GetStatus() Status {
-	return f.Status
+	return f.Status.Status
 }
 
 func (f *Frontend) Model() (models.Frontend, error) {
@@ -120,6 +266,12 @@ func (f *Frontend) Model() (models.Frontend, error) {
 		}
 	}
 
+	stickTable, err := f.Spec.StickTableModel(f.Spec.StickTable)
+	if err != nil {
+		return model, err
+	}
+	model.StickTable = stickTable
+
 	for name, timeout := range f.Spec.Timeouts {
 		switch name {
 		case "client":
@@ -143,6 +295,55 @@ func (f *Frontend) Model() (models.Frontend, error) {
 	return model, model.Validate(strfmt.Default)
 }
 
+// mutualTLSRules returns the actions for the requests without a valid client certificate, followed by the rules
+// forwarding the client certificate details. All forwarded headers are removed before they are set, so that headers
+// sent by the clients are dropped on every bind.
+func (f *Frontend) mutualTLSRules() (models.HTTPRequestRules, error) {
+	var actions, deletes, headers models.HTTPRequestRules
+	deleted := map[string]bool{}
+
+	for _, bind := range f.Spec.Binds {
+		if bind.SSL == nil || !bind.SSL.Enabled || bind.SSL.MutualTLS == nil {
+			continue
+		}
+		mtls := bind.SSL.MutualTLS
+
+		action, err := mtls.actionRule(bind.Name)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			actions = append(actions, action)
+		}
+
+		for i := range mtls.ForwardHeaders {
+			header, err := mtls.ForwardHeaders[i].headerRule(bind.Name)
+			if err != nil {
+				return nil, err
+			}
+			headers = append(headers, header)
+
+			if name := mtls.ForwardHeaders[i].Name; !deleted[name] {
+				deleted[name] = true
+				deletes = append(deletes, &models.HTTPRequestRule{Index: pointer.Int64(0), Type: "del-header", HdrName: name})
+			}
+		}
+	}
+
+	rules := append(append(actions, deletes...), headers...)
+	if len(rules) > 0 && f.Spec.Mode != "http" {
+		return nil, fmt.Errorf("client certificate actions and headers are only supported in http mode")
+	}
+
+	for i := range rules {
+		if err := rules[i].Validate(strfmt.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	return rules, nil
+}
+
 func (f *Frontend) AddToParser(p parser.Parser) error {
 	err := p.SectionsCreate(parser.Frontends, f.Name)
 	if err != nil {
@@ -170,12 +371,33 @@ func (f *Frontend) AddToParser(p parser.Parser) error {
 			return err
 		}
 
-		err = p.Insert(parser.Frontends, f.Name, "bind", configuration.SerializeBind(model), idx)
+		data := configuration.SerializeBind(model)
+		// ca-verify-file is not serialized by client-native
+		if model.CaVerifyFile != "" {
+			data.Params = append(data.Params, &params.BindOptionValue{Name: "ca-verify-file", Value: model.CaVerifyFile})
+		}
+
+		err = p.Insert(parser.Frontends, f.Name, "bind", data, idx)
 		if err != nil {
 			return err
 		}
 	}
 
+	// the client certificate rules are evaluated before the rules of the user
+	rules, err := f.mutualTLSRules()
+	if err != nil {
+		return err
+	}
+	for idx, rule := range rules {
+		data, err := configuration.SerializeHTTPRequestRule(*rule)
+		if err != nil {
+			return err
+		}
+		if err := p.Insert(parser.Frontends, f.Name, "http-request", data, idx); err != nil {
+			return err
+		}
+	}
+
 	for idx, rule := range f.Spec.BackendSwitching {
 		model, err := rule.Model()
 		if err != nil {
@@ -188,6 +410,37 @@ func (f *Frontend) AddToParser(p parser.Parser) error {
 		}
 	}
 
+	if f.Spec.TrafficSplit != nil {
+		if f.Spec.Mode != "http" {
+			return fmt.Errorf("traffic split is only supported in http mode")
+		}
+
+		if _, err := f.Spec.TrafficSplit.EffectiveWeights(); err != nil {
+			return err
+		}
+
+		rules, err := f.Spec.TrafficSplit.rules()
+		if err != nil {
+			return err
+		}
+		for i := range rules {
+			data, err := configuration.SerializeHTTPRequestRule(rules[i])
+			if err != nil {
+				return err
+			}
+			if err := p.Insert(parser.Frontends, f.Name, "http-request", data); err != nil {
+				return err
+			}
+		}
+
+		rule := models.BackendSwitchingRule{
+			Name: fmt.Sprintf("%%[var(txn.traffic_split),map_int(%s)]", f.Spec.TrafficSplit.FilePath(f.Name)),
+		}
+		if err := p.Insert(parser.Frontends, f.Name, "use_backend", configuration.SerializeBackendSwitchingRule(rule)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
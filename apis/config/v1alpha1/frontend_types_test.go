@@ -1,11 +1,16 @@
 package v1alpha1_test
 
 import (
+	"strings"
+	"time"
+
 	parser "github.com/haproxytech/config-parser/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 var simpleFrontend = `
@@ -60,5 +65,223 @@ var _ = Describe("Frontend", Label("type"), func() {
 			a := p.String()
 			Ω(a).Should(Equal(withBackendRule))
 		})
+
+		It("should create stick-table", func() {
+			frontend := &configv1alpha1.Frontend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.FrontendSpec{
+					StickTable: &configv1alpha1.StickTable{
+						Type:   "ip",
+						Size:   100000,
+						Expire: &metav1.Duration{Duration: 30 * time.Second},
+						Store:  []string{"conn_cur", "http_req_rate(10s)"},
+						Peers:  "mypeers",
+					},
+				},
+			}
+			Ω(frontend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(Equal("\nfrontend foo\n  stick-table type ip size 100000 expire 30000 peers mypeers store conn_cur,http_req_rate(10s)\n"))
+		})
+
+		It("should limit the request rate", func() {
+			frontend := &configv1alpha1.Frontend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.FrontendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						Mode: "http",
+						HTTPRequest: &configv1alpha1.HTTPRequestRules{
+							SetPath: []configv1alpha1.HTTPPathRule{{Value: "/"}},
+						},
+						RateLimit: &configv1alpha1.RateLimit{
+							StickTable: configv1alpha1.StickTable{
+								Type:   "string",
+								Size:   1000,
+								Expire: &metav1.Duration{Duration: time.Minute},
+								Store:  []string{"http_req_rate(10s)", "http_err_rate(10s)"},
+							},
+							Key:          "req.hdr(x-api-key)",
+							StickCounter: 1,
+							Thresholds: []configv1alpha1.RateLimitThreshold{
+								{Counter: "http_req_rate", Limit: 100},
+								{Counter: "http_err_rate", Limit: 10},
+							},
+						},
+					},
+				},
+			}
+			Ω(frontend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(Equal("\nfrontend foo\n  mode http\n" +
+				"  http-request track-sc1 req.hdr(x-api-key)\n" +
+				"  http-request deny deny_status 429 if { sc_http_req_rate(1) gt 100 } || { sc_http_err_rate(1) gt 10 }\n" +
+				"  http-request set-path /\n" +
+				"  stick-table type string size 1000 expire 60000 store http_req_rate(10s),http_err_rate(10s)\n"))
+		})
+
+		It("should require the rate limit counters to be stored", func() {
+			frontend := &configv1alpha1.Frontend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.FrontendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						RateLimit: &configv1alpha1.RateLimit{
+							StickTable: configv1alpha1.StickTable{Type: "ip", Size: 1000, Store: []string{"conn_cur"}},
+							Thresholds: []configv1alpha1.RateLimitThreshold{{Counter: "http_req_rate", Limit: 100}},
+						},
+					},
+				},
+			}
+			Ω(frontend.AddToParser(p)).Should(MatchError("rate limit counter http_req_rate is not stored in the stick table"))
+		})
+
+		It("should request authentication", func() {
+			frontend := &configv1alpha1.Frontend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.FrontendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						HTTPRequest: &configv1alpha1.HTTPRequestRules{
+							SetPath: []configv1alpha1.HTTPPathRule{{Value: "/"}},
+							Auth: []configv1alpha1.HTTPAuthRule{
+								{
+									Rule: configv1alpha1.Rule{
+										ConditionType: "if",
+										Condition:     "{ path_beg /admin } || { path_beg /metrics }",
+									},
+									Realm:    "admin",
+									Userlist: corev1.LocalObjectReference{Name: "admins"},
+									Groups:   []string{"ops"},
+								},
+								{
+									Rule: configv1alpha1.Rule{
+										ConditionType: "unless",
+										Condition:     "{ path /healthz }",
+									},
+									Userlist: corev1.LocalObjectReference{Name: "users"},
+								},
+							},
+						},
+					},
+				},
+			}
+			Ω(frontend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(Equal("\nfrontend foo\n" +
+				"  http-request auth realm admin if { path_beg /admin } !{ http_auth_group(admins) ops } || { path_beg /metrics } !{ http_auth_group(admins) ops }\n" +
+				"  http-request auth unless { path /healthz } || { http_auth(users) }\n" +
+				"  http-request set-path /\n"))
+		})
+
+		It("should split the traffic between backends", func() {
+			frontend := &configv1alpha1.Frontend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.FrontendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{Mode: "http"},
+					TrafficSplit: &configv1alpha1.TrafficSplit{
+						Backends: []configv1alpha1.WeightedBackend{{Name: "stable", Weight: 90}, {Name: "canary", Weight: 10}},
+						Pinning:  &configv1alpha1.TrafficSplitPinning{Cookie: "session"},
+					},
+				},
+			}
+			Ω(frontend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(Equal("\nfrontend foo\n  mode http\n" +
+				"  http-request set-var(txn.traffic_split) rand(100)\n" +
+				"  http-request set-var(txn.traffic_split) req.cook(session),crc32,mod(100) if { req.cook(session) -m found }\n" +
+				"  use_backend %[var(txn.traffic_split),map_int(/usr/local/etc/haproxy/foo-traffic-split.map)]\n"))
+		})
+
+		It("should only split http traffic", func() {
+			frontend := &configv1alpha1.Frontend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.FrontendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{Mode: "tcp"},
+					TrafficSplit: &configv1alpha1.TrafficSplit{
+						Backends: []configv1alpha1.WeightedBackend{{Name: "stable", Weight: 100}},
+					},
+				},
+			}
+			Ω(frontend.AddToParser(p)).Should(MatchError("traffic split is only supported in http mode"))
+		})
+
+		It("should verify client certificates and forward their details", func() {
+			frontend := &configv1alpha1.Frontend{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.FrontendSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						Mode: "http",
+						HTTPRequest: &configv1alpha1.HTTPRequestRules{
+							SetPath: []configv1alpha1.HTTPPathRule{{Value: "/"}},
+						},
+					},
+					Binds: []configv1alpha1.Bind{{
+						Name: "https",
+						Port: 443,
+						SSL: &configv1alpha1.SSL{
+							Enabled:       true,
+							Certificate:   &configv1alpha1.SSLCertificate{Name: "web"},
+							CACertificate: &configv1alpha1.SSLCertificate{Name: "clients-ca"},
+							MutualTLS: &configv1alpha1.MutualTLS{
+								Verify:              "optional",
+								CAVerifyCertificate: &configv1alpha1.SSLCertificate{Name: "clients-intermediate"},
+								CRL:                 &configv1alpha1.SSLCertificate{Name: "clients-crl"},
+								IgnoreErrors:        &configv1alpha1.VerifyIgnoreErrors{CA: []string{"10", "12"}, Certificate: []string{"all"}},
+								ForwardHeaders: []configv1alpha1.ClientCertificateHeader{
+									{Name: "X-SSL-Client-DN", Field: configv1alpha1.ClientCertificateFieldSubjectDN},
+									{Name: "X-SSL-Client-SHA1", Field: configv1alpha1.ClientCertificateFieldSHA1},
+								},
+								Action: &configv1alpha1.MutualTLSAction{
+									Redirect: &configv1alpha1.MutualTLSRedirect{Location: "https://example.com/certificates", Code: pointer.Int64(303)},
+								},
+							},
+						},
+					}},
+				},
+			}
+			Ω(frontend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(Equal("\nfrontend foo\n  mode http\n" +
+				"  bind :443 name https crt /usr/local/etc/haproxy/web.crt ca-file /usr/local/etc/haproxy/clients-ca.crt ssl verify optional ca-ignore-err 10,12 crl-file /usr/local/etc/haproxy/clients-crl.crt crt-ignore-err all ca-verify-file /usr/local/etc/haproxy/clients-intermediate.crt\n" +
+				"  http-request redirect location https://example.com/certificates code 303 if { so_name https } !{ ssl_c_used } || { so_name https } !{ ssl_c_verify 0 }\n" +
+				"  http-request del-header X-SSL-Client-DN\n" +
+				"  http-request del-header X-SSL-Client-SHA1\n" +
+				"  http-request set-header X-SSL-Client-DN %[ssl_c_s_dn] if { so_name https } { ssl_c_used }\n" +
+				"  http-request set-header X-SSL-Client-SHA1 %[ssl_c_sha1,hex] if { so_name https } { ssl_c_used }\n" +
+				"  http-request set-path /\n"))
+		})
+
+		It("should require a CA certificate for mutual TLS", func() {
+			bind := configv1alpha1.Bind{
+				Name: "https",
+				Port: 443,
+				SSL: &configv1alpha1.SSL{
+					Enabled:     true,
+					Certificate: &configv1alpha1.SSLCertificate{Name: "web"},
+					MutualTLS:   &configv1alpha1.MutualTLS{},
+				},
+			}
+			_, err := bind.Model()
+			Ω(err).Should(MatchError("bind https: mutual TLS requires a CA certificate"))
+		})
+	})
+
+	Context("TrafficSplit", func() {
+		It("should distribute the slots by weight", func() {
+			split := &configv1alpha1.TrafficSplit{
+				Backends: []configv1alpha1.WeightedBackend{{Name: "a", Weight: 1}, {Name: "b", Weight: 1}, {Name: "c", Weight: 1}},
+			}
+			Ω(split.EffectiveWeights()).Should(Equal([]configv1alpha1.WeightedBackend{{Name: "a", Weight: 34}, {Name: "b", Weight: 33}, {Name: "c", Weight: 33}}))
+
+			mapping, err := split.Map()
+			Ω(err).ShouldNot(HaveOccurred())
+			lines := strings.Split(mapping, "\n")
+			Ω(lines).Should(HaveLen(100))
+			Ω(lines[0]).Should(Equal("0 a"))
+			Ω(lines[33]).Should(Equal("33 a"))
+			Ω(lines[34]).Should(Equal("34 b"))
+			Ω(lines[99]).Should(Equal("99 c"))
+		})
+
+		It("should require a weight", func() {
+			split := &configv1alpha1.TrafficSplit{
+				Backends: []configv1alpha1.WeightedBackend{{Name: "a"}},
+			}
+			_, err := split.EffectiveWeights()
+			Ω(err).Should(HaveOccurred())
+		})
 	})
 })
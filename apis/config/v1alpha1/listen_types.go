@@ -19,16 +19,49 @@ type ListenSpec struct {
 	// ServerTemplates defines the backend server templates and its configuration.
 	// +optional
 	ServerTemplates []ServerTemplate `json:"serverTemplates,omitempty"`
+	// ServiceRef discovers the backend servers from the EndpointSlices of a Service.
+	// +optional
+	ServiceRef *ServiceReference `json:"serviceRef,omitempty"`
+	// ServiceRefs discovers the backend servers from the EndpointSlices of several Services, e.g. to split the
+	// traffic between them by weight. The server names are prefixed with the name of the Service.
+	// +optional
+	ServiceRefs []ServiceReference `json:"serviceRefs,omitempty"`
 	// CheckTimeout sets an additional check timeout, but only after a connection has been already
 	// established.
 	// +optional
 	CheckTimeout *metav1.Duration `json:"checkTimeout,omitempty"`
+	// HTTPCheck enables HTTP health checks of the servers.
+	// +optional
+	HTTPCheck *HTTPCheck `json:"httpCheck,omitempty"`
 	// Balance defines the load balancing algorithm to be used in a backend.
 	// +optional
 	Balance *Balance `json:"balance,omitempty"`
 	// Redispatch enable or disable session redistribution in case of connection failure
 	// +optional
 	Redispatch *bool `json:"redispatch,omitempty"`
+	// RedispatchInterval redispatches the session to another server on every Nth retry, or only on the Nth last
+	// retry if negative. Defaults to 3.
+	// +optional
+	RedispatchInterval *int64 `json:"redispatchInterval,omitempty"`
+	// Retries is the number of retries after a connection failure to a server.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retries *int64 `json:"retries,omitempty"`
+	// RetryOn lists the failures triggering a retry. Defaults to conn-failure.
+	// +optional
+	RetryOn []RetryOnCondition `json:"retryOn,omitempty"`
+	// HTTPReuse declares how idle connections to the servers are shared between requests.
+	// +kubebuilder:validation:Enum=never;safe;aggressive;always
+	// +optional
+	HTTPReuse string `json:"httpReuse,omitempty"`
+	// HTTPRestrictReqHdrNames declares how request header names containing characters other than letters, digits
+	// and hyphens are handled.
+	// +kubebuilder:validation:Enum=preserve;delete;reject
+	// +optional
+	HTTPRestrictReqHdrNames string `json:"httpRestrictReqHdrNames,omitempty"`
+	// HTTPBufferRequest waits for the request body before the server is selected, e.g. to balance on its content.
+	// +optional
+	HTTPBufferRequest *bool `json:"httpBufferRequest,omitempty"`
 	// HashType Specify a method to use for mapping hashes to servers
 	// +optional
 	HashType *HashType `json:"hashType,omitempty"`
@@ -91,19 +124,32 @@ func (l *Listen) ToBackend() *Backend {
 		TypeMeta:   l.TypeMeta,
 		ObjectMeta: l.ObjectMeta,
 		Spec: BackendSpec{
-			BaseSpec:        l.Spec.BaseSpec,
-			CheckTimeout:    l.Spec.CheckTimeout,
-			Servers:         l.Spec.Servers,
-			ServerTemplates: l.Spec.ServerTemplates,
-			Balance:         l.Spec.Balance,
-			Redispatch:      l.Spec.Redispatch,
-			HashType:        l.Spec.HashType,
-			Cookie:          l.Spec.Cookie,
-			HostCertificate: l.Spec.HostCertificate,
+			BaseSpec:                l.Spec.BaseSpec,
+			CheckTimeout:            l.Spec.CheckTimeout,
+			HTTPCheck:               l.Spec.HTTPCheck,
+			Servers:                 l.Spec.Servers,
+			ServerTemplates:         l.Spec.ServerTemplates,
+			ServiceRef:              l.Spec.ServiceRef,
+			ServiceRefs:             l.Spec.ServiceRefs,
+			Balance:                 l.Spec.Balance,
+			Redispatch:              l.Spec.Redispatch,
+			RedispatchInterval:      l.Spec.RedispatchInterval,
+			Retries:                 l.Spec.Retries,
+			RetryOn:                 l.Spec.RetryOn,
+			HTTPReuse:               l.Spec.HTTPReuse,
+			HTTPRestrictReqHdrNames: l.Spec.HTTPRestrictReqHdrNames,
+			HTTPBufferRequest:       l.Spec.HTTPBufferRequest,
+			HashType:                l.Spec.HashType,
+			Cookie:                  l.Spec.Cookie,
+			HostCertificate:         l.Spec.HostCertificate,
 		},
 	}
 
 	delete(backend.Spec.Timeouts, "client")
+	// the rate limit and the response rules are applied by the frontend
+	backend.Spec.RateLimit = nil
+	backend.Spec.HTTPResponse = nil
+	backend.Spec.HTTPAfterResponse = nil
 
 	return &backend
 }
@@ -445,5 +445,42 @@ var _ = Describe("Listen", Label("type"), func() {
 			Ω(listen.AddToParser(p)).ShouldNot(HaveOccurred())
 			Ω(p.String()).Should(ContainSubstring("http-request return status 200 content-type text/plain lf-string \"Hello World\"\n"))
 		})
+		It("should limit connections in the frontend", func() {
+			listen := &configv1alpha1.Listen{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.ListenSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						Mode: "tcp",
+						RateLimit: &configv1alpha1.RateLimit{
+							StickTable: configv1alpha1.StickTable{Type: "ip", Size: 1000, Store: []string{"conn_cur"}},
+							Thresholds: []configv1alpha1.RateLimitThreshold{{Counter: "conn_cur", Limit: 10}},
+							Action:     "silent-drop",
+						},
+					},
+				},
+			}
+			Ω(listen.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(Equal("\nfrontend foo\n  mode tcp\n" +
+				"  tcp-request connection track-sc0 src\n" +
+				"  tcp-request connection silent-drop if { sc_conn_cur(0) gt 10 }\n" +
+				"  default_backend foo\n" +
+				"  stick-table type ip size 1000 store conn_cur\n\nbackend foo\n  mode tcp\n"))
+		})
+		It("should not support tarpit in mode tcp", func() {
+			listen := &configv1alpha1.Listen{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: configv1alpha1.ListenSpec{
+					BaseSpec: configv1alpha1.BaseSpec{
+						Mode: "tcp",
+						RateLimit: &configv1alpha1.RateLimit{
+							StickTable: configv1alpha1.StickTable{Type: "ip", Size: 1000, Store: []string{"conn_cur"}},
+							Thresholds: []configv1alpha1.RateLimitThreshold{{Counter: "conn_cur", Limit: 10}},
+							Action:     "tarpit",
+						},
+					},
+				},
+			}
+			Ω(listen.AddToParser(p)).Should(MatchError("rate limit action tarpit is not supported in mode tcp"))
+		})
 	})
 })
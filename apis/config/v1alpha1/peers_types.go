@@ -0,0 +1,105 @@
+package v1alpha1
+
+import (
+	"github.com/go-openapi/strfmt"
+	"github.com/haproxytech/client-native/v4/configuration"
+	"github.com/haproxytech/client-native/v4/models"
+	parser "github.com/haproxytech/config-parser/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// PeersSpec defines the desired state of Peers
+type PeersSpec struct {
+	// Port on which the replicas of the instance exchange the entries of the stick tables referencing these peers.
+	// Every replica is added as peer using its DNS name in the headless peers Service of the instance.
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10000
+	// +optional
+	Port int64 `json:"port,omitempty"`
+	// Peers defines additional remote peers, e.g. HAProxy instances outside of the cluster.
+	// +optional
+	Peers []Peer `json:"peers,omitempty"`
+	// Replicas of the instance, resolved by the controller before the configuration is rendered.
+	Replicas []Peer `json:"-"`
+}
+
+type Peer struct {
+	// Name of the peer. It must match the hostname or the local peer name of the remote HAProxy.
+	// +kubebuilder:validation:Pattern="^[A-Za-z0-9-_.:]+$"
+	Name string `json:"name"`
+	// Address of the peer.
+	// +kubebuilder:validation:Pattern=^[^\s]+$
+	Address string `json:"address"`
+	// Port of the peer.
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:Minimum=1
+	Port int64 `json:"port"`
+}
+
+func (p *Peer) Model() (models.PeerEntry, error) {
+	model := models.PeerEntry{
+		Name:    p.Name,
+		Address: pointer.String(p.Address),
+		Port:    pointer.Int64(p.Port),
+	}
+
+	return model, model.Validate(strfmt.Default)
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name=Port,type=integer,JSONPath=`.spec.port`
+//+kubebuilder:printcolumn:name=Phase,type=string,JSONPath=`.status.phase`
+
+// Peers is the Schema for the Peers API
+type Peers struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PeersSpec `json:"spec,omitempty"`
+	Status Status    `json:"status,omitempty"`
+}
+
+var _ Object = &Peers{}
+
+func (p *Peers) SetStatus(status Status) {
+	p.Status = status
+}
+
+func (p *Peers) GetStatus() Status {
+	return p.Status
+}
+
+func (p *Peers) AddToParser(ps parser.Parser) error {
+	if err := ps.SectionsCreate(parser.Peers, p.Name); err != nil {
+		return err
+	}
+
+	for idx, peer := range append(append([]Peer{}, p.Spec.Replicas...), p.Spec.Peers...) {
+		model, err := peer.Model()
+		if err != nil {
+			return err
+		}
+
+		if err := ps.Insert(parser.Peers, p.Name, "peer", configuration.SerializePeerEntry(model), idx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//+kubebuilder:object:root=true
+
+// PeersList contains a list of Peers
+type PeersList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Peers `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Peers{}, &PeersList{})
+}
@@ -0,0 +1,131 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/haproxytech/client-native/v4/configuration"
+	"github.com/haproxytech/client-native/v4/models"
+	parser "github.com/haproxytech/config-parser/v4"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// UserlistSpec defines the desired state of Userlist
+type UserlistSpec struct {
+	// Groups declares the groups the users can be assigned to.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+	// Users with their credentials.
+	// +optional
+	Users []User `json:"users,omitempty"`
+}
+
+type User struct {
+	// Name of the user.
+	// +kubebuilder:validation:Pattern="^[A-Za-z0-9-_.:]+$"
+	Name string `json:"name"`
+	// Password selects the key of a Secret in the namespace of the instance holding the password. The password must
+	// be hashed with crypt(3), e.g. using 'mkpasswd -m sha-512', unless Insecure is set.
+	Password corev1.SecretKeySelector `json:"password"`
+	// Insecure marks the password as plain text.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+	// Groups the user belongs to.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+	// PasswordValue is resolved from the Secret by the controller before the configuration is rendered.
+	PasswordValue string `json:"-"`
+}
+
+func (u *User) Model() (models.User, error) {
+	model := models.User{
+		Username:       u.Name,
+		Password:       u.PasswordValue,
+		SecurePassword: pointer.Bool(!u.Insecure),
+		Groups:         strings.Join(u.Groups, ","),
+	}
+
+	// the password is not resolved when the object is validated on admission
+	if model.Password == "" {
+		return model, nil
+	}
+
+	return model, model.Validate(strfmt.Default)
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name=Phase,type=string,JSONPath=`.status.phase`
+
+// Userlist is the Schema for the Userlist API
+type Userlist struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserlistSpec `json:"spec,omitempty"`
+	Status Status       `json:"status,omitempty"`
+}
+
+var _ Object = &Userlist{}
+
+func (u *Userlist) SetStatus(status Status) {
+	u.Status = status
+}
+
+func (u *Userlist) GetStatus() Status {
+	return u.Status
+}
+
+func (u *Userlist) AddToParser(p parser.Parser) error {
+	if err := p.SectionsCreate(parser.UserList, u.Name); err != nil {
+		return err
+	}
+
+	groups := map[string]bool{}
+	for idx, group := range u.Spec.Groups {
+		model := models.Group{Name: group}
+		if err := model.Validate(strfmt.Default); err != nil {
+			return err
+		}
+		groups[group] = true
+
+		if err := p.Insert(parser.UserList, u.Name, "group", configuration.SerializeGroup(model), idx); err != nil {
+			return err
+		}
+	}
+
+	for idx, user := range u.Spec.Users {
+		for _, group := range user.Groups {
+			if !groups[group] {
+				return fmt.Errorf("group %s of user %s is not declared", group, user.Name)
+			}
+		}
+
+		model, err := user.Model()
+		if err != nil {
+			return err
+		}
+
+		if err := p.Insert(parser.UserList, u.Name, "user", configuration.SerializeUser(model), idx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//+kubebuilder:object:root=true
+
+// UserlistList contains a list of Userlist
+type UserlistList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Userlist `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Userlist{}, &UserlistList{})
+}
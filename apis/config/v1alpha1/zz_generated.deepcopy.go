@@ -23,6 +23,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -47,13 +48,33 @@ func (in *ACL) DeepCopy() *ACL {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentCheck) DeepCopyInto(out *AgentCheck) {
+	*out = *in
+	if in.Inter != nil {
+		in, out := &in.Inter, &out.Inter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentCheck.
+func (in *AgentCheck) DeepCopy() *AgentCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Backend) DeepCopyInto(out *Backend) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backend.
@@ -140,6 +161,11 @@ func (in *BackendSpec) DeepCopyInto(out *BackendSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.HTTPCheck != nil {
+		in, out := &in.HTTPCheck, &out.HTTPCheck
+		*out = new(HTTPCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Servers != nil {
 		in, out := &in.Servers, &out.Servers
 		*out = make([]Server, len(*in))
@@ -154,6 +180,18 @@ func (in *BackendSpec) DeepCopyInto(out *BackendSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ServiceRef != nil {
+		in, out := &in.ServiceRef, &out.ServiceRef
+		*out = new(ServiceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceRefs != nil {
+		in, out := &in.ServiceRefs, &out.ServiceRefs
+		*out = make([]ServiceReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Balance != nil {
 		in, out := &in.Balance, &out.Balance
 		*out = new(Balance)
@@ -169,6 +207,26 @@ func (in *BackendSpec) DeepCopyInto(out *BackendSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.RedispatchInterval != nil {
+		in, out := &in.RedispatchInterval, &out.RedispatchInterval
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RetryOn != nil {
+		in, out := &in.RetryOn, &out.RetryOn
+		*out = make([]RetryOnCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.HTTPBufferRequest != nil {
+		in, out := &in.HTTPBufferRequest, &out.HTTPBufferRequest
+		*out = new(bool)
+		**out = **in
+	}
 	if in.HashType != nil {
 		in, out := &in.HashType, &out.HashType
 		*out = new(HashType)
@@ -179,6 +237,11 @@ func (in *BackendSpec) DeepCopyInto(out *BackendSpec) {
 		*out = new(Cookie)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StickTable != nil {
+		in, out := &in.StickTable, &out.StickTable
+		*out = new(StickTable)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSpec.
@@ -273,6 +336,21 @@ func (in *BaseSpec) DeepCopyInto(out *BaseSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPResponse != nil {
+		in, out := &in.HTTPResponse, &out.HTTPResponse
+		*out = new(HTTPResponseRules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPAfterResponse != nil {
+		in, out := &in.HTTPAfterResponse, &out.HTTPAfterResponse
+		*out = new(HTTPAfterResponseRules)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseSpec.
@@ -373,6 +451,42 @@ func (in *CertificateListElement) DeepCopy() *CertificateListElement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRef) DeepCopyInto(out *CertificateRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRef.
+func (in *CertificateRef) DeepCopy() *CertificateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	if in.SANs != nil {
+		in, out := &in.SANs, &out.SANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Check) DeepCopyInto(out *Check) {
 	*out = *in
@@ -391,6 +505,21 @@ func (in *Check) DeepCopyInto(out *Check) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Fastinter != nil {
+		in, out := &in.Fastinter, &out.Fastinter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Downinter != nil {
+		in, out := &in.Downinter, &out.Downinter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Check.
@@ -403,6 +532,21 @@ func (in *Check) DeepCopy() *Check {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateHeader) DeepCopyInto(out *ClientCertificateHeader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateHeader.
+func (in *ClientCertificateHeader) DeepCopy() *ClientCertificateHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cookie) DeepCopyInto(out *Cookie) {
 	*out = *in
@@ -552,7 +696,7 @@ func (in *Frontend) DeepCopyInto(out *Frontend) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Frontend.
@@ -624,6 +768,16 @@ func (in *FrontendSpec) DeepCopyInto(out *FrontendSpec) {
 		}
 	}
 	out.DefaultBackend = in.DefaultBackend
+	if in.StickTable != nil {
+		in, out := &in.StickTable, &out.StickTable
+		*out = new(StickTable)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrafficSplit != nil {
+		in, out := &in.TrafficSplit, &out.TrafficSplit
+		*out = new(TrafficSplit)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendSpec.
@@ -637,250 +791,1049 @@ func (in *FrontendSpec) DeepCopy() *FrontendSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPHeaderRule) DeepCopyInto(out *HTTPHeaderRule) {
+func (in *FrontendStatus) DeepCopyInto(out *FrontendStatus) {
 	*out = *in
-	out.Rule = in.Rule
-	in.Value.DeepCopyInto(&out.Value)
+	in.Status.DeepCopyInto(&out.Status)
+	if in.TrafficSplit != nil {
+		in, out := &in.TrafficSplit, &out.TrafficSplit
+		*out = make([]WeightedBackend, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeaderRule.
-func (in *HTTPHeaderRule) DeepCopy() *HTTPHeaderRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendStatus.
+func (in *FrontendStatus) DeepCopy() *FrontendStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPHeaderRule)
+	out := new(FrontendStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPHeaderValue) DeepCopyInto(out *HTTPHeaderValue) {
+func (in *HTTPAfterResponseRules) DeepCopyInto(out *HTTPAfterResponseRules) {
 	*out = *in
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = new(corev1.EnvVar)
-		(*in).DeepCopyInto(*out)
+	if in.SetVar != nil {
+		in, out := &in.SetVar, &out.SetVar
+		*out = make([]HTTPSetVarRule, len(*in))
+		copy(*out, *in)
 	}
-	if in.Str != nil {
-		in, out := &in.Str, &out.Str
-		*out = new(string)
-		**out = **in
+	if in.DelHeader != nil {
+		in, out := &in.DelHeader, &out.DelHeader
+		*out = make([]HTTPDelHeaderRule, len(*in))
+		copy(*out, *in)
 	}
-	if in.Format != nil {
-		in, out := &in.Format, &out.Format
-		*out = new(string)
-		**out = **in
+	if in.SetHeader != nil {
+		in, out := &in.SetHeader, &out.SetHeader
+		*out = make([]HTTPHeaderRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AddHeader != nil {
+		in, out := &in.AddHeader, &out.AddHeader
+		*out = make([]HTTPHeaderRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReplaceHeader != nil {
+		in, out := &in.ReplaceHeader, &out.ReplaceHeader
+		*out = make([]HTTPReplaceHeaderRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReplaceValue != nil {
+		in, out := &in.ReplaceValue, &out.ReplaceValue
+		*out = make([]HTTPReplaceHeaderRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.SetStatus != nil {
+		in, out := &in.SetStatus, &out.SetStatus
+		*out = make([]HTTPSetStatusRule, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeaderValue.
-func (in *HTTPHeaderValue) DeepCopy() *HTTPHeaderValue {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPAfterResponseRules.
+func (in *HTTPAfterResponseRules) DeepCopy() *HTTPAfterResponseRules {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPHeaderValue)
+	out := new(HTTPAfterResponseRules)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPPathRule) DeepCopyInto(out *HTTPPathRule) {
+func (in *HTTPAuthRule) DeepCopyInto(out *HTTPAuthRule) {
 	*out = *in
 	out.Rule = in.Rule
+	out.Userlist = in.Userlist
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPPathRule.
-func (in *HTTPPathRule) DeepCopy() *HTTPPathRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPAuthRule.
+func (in *HTTPAuthRule) DeepCopy() *HTTPAuthRule {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPPathRule)
+	out := new(HTTPAuthRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPPretendKeepalive) DeepCopyInto(out *HTTPPretendKeepalive) {
+func (in *HTTPCaptureRule) DeepCopyInto(out *HTTPCaptureRule) {
 	*out = *in
+	out.Rule = in.Rule
+	if in.Length != nil {
+		in, out := &in.Length, &out.Length
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int64)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPPretendKeepalive.
-func (in *HTTPPretendKeepalive) DeepCopy() *HTTPPretendKeepalive {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCaptureRule.
+func (in *HTTPCaptureRule) DeepCopy() *HTTPCaptureRule {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPPretendKeepalive)
+	out := new(HTTPCaptureRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPRequestRules) DeepCopyInto(out *HTTPRequestRules) {
+func (in *HTTPCheck) DeepCopyInto(out *HTTPCheck) {
 	*out = *in
-	if in.SetHeader != nil {
-		in, out := &in.SetHeader, &out.SetHeader
-		*out = make([]HTTPHeaderRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Connect != nil {
+		in, out := &in.Connect, &out.Connect
+		*out = new(HTTPCheckConnect)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.SetPath != nil {
-		in, out := &in.SetPath, &out.SetPath
-		*out = make([]HTTPPathRule, len(*in))
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HTTPCheckHeader, len(*in))
 		copy(*out, *in)
 	}
-	if in.AddHeader != nil {
-		in, out := &in.AddHeader, &out.AddHeader
-		*out = make([]HTTPHeaderRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Redirect != nil {
-		in, out := &in.Redirect, &out.Redirect
-		*out = make([]Redirect, len(*in))
+	if in.Expect != nil {
+		in, out := &in.Expect, &out.Expect
+		*out = make([]HTTPCheckExpect, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Deny != nil {
-		in, out := &in.Deny, &out.Deny
-		*out = new(Deny)
-		**out = **in
-	}
-	if in.DenyStatus != nil {
-		in, out := &in.DenyStatus, &out.DenyStatus
-		*out = new(int64)
-		**out = **in
-	}
-	if in.Return != nil {
-		in, out := &in.Return, &out.Return
-		*out = new(HTTPReturn)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRequestRules.
-func (in *HTTPRequestRules) DeepCopy() *HTTPRequestRules {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheck.
+func (in *HTTPCheck) DeepCopy() *HTTPCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPRequestRules)
+	out := new(HTTPCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPReturn) DeepCopyInto(out *HTTPReturn) {
+func (in *HTTPCheckConnect) DeepCopyInto(out *HTTPCheckConnect) {
 	*out = *in
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
 		*out = new(int64)
 		**out = **in
 	}
-	out.Content = in.Content
+	if in.ALPN != nil {
+		in, out := &in.ALPN, &out.ALPN
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPReturn.
-func (in *HTTPReturn) DeepCopy() *HTTPReturn {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheckConnect.
+func (in *HTTPCheckConnect) DeepCopy() *HTTPCheckConnect {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPReturn)
+	out := new(HTTPCheckConnect)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPReturnContent) DeepCopyInto(out *HTTPReturnContent) {
+func (in *HTTPCheckExpect) DeepCopyInto(out *HTTPCheckExpect) {
 	*out = *in
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(HTTPCheckExpectHeader)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPReturnContent.
-func (in *HTTPReturnContent) DeepCopy() *HTTPReturnContent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheckExpect.
+func (in *HTTPCheckExpect) DeepCopy() *HTTPCheckExpect {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPReturnContent)
+	out := new(HTTPCheckExpect)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HashType) DeepCopyInto(out *HashType) {
+func (in *HTTPCheckExpectHeader) DeepCopyInto(out *HTTPCheckExpectHeader) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashType.
-func (in *HashType) DeepCopy() *HashType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheckExpectHeader.
+func (in *HTTPCheckExpectHeader) DeepCopy() *HTTPCheckExpectHeader {
 	if in == nil {
 		return nil
 	}
-	out := new(HashType)
+	out := new(HTTPCheckExpectHeader)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Hold) DeepCopyInto(out *Hold) {
+func (in *HTTPCheckHeader) DeepCopyInto(out *HTTPCheckHeader) {
 	*out = *in
-	if in.Nx != nil {
-		in, out := &in.Nx, &out.Nx
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.Obsolete != nil {
-		in, out := &in.Obsolete, &out.Obsolete
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.Other != nil {
-		in, out := &in.Other, &out.Other
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.Refused != nil {
-		in, out := &in.Refused, &out.Refused
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.Timeout != nil {
-		in, out := &in.Timeout, &out.Timeout
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.Valid != nil {
-		in, out := &in.Valid, &out.Valid
-		*out = new(v1.Duration)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hold.
-func (in *Hold) DeepCopy() *Hold {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheckHeader.
+func (in *HTTPCheckHeader) DeepCopy() *HTTPCheckHeader {
 	if in == nil {
 		return nil
 	}
-	out := new(Hold)
+	out := new(HTTPCheckHeader)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Listen) DeepCopyInto(out *Listen) {
+func (in *HTTPDelHeaderRule) DeepCopyInto(out *HTTPDelHeaderRule) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	out.Rule = in.Rule
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Listen.
-func (in *Listen) DeepCopy() *Listen {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPDelHeaderRule.
+func (in *HTTPDelHeaderRule) DeepCopy() *HTTPDelHeaderRule {
 	if in == nil {
 		return nil
 	}
-	out := new(Listen)
+	out := new(HTTPDelHeaderRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPDenyRule) DeepCopyInto(out *HTTPDenyRule) {
+	*out = *in
+	out.Rule = in.Rule
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPDenyRule.
+func (in *HTTPDenyRule) DeepCopy() *HTTPDenyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPDenyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPExpressionRule) DeepCopyInto(out *HTTPExpressionRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPExpressionRule.
+func (in *HTTPExpressionRule) DeepCopy() *HTTPExpressionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPExpressionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPFormatRule) DeepCopyInto(out *HTTPFormatRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPFormatRule.
+func (in *HTTPFormatRule) DeepCopy() *HTTPFormatRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPFormatRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHeaderRule) DeepCopyInto(out *HTTPHeaderRule) {
+	*out = *in
+	out.Rule = in.Rule
+	in.Value.DeepCopyInto(&out.Value)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeaderRule.
+func (in *HTTPHeaderRule) DeepCopy() *HTTPHeaderRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHeaderRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHeaderValue) DeepCopyInto(out *HTTPHeaderValue) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = new(corev1.EnvVar)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Str != nil {
+		in, out := &in.Str, &out.Str
+		*out = new(string)
+		**out = **in
+	}
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeaderValue.
+func (in *HTTPHeaderValue) DeepCopy() *HTTPHeaderValue {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHeaderValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPNameRule) DeepCopyInto(out *HTTPNameRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPNameRule.
+func (in *HTTPNameRule) DeepCopy() *HTTPNameRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPNameRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPNormalizeURIRule) DeepCopyInto(out *HTTPNormalizeURIRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPNormalizeURIRule.
+func (in *HTTPNormalizeURIRule) DeepCopy() *HTTPNormalizeURIRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPNormalizeURIRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPPathRule) DeepCopyInto(out *HTTPPathRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPPathRule.
+func (in *HTTPPathRule) DeepCopy() *HTTPPathRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPPathRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPPretendKeepalive) DeepCopyInto(out *HTTPPretendKeepalive) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPPretendKeepalive.
+func (in *HTTPPretendKeepalive) DeepCopy() *HTTPPretendKeepalive {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPPretendKeepalive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPReplaceHeaderRule) DeepCopyInto(out *HTTPReplaceHeaderRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPReplaceHeaderRule.
+func (in *HTTPReplaceHeaderRule) DeepCopy() *HTTPReplaceHeaderRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPReplaceHeaderRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPReplaceRule) DeepCopyInto(out *HTTPReplaceRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPReplaceRule.
+func (in *HTTPReplaceRule) DeepCopy() *HTTPReplaceRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPReplaceRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRequestRule) DeepCopyInto(out *HTTPRequestRule) {
+	*out = *in
+	if in.SetHeader != nil {
+		in, out := &in.SetHeader, &out.SetHeader
+		*out = new(HTTPHeaderRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AddHeader != nil {
+		in, out := &in.AddHeader, &out.AddHeader
+		*out = new(HTTPHeaderRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DelHeader != nil {
+		in, out := &in.DelHeader, &out.DelHeader
+		*out = new(HTTPDelHeaderRule)
+		**out = **in
+	}
+	if in.ReplaceHeader != nil {
+		in, out := &in.ReplaceHeader, &out.ReplaceHeader
+		*out = new(HTTPReplaceHeaderRule)
+		**out = **in
+	}
+	if in.SetPath != nil {
+		in, out := &in.SetPath, &out.SetPath
+		*out = new(HTTPPathRule)
+		**out = **in
+	}
+	if in.ReplacePath != nil {
+		in, out := &in.ReplacePath, &out.ReplacePath
+		*out = new(HTTPReplaceRule)
+		**out = **in
+	}
+	if in.ReplaceURI != nil {
+		in, out := &in.ReplaceURI, &out.ReplaceURI
+		*out = new(HTTPReplaceRule)
+		**out = **in
+	}
+	if in.SetQuery != nil {
+		in, out := &in.SetQuery, &out.SetQuery
+		*out = new(HTTPFormatRule)
+		**out = **in
+	}
+	if in.SetURI != nil {
+		in, out := &in.SetURI, &out.SetURI
+		*out = new(HTTPFormatRule)
+		**out = **in
+	}
+	if in.SetMethod != nil {
+		in, out := &in.SetMethod, &out.SetMethod
+		*out = new(HTTPFormatRule)
+		**out = **in
+	}
+	if in.SetVar != nil {
+		in, out := &in.SetVar, &out.SetVar
+		*out = new(HTTPSetVarRule)
+		**out = **in
+	}
+	if in.UnsetVar != nil {
+		in, out := &in.UnsetVar, &out.UnsetVar
+		*out = new(HTTPUnsetVarRule)
+		**out = **in
+	}
+	if in.SetLogLevel != nil {
+		in, out := &in.SetLogLevel, &out.SetLogLevel
+		*out = new(HTTPSetLogLevelRule)
+		**out = **in
+	}
+	if in.Capture != nil {
+		in, out := &in.Capture, &out.Capture
+		*out = new(HTTPCaptureRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SetSrc != nil {
+		in, out := &in.SetSrc, &out.SetSrc
+		*out = new(HTTPExpressionRule)
+		**out = **in
+	}
+	if in.NormalizeURI != nil {
+		in, out := &in.NormalizeURI, &out.NormalizeURI
+		*out = new(HTTPNormalizeURIRule)
+		**out = **in
+	}
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(Redirect)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = new(HTTPDenyRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tarpit != nil {
+		in, out := &in.Tarpit, &out.Tarpit
+		*out = new(HTTPDenyRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SilentDrop != nil {
+		in, out := &in.SilentDrop, &out.SilentDrop
+		*out = new(Rule)
+		**out = **in
+	}
+	if in.UseService != nil {
+		in, out := &in.UseService, &out.UseService
+		*out = new(HTTPNameRule)
+		**out = **in
+	}
+	if in.CacheUse != nil {
+		in, out := &in.CacheUse, &out.CacheUse
+		*out = new(HTTPNameRule)
+		**out = **in
+	}
+	if in.Return != nil {
+		in, out := &in.Return, &out.Return
+		*out = new(HTTPReturnRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(HTTPAuthRule)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRequestRule.
+func (in *HTTPRequestRule) DeepCopy() *HTTPRequestRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRequestRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRequestRules) DeepCopyInto(out *HTTPRequestRules) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]HTTPRequestRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SetHeader != nil {
+		in, out := &in.SetHeader, &out.SetHeader
+		*out = make([]HTTPHeaderRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SetPath != nil {
+		in, out := &in.SetPath, &out.SetPath
+		*out = make([]HTTPPathRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.AddHeader != nil {
+		in, out := &in.AddHeader, &out.AddHeader
+		*out = make([]HTTPHeaderRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DelHeader != nil {
+		in, out := &in.DelHeader, &out.DelHeader
+		*out = make([]HTTPDelHeaderRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReplaceHeader != nil {
+		in, out := &in.ReplaceHeader, &out.ReplaceHeader
+		*out = make([]HTTPReplaceHeaderRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReplacePath != nil {
+		in, out := &in.ReplacePath, &out.ReplacePath
+		*out = make([]HTTPReplaceRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReplaceURI != nil {
+		in, out := &in.ReplaceURI, &out.ReplaceURI
+		*out = make([]HTTPReplaceRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.SetQuery != nil {
+		in, out := &in.SetQuery, &out.SetQuery
+		*out = make([]HTTPFormatRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.SetURI != nil {
+		in, out := &in.SetURI, &out.SetURI
+		*out = make([]HTTPFormatRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.SetMethod != nil {
+		in, out := &in.SetMethod, &out.SetMethod
+		*out = make([]HTTPFormatRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.SetVar != nil {
+		in, out := &in.SetVar, &out.SetVar
+		*out = make([]HTTPSetVarRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnsetVar != nil {
+		in, out := &in.UnsetVar, &out.UnsetVar
+		*out = make([]HTTPUnsetVarRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.SetLogLevel != nil {
+		in, out := &in.SetLogLevel, &out.SetLogLevel
+		*out = make([]HTTPSetLogLevelRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Capture != nil {
+		in, out := &in.Capture, &out.Capture
+		*out = make([]HTTPCaptureRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SetSrc != nil {
+		in, out := &in.SetSrc, &out.SetSrc
+		*out = make([]HTTPExpressionRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.NormalizeURI != nil {
+		in, out := &in.NormalizeURI, &out.NormalizeURI
+		*out = make([]HTTPNormalizeURIRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = make([]Redirect, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = new(Deny)
+		**out = **in
+	}
+	if in.DenyRules != nil {
+		in, out := &in.DenyRules, &out.DenyRules
+		*out = make([]HTTPDenyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DenyStatus != nil {
+		in, out := &in.DenyStatus, &out.DenyStatus
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Tarpit != nil {
+		in, out := &in.Tarpit, &out.Tarpit
+		*out = make([]HTTPDenyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SilentDrop != nil {
+		in, out := &in.SilentDrop, &out.SilentDrop
+		*out = make([]Rule, len(*in))
+		copy(*out, *in)
+	}
+	if in.UseService != nil {
+		in, out := &in.UseService, &out.UseService
+		*out = make([]HTTPNameRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.CacheUse != nil {
+		in, out := &in.CacheUse, &out.CacheUse
+		*out = make([]HTTPNameRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Return != nil {
+		in, out := &in.Return, &out.Return
+		*out = new(HTTPReturn)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = make([]HTTPAuthRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRequestRules.
+func (in *HTTPRequestRules) DeepCopy() *HTTPRequestRules {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRequestRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPResponseRule) DeepCopyInto(out *HTTPResponseRule) {
+	*out = *in
+	if in.SetVar != nil {
+		in, out := &in.SetVar, &out.SetVar
+		*out = new(HTTPSetVarRule)
+		**out = **in
+	}
+	if in.DelHeader != nil {
+		in, out := &in.DelHeader, &out.DelHeader
+		*out = new(HTTPDelHeaderRule)
+		**out = **in
+	}
+	if in.SetHeader != nil {
+		in, out := &in.SetHeader, &out.SetHeader
+		*out = new(HTTPHeaderRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AddHeader != nil {
+		in, out := &in.AddHeader, &out.AddHeader
+		*out = new(HTTPHeaderRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplaceHeader != nil {
+		in, out := &in.ReplaceHeader, &out.ReplaceHeader
+		*out = new(HTTPReplaceHeaderRule)
+		**out = **in
+	}
+	if in.ReplaceValue != nil {
+		in, out := &in.ReplaceValue, &out.ReplaceValue
+		*out = new(HTTPReplaceHeaderRule)
+		**out = **in
+	}
+	if in.SetStatus != nil {
+		in, out := &in.SetStatus, &out.SetStatus
+		*out = new(HTTPSetStatusRule)
+		**out = **in
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = new(HTTPDenyRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(Redirect)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Return != nil {
+		in, out := &in.Return, &out.Return
+		*out = new(HTTPReturnRule)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPResponseRule.
+func (in *HTTPResponseRule) DeepCopy() *HTTPResponseRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPResponseRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPResponseRules) DeepCopyInto(out *HTTPResponseRules) {
+	*out = *in
+	in.HTTPAfterResponseRules.DeepCopyInto(&out.HTTPAfterResponseRules)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]HTTPResponseRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = new(Deny)
+		**out = **in
+	}
+	if in.DenyStatus != nil {
+		in, out := &in.DenyStatus, &out.DenyStatus
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = make([]Redirect, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Return != nil {
+		in, out := &in.Return, &out.Return
+		*out = new(HTTPReturn)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPResponseRules.
+func (in *HTTPResponseRules) DeepCopy() *HTTPResponseRules {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPResponseRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPReturn) DeepCopyInto(out *HTTPReturn) {
+	*out = *in
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(int64)
+		**out = **in
+	}
+	out.Content = in.Content
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPReturn.
+func (in *HTTPReturn) DeepCopy() *HTTPReturn {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPReturn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPReturnContent) DeepCopyInto(out *HTTPReturnContent) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPReturnContent.
+func (in *HTTPReturnContent) DeepCopy() *HTTPReturnContent {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPReturnContent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPReturnRule) DeepCopyInto(out *HTTPReturnRule) {
+	*out = *in
+	out.Rule = in.Rule
+	in.HTTPReturn.DeepCopyInto(&out.HTTPReturn)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPReturnRule.
+func (in *HTTPReturnRule) DeepCopy() *HTTPReturnRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPReturnRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSetLogLevelRule) DeepCopyInto(out *HTTPSetLogLevelRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSetLogLevelRule.
+func (in *HTTPSetLogLevelRule) DeepCopy() *HTTPSetLogLevelRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSetLogLevelRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSetStatusRule) DeepCopyInto(out *HTTPSetStatusRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSetStatusRule.
+func (in *HTTPSetStatusRule) DeepCopy() *HTTPSetStatusRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSetStatusRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSetVarRule) DeepCopyInto(out *HTTPSetVarRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSetVarRule.
+func (in *HTTPSetVarRule) DeepCopy() *HTTPSetVarRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSetVarRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPUnsetVarRule) DeepCopyInto(out *HTTPUnsetVarRule) {
+	*out = *in
+	out.Rule = in.Rule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPUnsetVarRule.
+func (in *HTTPUnsetVarRule) DeepCopy() *HTTPUnsetVarRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPUnsetVarRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HashType) DeepCopyInto(out *HashType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashType.
+func (in *HashType) DeepCopy() *HashType {
+	if in == nil {
+		return nil
+	}
+	out := new(HashType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hold) DeepCopyInto(out *Hold) {
+	*out = *in
+	if in.Nx != nil {
+		in, out := &in.Nx, &out.Nx
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Obsolete != nil {
+		in, out := &in.Obsolete, &out.Obsolete
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Other != nil {
+		in, out := &in.Other, &out.Other
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Refused != nil {
+		in, out := &in.Refused, &out.Refused
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Valid != nil {
+		in, out := &in.Valid, &out.Valid
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hold.
+func (in *Hold) DeepCopy() *Hold {
+	if in == nil {
+		return nil
+	}
+	out := new(Hold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Listen) DeepCopyInto(out *Listen) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Listen.
+func (in *Listen) DeepCopy() *Listen {
+	if in == nil {
+		return nil
+	}
+	out := new(Listen)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -950,11 +1903,28 @@ func (in *ListenSpec) DeepCopyInto(out *ListenSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ServiceRef != nil {
+		in, out := &in.ServiceRef, &out.ServiceRef
+		*out = new(ServiceReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceRefs != nil {
+		in, out := &in.ServiceRefs, &out.ServiceRefs
+		*out = make([]ServiceReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.CheckTimeout != nil {
 		in, out := &in.CheckTimeout, &out.CheckTimeout
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.HTTPCheck != nil {
+		in, out := &in.HTTPCheck, &out.HTTPCheck
+		*out = new(HTTPCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Balance != nil {
 		in, out := &in.Balance, &out.Balance
 		*out = new(Balance)
@@ -965,6 +1935,26 @@ func (in *ListenSpec) DeepCopyInto(out *ListenSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.RedispatchInterval != nil {
+		in, out := &in.RedispatchInterval, &out.RedispatchInterval
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RetryOn != nil {
+		in, out := &in.RetryOn, &out.RetryOn
+		*out = make([]RetryOnCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.HTTPBufferRequest != nil {
+		in, out := &in.HTTPBufferRequest, &out.HTTPBufferRequest
+		*out = new(bool)
+		**out = **in
+	}
 	if in.HashType != nil {
 		in, out := &in.HashType, &out.HashType
 		*out = new(HashType)
@@ -982,27 +1972,271 @@ func (in *ListenSpec) DeepCopyInto(out *ListenSpec) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenSpec.
-func (in *ListenSpec) DeepCopy() *ListenSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenSpec.
+func (in *ListenSpec) DeepCopy() *ListenSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ListenSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MutualTLS) DeepCopyInto(out *MutualTLS) {
+	*out = *in
+	if in.CAVerifyCertificate != nil {
+		in, out := &in.CAVerifyCertificate, &out.CAVerifyCertificate
+		*out = new(SSLCertificate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CRL != nil {
+		in, out := &in.CRL, &out.CRL
+		*out = new(SSLCertificate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IgnoreErrors != nil {
+		in, out := &in.IgnoreErrors, &out.IgnoreErrors
+		*out = new(VerifyIgnoreErrors)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ForwardHeaders != nil {
+		in, out := &in.ForwardHeaders, &out.ForwardHeaders
+		*out = make([]ClientCertificateHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.Action != nil {
+		in, out := &in.Action, &out.Action
+		*out = new(MutualTLSAction)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MutualTLS.
+func (in *MutualTLS) DeepCopy() *MutualTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(MutualTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MutualTLSAction) DeepCopyInto(out *MutualTLSAction) {
+	*out = *in
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = new(MutualTLSDeny)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(MutualTLSRedirect)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MutualTLSAction.
+func (in *MutualTLSAction) DeepCopy() *MutualTLSAction {
+	if in == nil {
+		return nil
+	}
+	out := new(MutualTLSAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MutualTLSDeny) DeepCopyInto(out *MutualTLSDeny) {
+	*out = *in
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MutualTLSDeny.
+func (in *MutualTLSDeny) DeepCopy() *MutualTLSDeny {
+	if in == nil {
+		return nil
+	}
+	out := new(MutualTLSDeny)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MutualTLSRedirect) DeepCopyInto(out *MutualTLSRedirect) {
+	*out = *in
+	if in.Code != nil {
+		in, out := &in.Code, &out.Code
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MutualTLSRedirect.
+func (in *MutualTLSRedirect) DeepCopy() *MutualTLSRedirect {
+	if in == nil {
+		return nil
+	}
+	out := new(MutualTLSRedirect)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Nameserver) DeepCopyInto(out *Nameserver) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nameserver.
+func (in *Nameserver) DeepCopy() *Nameserver {
+	if in == nil {
+		return nil
+	}
+	out := new(Nameserver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCSP) DeepCopyInto(out *OCSP) {
+	*out = *in
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCSP.
+func (in *OCSP) DeepCopy() *OCSP {
+	if in == nil {
+		return nil
+	}
+	out := new(OCSP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Observe) DeepCopyInto(out *Observe) {
+	*out = *in
+	if in.ErrorLimit != nil {
+		in, out := &in.ErrorLimit, &out.ErrorLimit
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observe.
+func (in *Observe) DeepCopy() *Observe {
+	if in == nil {
+		return nil
+	}
+	out := new(Observe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Peer) DeepCopyInto(out *Peer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Peer.
+func (in *Peer) DeepCopy() *Peer {
+	if in == nil {
+		return nil
+	}
+	out := new(Peer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Peers) DeepCopyInto(out *Peers) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Peers.
+func (in *Peers) DeepCopy() *Peers {
+	if in == nil {
+		return nil
+	}
+	out := new(Peers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Peers) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeersList) DeepCopyInto(out *PeersList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Peers, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeersList.
+func (in *PeersList) DeepCopy() *PeersList {
 	if in == nil {
 		return nil
 	}
-	out := new(ListenSpec)
+	out := new(PeersList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PeersList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Nameserver) DeepCopyInto(out *Nameserver) {
+func (in *PeersSpec) DeepCopyInto(out *PeersSpec) {
 	*out = *in
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]Peer, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]Peer, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nameserver.
-func (in *Nameserver) DeepCopy() *Nameserver {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeersSpec.
+func (in *PeersSpec) DeepCopy() *PeersSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Nameserver)
+	out := new(PeersSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1062,6 +2296,47 @@ func (in *ProxyProtocolV2Options) DeepCopy() *ProxyProtocolV2Options {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+	in.StickTable.DeepCopyInto(&out.StickTable)
+	if in.Thresholds != nil {
+		in, out := &in.Thresholds, &out.Thresholds
+		*out = make([]RateLimitThreshold, len(*in))
+		copy(*out, *in)
+	}
+	if in.DenyStatus != nil {
+		in, out := &in.DenyStatus, &out.DenyStatus
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitThreshold) DeepCopyInto(out *RateLimitThreshold) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitThreshold.
+func (in *RateLimitThreshold) DeepCopy() *RateLimitThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Redirect) DeepCopyInto(out *Redirect) {
 	*out = *in
@@ -1166,7 +2441,7 @@ func (in *Resolver) DeepCopyInto(out *Resolver) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resolver.
@@ -1297,6 +2572,16 @@ func (in *SSL) DeepCopyInto(out *SSL) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.OCSP != nil {
+		in, out := &in.OCSP, &out.OCSP
+		*out = new(OCSP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MutualTLS != nil {
+		in, out := &in.MutualTLS, &out.MutualTLS
+		*out = new(MutualTLS)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSL.
@@ -1324,6 +2609,11 @@ func (in *SSLCertificate) DeepCopyInto(out *SSLCertificate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CertificateRef != nil {
+		in, out := &in.CertificateRef, &out.CertificateRef
+		*out = new(CertificateRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSLCertificate.
@@ -1415,6 +2705,41 @@ func (in *ServerParams) DeepCopyInto(out *ServerParams) {
 		*out = new(ProxyProtocol)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Maxconn != nil {
+		in, out := &in.Maxconn, &out.Maxconn
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Maxqueue != nil {
+		in, out := &in.Maxqueue, &out.Maxqueue
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Slowstart != nil {
+		in, out := &in.Slowstart, &out.Slowstart
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Observe != nil {
+		in, out := &in.Observe, &out.Observe
+		*out = new(Observe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AgentCheck != nil {
+		in, out := &in.AgentCheck, &out.AgentCheck
+		*out = new(AgentCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PoolMaxConn != nil {
+		in, out := &in.PoolMaxConn, &out.PoolMaxConn
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PoolPurgeDelay != nil {
+		in, out := &in.PoolPurgeDelay, &out.PoolPurgeDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerParams.
@@ -1448,6 +2773,29 @@ func (in *ServerTemplate) DeepCopy() *ServerTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceReference) DeepCopyInto(out *ServiceReference) {
+	*out = *in
+	in.ServerParams.DeepCopyInto(&out.ServerParams)
+	if in.EndpointSlices != nil {
+		in, out := &in.EndpointSlices, &out.EndpointSlices
+		*out = make([]discoveryv1.EndpointSlice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceReference.
+func (in *ServiceReference) DeepCopy() *ServiceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StaticHTTPFile) DeepCopyInto(out *StaticHTTPFile) {
 	*out = *in
@@ -1472,6 +2820,13 @@ func (in *StaticHTTPFile) DeepCopy() *StaticHTTPFile {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Status) DeepCopyInto(out *Status) {
 	*out = *in
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
@@ -1484,6 +2839,41 @@ func (in *Status) DeepCopy() *Status {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StickTable) DeepCopyInto(out *StickTable) {
+	*out = *in
+	if in.Expire != nil {
+		in, out := &in.Expire, &out.Expire
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.KeyLength != nil {
+		in, out := &in.KeyLength, &out.KeyLength
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NoPurge != nil {
+		in, out := &in.NoPurge, &out.NoPurge
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Store != nil {
+		in, out := &in.Store, &out.Store
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StickTable.
+func (in *StickTable) DeepCopy() *StickTable {
+	if in == nil {
+		return nil
+	}
+	out := new(StickTable)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPRequestRule) DeepCopyInto(out *TCPRequestRule) {
 	*out = *in
@@ -1534,3 +2924,190 @@ func (in *Timeouts) DeepCopy() *Timeouts {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficSplit) DeepCopyInto(out *TrafficSplit) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]WeightedBackend, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pinning != nil {
+		in, out := &in.Pinning, &out.Pinning
+		*out = new(TrafficSplitPinning)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSplit.
+func (in *TrafficSplit) DeepCopy() *TrafficSplit {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficSplit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficSplitPinning) DeepCopyInto(out *TrafficSplitPinning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSplitPinning.
+func (in *TrafficSplitPinning) DeepCopy() *TrafficSplitPinning {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficSplitPinning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *User) DeepCopyInto(out *User) {
+	*out = *in
+	in.Password.DeepCopyInto(&out.Password)
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new User.
+func (in *User) DeepCopy() *User {
+	if in == nil {
+		return nil
+	}
+	out := new(User)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Userlist) DeepCopyInto(out *Userlist) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Userlist.
+func (in *Userlist) DeepCopy() *Userlist {
+	if in == nil {
+		return nil
+	}
+	out := new(Userlist)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Userlist) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserlistList) DeepCopyInto(out *UserlistList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Userlist, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserlistList.
+func (in *UserlistList) DeepCopy() *UserlistList {
+	if in == nil {
+		return nil
+	}
+	out := new(UserlistList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UserlistList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserlistSpec) DeepCopyInto(out *UserlistSpec) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]User, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserlistSpec.
+func (in *UserlistSpec) DeepCopy() *UserlistSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserlistSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerifyIgnoreErrors) DeepCopyInto(out *VerifyIgnoreErrors) {
+	*out = *in
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerifyIgnoreErrors.
+func (in *VerifyIgnoreErrors) DeepCopy() *VerifyIgnoreErrors {
+	if in == nil {
+		return nil
+	}
+	out := new(VerifyIgnoreErrors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeightedBackend) DeepCopyInto(out *WeightedBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeightedBackend.
+func (in *WeightedBackend) DeepCopy() *WeightedBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(WeightedBackend)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,29 @@
+package v1alpha1
+
+import (
+	"k8s.io/utils/pointer"
+)
+
+// Default sets the values which are otherwise assumed when the configuration is rendered.
+func (i *Instance) Default() {
+	if i.Spec.Metrics != nil && i.Spec.Metrics.Address == nil {
+		i.Spec.Metrics.Address = pointer.String("0.0.0.0")
+	}
+
+	global := &i.Spec.Configuration.Global
+	if global.RuntimeSync != nil && global.RuntimeSync.Port == nil {
+		global.RuntimeSync.Port = pointer.Int64(9999)
+	}
+	if global.Logging != nil && global.Logging.SendHostname == nil {
+		global.Logging.SendHostname = pointer.Bool(false)
+	}
+
+	if logging := i.Spec.Configuration.Defaults.Logging; logging != nil {
+		if logging.HTTPLog == nil {
+			logging.HTTPLog = pointer.Bool(false)
+		}
+		if logging.TCPLog == nil {
+			logging.TCPLog = pointer.Bool(false)
+		}
+	}
+}
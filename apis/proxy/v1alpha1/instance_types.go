@@ -15,6 +15,7 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
 	"go.uber.org/multierr"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
@@ -57,6 +58,10 @@ type InstanceSpec struct {
 	// +nullable
 	// Labels additional labels for the ha-proxy pods
 	Labels map[string]string `json:"labels,omitempty"`
+	// UpdateStrategy defines how changes of the pods are rolled out. If reload is disabled, this includes every change
+	// of the configuration.
+	// +optional
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
 }
 
 type Placement struct {
@@ -197,6 +202,11 @@ type GlobalConfiguration struct {
 	// Reload enables auto-reload of the configuration using sockets. Requires an image that supports this feature.
 	// +kubebuilder:default=false
 	Reload bool `json:"reload"`
+	// RuntimeSync applies server, map and certificate changes through the runtime API of each pod instead of reloading
+	// HAProxy. Structural changes of the configuration, including changed ACL values, trigger a reload. Requires
+	// reload to be enabled.
+	// +optional
+	RuntimeSync *RuntimeSyncConfiguration `json:"runtimeSync,omitempty"`
 	// StatsTimeout sets the timeout on the stats socket. Default is set to 10 seconds.
 	// +optional
 	StatsTimeout *metav1.Duration `json:"statsTimeout,omitempty"`
@@ -227,10 +237,7 @@ type GlobalConfiguration struct {
 }
 
 func (g *GlobalConfiguration) Model() (models.Global, error) {
-	global := models.Global{
-		Maxconn:  pointer.Int64Deref(g.Maxconn, 0),
-		Nbthread: pointer.Int64Deref(g.Nbthread, 0),
-	}
+	global := models.Global{}
 
 	if g.AdditionalParameters != "" {
 		str := strings.ReplaceAll(fmt.Sprintf("%s\n%s", parser.Global, g.AdditionalParameters), "\n", "\n  ")
@@ -247,6 +254,14 @@ func (g *GlobalConfiguration) Model() (models.Global, error) {
 		}
 	}
 
+	if g.Maxconn != nil {
+		global.Maxconn = *g.Maxconn
+	}
+
+	if g.Nbthread != nil {
+		global.Nbthread = *g.Nbthread
+	}
+
 	if g.StatsTimeout != nil {
 		global.StatsTimeout = pointer.Int64(g.StatsTimeout.Milliseconds())
 	}
@@ -262,6 +277,19 @@ func (g *GlobalConfiguration) Model() (models.Global, error) {
 		})
 	}
 
+	if g.RuntimeSyncEnabled() {
+		global.RuntimeAPIs = append(global.RuntimeAPIs, &models.RuntimeAPI{
+			Address: pointer.String(fmt.Sprintf("ipv4@0.0.0.0:%d", g.RuntimeSync.GetPort())),
+			BindParams: models.BindParams{
+				Level:          "admin",
+				Ssl:            true,
+				SslCertificate: RuntimeAPICertificateFile,
+				SslCafile:      RuntimeAPICAFile,
+				Verify:         "required",
+			},
+		})
+	}
+
 	if g.TuneOptions != nil {
 		opts, err := g.TuneOptions.Model()
 		if err != nil {
@@ -297,6 +325,10 @@ func (g *GlobalConfiguration) Model() (models.Global, error) {
 	return global, global.Validate(strfmt.Default)
 }
 
+func (g *GlobalConfiguration) RuntimeSyncEnabled() bool {
+	return g.Reload && g.RuntimeSync != nil && g.RuntimeSync.Enabled
+}
+
 func (g *GlobalConfiguration) AddToParser(p parser.Parser) error {
 	global, err := g.Model()
 	if err != nil {
@@ -319,6 +351,29 @@ func (g *GlobalConfiguration) AddToParser(p parser.Parser) error {
 	return nil
 }
 
+const (
+	// RuntimeAPICertificateFile is the server certificate and key of the runtime API exposed for runtime sync.
+	RuntimeAPICertificateFile = "/usr/local/etc/haproxy/runtime-api.pem"
+	// RuntimeAPICAFile is the CA of the client certificates accepted by the runtime API exposed for runtime sync.
+	RuntimeAPICAFile = "/usr/local/etc/haproxy/runtime-api-ca.crt"
+)
+
+type RuntimeSyncConfiguration struct {
+	// Enabled exposes the runtime API on the pod network and lets the operator apply changes through it. The runtime
+	// API requires TLS with a client certificate issued by a CA which the operator keeps in the Secret
+	// <instance>-haproxy-runtime-tls.
+	Enabled bool `json:"enabled"`
+	// Port on which the runtime API is exposed to the operator. Default is set to 9999.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int64 `json:"port,omitempty"`
+}
+
+func (r *RuntimeSyncConfiguration) GetPort() int64 {
+	return pointer.Int64Deref(r.Port, 9999)
+}
+
 type GlobalSSL struct {
 	// DefaultBindCiphers sets the list of cipher algorithms ("cipher suite") that are negotiated during the SSL/TLS handshake up to TLSv1.2 for all
 	// binds which do not explicitly define theirs.
@@ -591,6 +646,40 @@ type InstanceStatus struct {
 	// Error shows the actual error message if Phase is 'Error'.
 	// +optional
 	Error string `json:"error,omitempty"`
+	// ConfigHash is the hash of the rendered configuration files.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+	// LastAppliedTime is the time the rendered configuration was last published.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// AppliedGenerations lists the generation of each selected config object in the published configuration.
+	// +optional
+	AppliedGenerations []AppliedGeneration `json:"appliedGenerations,omitempty"`
+	// Pods shows the configuration hash loaded by the replicas. Replicas which reload the configuration on their own are
+	// omitted, as the configuration they have loaded is unknown.
+	// +optional
+	Pods []PodConfigStatus `json:"pods,omitempty"`
+}
+
+// AppliedGeneration is the generation of a config object which is part of the published configuration.
+type AppliedGeneration struct {
+	// Kind of the config object.
+	Kind string `json:"kind"`
+	// Name of the config object.
+	Name string `json:"name"`
+	// Generation of the config object.
+	Generation int64 `json:"generation"`
+}
+
+// PodConfigStatus shows the configuration loaded by a replica.
+type PodConfigStatus struct {
+	// Name of the pod.
+	Name string `json:"name"`
+	// ConfigHash is the hash of the configuration loaded by the pod.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+	// UpToDate is true if the pod has loaded the current configuration.
+	UpToDate bool `json:"upToDate"`
 }
 
 // InstancePhase is a label for the phase of a Instance at the current time.
@@ -0,0 +1,43 @@
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("GlobalConfiguration", Label("type"), func() {
+	Context("Model", func() {
+		It("should set maxconn and nbthread", func() {
+			global := &proxyv1alpha1.GlobalConfiguration{
+				Maxconn:  pointer.Int64(1000),
+				Nbthread: pointer.Int64(4),
+			}
+			model, err := global.Model()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(model.Maxconn).Should(BeEquivalentTo(1000))
+			Ω(model.Nbthread).Should(BeEquivalentTo(4))
+		})
+		It("should keep maxconn and nbthread with additional parameters", func() {
+			global := &proxyv1alpha1.GlobalConfiguration{
+				Maxconn:              pointer.Int64(1000),
+				Nbthread:             pointer.Int64(4),
+				AdditionalParameters: "ulimit-n 65536\nmaxconn 10",
+			}
+			model, err := global.Model()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(model.Ulimitn).Should(BeEquivalentTo(65536))
+			Ω(model.Maxconn).Should(BeEquivalentTo(1000))
+			Ω(model.Nbthread).Should(BeEquivalentTo(4))
+		})
+		It("should keep maxconn of the additional parameters if it is not set", func() {
+			global := &proxyv1alpha1.GlobalConfiguration{
+				AdditionalParameters: "maxconn 10",
+			}
+			model, err := global.Model()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(model.Maxconn).Should(BeEquivalentTo(10))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package v1alpha1_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestProxyAPI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Proxy API Test Suite")
+}
@@ -25,12 +25,28 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	timex "time"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedGeneration) DeepCopyInto(out *AppliedGeneration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedGeneration.
+func (in *AppliedGeneration) DeepCopy() *AppliedGeneration {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedGeneration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Configuration) DeepCopyInto(out *Configuration) {
 	*out = *in
@@ -115,6 +131,11 @@ func (in *DefaultsLoggingConfiguration) DeepCopy() *DefaultsLoggingConfiguration
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlobalConfiguration) DeepCopyInto(out *GlobalConfiguration) {
 	*out = *in
+	if in.RuntimeSync != nil {
+		in, out := &in.RuntimeSync, &out.RuntimeSync
+		*out = new(RuntimeSyncConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StatsTimeout != nil {
 		in, out := &in.StatsTimeout, &out.StatsTimeout
 		*out = new(metav1.Duration)
@@ -315,7 +336,7 @@ func (in *Instance) DeepCopyInto(out *Instance) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Instance.
@@ -402,6 +423,11 @@ func (in *InstanceSpec) DeepCopyInto(out *InstanceSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.StatefulSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceSpec.
@@ -417,6 +443,20 @@ func (in *InstanceSpec) DeepCopy() *InstanceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceStatus) DeepCopyInto(out *InstanceStatus) {
 	*out = *in
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AppliedGenerations != nil {
+		in, out := &in.AppliedGenerations, &out.AppliedGenerations
+		*out = make([]AppliedGeneration, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]PodConfigStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceStatus.
@@ -513,6 +553,21 @@ func (in *Placement) DeepCopy() *Placement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodConfigStatus) DeepCopyInto(out *PodConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodConfigStatus.
+func (in *PodConfigStatus) DeepCopy() *PodConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PodConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
 	*out = *in
@@ -533,6 +588,26 @@ func (in *RouteSpec) DeepCopy() *RouteSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeSyncConfiguration) DeepCopyInto(out *RuntimeSyncConfiguration) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeSyncConfiguration.
+func (in *RuntimeSyncConfiguration) DeepCopy() *RuntimeSyncConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeSyncConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 	*out = *in
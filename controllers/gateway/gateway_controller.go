@@ -0,0 +1,332 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+const (
+	// ControllerName is the value of spec.controllerName of the GatewayClasses served by the operator.
+	ControllerName = "proxy.haproxy.com/gateway-controller"
+
+	defaultImage = "haproxy:2.8.0"
+)
+
+// Reconciler runs an Instance per Gateway and translates the listeners and attached routes of the Gateway into
+// Frontends and Backends.
+type Reconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways;httproutes;tlsroutes;tcproutes;referencegrants,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/status;httproutes/status;tlsroutes/status;tcproutes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=proxy.haproxy.com,resources=instances,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=config.haproxy.com,resources=frontends;backends,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	gateway := &gatewayv1beta1.Gateway{}
+	if err := r.Get(ctx, req.NamespacedName, gateway); err != nil {
+		// the instance and the configuration are garbage collected through their owner references
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	class := &gatewayv1beta1.GatewayClass{}
+	if err := r.Get(ctx, client.ObjectKey{Name: string(gateway.Spec.GatewayClassName)}, class); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if class.Spec.ControllerName != ControllerName {
+		return reconcile.Result{}, nil
+	}
+
+	spec, message, err := instanceSpec(ctx, r.Client, class)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if message != "" {
+		meta.SetStatusCondition(&gateway.Status.Conditions, metav1.Condition{
+			Type:               string(gatewayv1beta1.GatewayConditionScheduled),
+			Status:             metav1.ConditionFalse,
+			Reason:             string(gatewayv1beta1.GatewayReasonNotReconciled),
+			Message:            message,
+			ObservedGeneration: gateway.Generation,
+		})
+		return reconcile.Result{}, r.Status().Update(ctx, gateway)
+	}
+
+	instance, err := r.reconcileInstance(ctx, gateway, spec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	routes, err := r.listRoutes(ctx, gateway)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	t, err := r.translate(ctx, gateway, routes)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for _, backend := range t.backends {
+		object := &configv1alpha1.Backend{ObjectMeta: metav1.ObjectMeta{Name: backend.Name, Namespace: backend.Namespace}}
+		if err := r.apply(ctx, instance, object, func() {
+			object.Labels = backend.Labels
+			object.Spec = backend.Spec
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	for _, frontend := range t.frontends {
+		object := &configv1alpha1.Frontend{ObjectMeta: metav1.ObjectMeta{Name: frontend.Name, Namespace: frontend.Namespace}}
+		if err := r.apply(ctx, instance, object, func() {
+			object.Labels = frontend.Labels
+			object.Spec = frontend.Spec
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err := r.cleanup(ctx, gateway, t); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, r.updateStatus(ctx, gateway, instance, routes, t)
+}
+
+// reconcileInstance creates or updates the Instance of the Gateway based on the spec derived from the GatewayClass.
+func (r *Reconciler) reconcileInstance(ctx context.Context, gateway *gatewayv1beta1.Gateway, spec *proxyv1alpha1.InstanceSpec) (*proxyv1alpha1.Instance, error) {
+	logger := log.FromContext(ctx)
+
+	instance := &proxyv1alpha1.Instance{ObjectMeta: metav1.ObjectMeta{Name: gateway.Name, Namespace: gateway.Namespace}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, instance, func() error {
+		instance.Spec = *spec.DeepCopy()
+		instance.Spec.Configuration.LabelSelector = metav1.LabelSelector{
+			MatchLabels: map[string]string{gatewayLabel: gateway.Name},
+		}
+		instance.Spec.Network.Service.Enabled = true
+
+		for _, listener := range gateway.Spec.Listeners {
+			if listener.Port < 1024 {
+				instance.Spec.AllowPrivilegedPorts = pointer.Bool(true)
+			}
+		}
+
+		return controllerutil.SetControllerReference(gateway, instance, r.Scheme)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result != controllerutil.OperationResultNone {
+		logger.Info(fmt.Sprintf("Object %s", result), "Instance", instance.Name)
+	}
+
+	return instance, nil
+}
+
+func (r *Reconciler) apply(ctx context.Context, instance *proxyv1alpha1.Instance, object configv1alpha1.Object, mutate func()) error {
+	logger := log.FromContext(ctx)
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, object, func() error {
+		mutate()
+		return controllerutil.SetControllerReference(instance, object, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		logger.Info(fmt.Sprintf("Object %s", result), reflect.TypeOf(object).Elem().Name(), object.GetName())
+	}
+
+	return nil
+}
+
+// cleanup deletes the objects generated for the gateway which are not part of the translation.
+func (r *Reconciler) cleanup(ctx context.Context, gateway *gatewayv1beta1.Gateway, t *translation) error {
+	desired := map[string]bool{}
+	for _, frontend := range t.frontends {
+		desired["Frontend/"+frontend.Name] = true
+	}
+	for _, backend := range t.backends {
+		desired["Backend/"+backend.Name] = true
+	}
+
+	var objects []client.Object
+	opts := []client.ListOption{client.InNamespace(gateway.Namespace), client.MatchingLabels{gatewayLabel: gateway.Name}}
+
+	frontends := &configv1alpha1.FrontendList{}
+	if err := r.List(ctx, frontends, opts...); err != nil {
+		return err
+	}
+	for i := range frontends.Items {
+		if !desired["Frontend/"+frontends.Items[i].Name] {
+			objects = append(objects, &frontends.Items[i])
+		}
+	}
+
+	backends := &configv1alpha1.BackendList{}
+	if err := r.List(ctx, backends, opts...); err != nil {
+		return err
+	}
+	for i := range backends.Items {
+		if !desired["Backend/"+backends.Items[i].Name] {
+			objects = append(objects, &backends.Items[i])
+		}
+	}
+
+	var errs error
+	for _, object := range objects {
+		if err := r.Delete(ctx, object); client.IgnoreNotFound(err) != nil {
+			errs = multierr.Append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1beta1.Gateway{}).
+		Owns(&proxyv1alpha1.Instance{}).
+		Watches(&source.Kind{Type: &gatewayv1beta1.HTTPRoute{}}, handler.EnqueueRequestsFromMapFunc(r.findGatewaysForRoute)).
+		Watches(&source.Kind{Type: &gatewayv1beta1.GatewayClass{}}, handler.EnqueueRequestsFromMapFunc(r.findGatewaysForClass)).
+		Watches(&source.Kind{Type: &corev1.Service{}}, handler.EnqueueRequestsFromMapFunc(r.findGatewaysForNamespace)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.findGatewaysForNamespace)).
+		Watches(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(r.findAllGateways))
+
+	// TLSRoutes, TCPRoutes and ReferenceGrants are only watched if their CRDs are installed
+	mappers := map[client.Object]handler.MapFunc{
+		&gatewayv1alpha2.TLSRoute{}:       r.findGatewaysForRoute,
+		&gatewayv1alpha2.TCPRoute{}:       r.findGatewaysForRoute,
+		&gatewayv1alpha2.ReferenceGrant{}: r.findAllGateways,
+	}
+	for object, mapper := range mappers {
+		gvk, err := apiutil.GVKForObject(object, mgr.GetScheme())
+		if err != nil {
+			return err
+		}
+		if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return err
+		}
+		builder = builder.Watches(&source.Kind{Type: object}, handler.EnqueueRequestsFromMapFunc(mapper))
+	}
+
+	return builder.Complete(r)
+}
+
+// findGatewaysForRoute enqueues the parent Gateways of the route. Update events map the old and the new route, so
+// Gateways a route detached from are reconciled as well.
+func (r *Reconciler) findGatewaysForRoute(object client.Object) []reconcile.Request {
+	var rt *route
+	switch o := object.(type) {
+	case *gatewayv1beta1.HTTPRoute:
+		rt = fromHTTPRoute(o)
+	case *gatewayv1alpha2.TLSRoute:
+		rt = fromTLSRoute(o)
+	case *gatewayv1alpha2.TCPRoute:
+		rt = fromTCPRoute(o)
+	default:
+		return nil
+	}
+
+	return parentRequests(rt)
+}
+
+func parentRequests(rt *route) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, parent := range rt.parents {
+		if parent.group == gatewayv1beta1.GroupName && parent.kind == "Gateway" {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: parent.namespace, Name: parent.name}})
+		}
+	}
+
+	return requests
+}
+
+func (r *Reconciler) findGatewaysForClass(object client.Object) []reconcile.Request {
+	gateways := &gatewayv1beta1.GatewayList{}
+	if err := r.List(context.Background(), gateways); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, gateway := range gateways.Items {
+		if string(gateway.Spec.GatewayClassName) == object.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+		}
+	}
+
+	return requests
+}
+
+// findAllGateways enqueues all Gateways, as the labels of namespaces and ReferenceGrants decide which routes and
+// backends of other namespaces they accept.
+func (r *Reconciler) findAllGateways(_ client.Object) []reconcile.Request {
+	gateways := &gatewayv1beta1.GatewayList{}
+	if err := r.List(context.Background(), gateways); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, gateway := range gateways.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+	}
+
+	return requests
+}
+
+// findGatewaysForNamespace enqueues the Gateways in the namespace of the object, as they might reference it as
+// certificate or backend, and the parent Gateways of the routes referencing backends in the namespace.
+func (r *Reconciler) findGatewaysForNamespace(object client.Object) []reconcile.Request {
+	ctx := context.Background()
+
+	gateways := &gatewayv1beta1.GatewayList{}
+	if err := r.List(ctx, gateways, client.InNamespace(object.GetNamespace())); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, gateway := range gateways.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+	}
+
+	routes, err := r.listAllRoutes(ctx)
+	if err != nil {
+		return requests
+	}
+	for _, rt := range routes {
+		if rt.object.GetNamespace() != object.GetNamespace() && rt.referencesNamespace(object.GetNamespace()) {
+			requests = append(requests, parentRequests(rt)...)
+		}
+	}
+
+	return requests
+}
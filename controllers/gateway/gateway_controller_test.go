@@ -0,0 +1,470 @@
+package gateway_test
+
+import (
+	"context"
+
+	parser "github.com/haproxytech/config-parser/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/controllers/gateway"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+var _ = Describe("Reconcile", Label("controller"), func() {
+	Context("Reconcile", func() {
+		var (
+			scheme   *runtime.Scheme
+			ctx      context.Context
+			template *proxyv1alpha1.Instance
+			class    *gatewayv1beta1.GatewayClass
+			gw       *gatewayv1beta1.Gateway
+			stable   *corev1.Service
+			canary   *corev1.Service
+			app      *gatewayv1beta1.HTTPRoute
+		)
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Ω(clientgoscheme.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+			Ω(configv1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+			Ω(proxyv1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+			Ω(gatewayv1beta1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+			Ω(gatewayv1alpha2.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+
+			ctx = context.Background()
+
+			template = &proxyv1alpha1.Instance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "template",
+					Namespace: "haproxy",
+				},
+				Spec: proxyv1alpha1.InstanceSpec{
+					Replicas: 2,
+					Image:    "haproxy:2.8.0",
+				},
+			}
+
+			class = &gatewayv1beta1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "haproxy",
+				},
+				Spec: gatewayv1beta1.GatewayClassSpec{
+					ControllerName: gateway.ControllerName,
+					ParametersRef: &gatewayv1beta1.ParametersReference{
+						Group:     gatewayv1beta1.Group(proxyv1alpha1.GroupVersion.Group),
+						Kind:      "Instance",
+						Name:      template.Name,
+						Namespace: (*gatewayv1beta1.Namespace)(pointer.String(template.Namespace)),
+					},
+				},
+			}
+
+			gw = &gatewayv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gw",
+					Namespace: "foo",
+					UID:       uuid.NewUUID(),
+				},
+				Spec: gatewayv1beta1.GatewaySpec{
+					GatewayClassName: gatewayv1beta1.ObjectName(class.Name),
+					Listeners: []gatewayv1beta1.Listener{
+						{Name: "http", Port: 80, Protocol: gatewayv1beta1.HTTPProtocolType},
+					},
+				},
+			}
+
+			stable = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "foo"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 8080}}},
+			}
+			canary = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "foo"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "web", Port: 8080}}},
+			}
+
+			exact := gatewayv1beta1.PathMatchExact
+			prefix := gatewayv1beta1.PathMatchPathPrefix
+			app = &gatewayv1beta1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app",
+					Namespace: "foo",
+				},
+				Spec: gatewayv1beta1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1beta1.CommonRouteSpec{
+						ParentRefs: []gatewayv1beta1.ParentReference{{Name: gatewayv1beta1.ObjectName(gw.Name)}},
+					},
+					Hostnames: []gatewayv1beta1.Hostname{"app.example.com"},
+					Rules: []gatewayv1beta1.HTTPRouteRule{
+						{
+							Matches: []gatewayv1beta1.HTTPRouteMatch{
+								{
+									Path: &gatewayv1beta1.HTTPPathMatch{Type: &prefix, Value: pointer.String("/api")},
+									Headers: []gatewayv1beta1.HTTPHeaderMatch{
+										{Name: "x-version", Value: "2"},
+									},
+								},
+							},
+							BackendRefs: []gatewayv1beta1.HTTPBackendRef{
+								{BackendRef: backendRef("stable", 8080, 900)},
+								{BackendRef: backendRef("canary", 8080, 100)},
+							},
+						},
+						{
+							Matches: []gatewayv1beta1.HTTPRouteMatch{
+								{Path: &gatewayv1beta1.HTTPPathMatch{Type: &exact, Value: pointer.String("/health")}},
+							},
+							BackendRefs: []gatewayv1beta1.HTTPBackendRef{
+								{BackendRef: backendRef("stable", 8080, 1)},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		reconcile := func(cli client.Client) {
+			r := gateway.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gw)})
+			Ω(err).ShouldNot(HaveOccurred())
+		}
+
+		It("should create an instance from the parameters of the gateway class", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template, class, gw).Build()
+			reconcile(cli)
+
+			instance := &proxyv1alpha1.Instance{}
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(gw), instance)).ShouldNot(HaveOccurred())
+			Ω(instance.Spec.Replicas).Should(BeEquivalentTo(2))
+			Ω(instance.Spec.Configuration.LabelSelector.MatchLabels).Should(HaveKeyWithValue("proxy.haproxy.com/gateway", gw.Name))
+			Ω(instance.Spec.Network.Service.Enabled).Should(BeTrue())
+			Ω(instance.Spec.AllowPrivilegedPorts).Should(Equal(pointer.Bool(true)))
+			Ω(instance.OwnerReferences).Should(HaveLen(1))
+			Ω(instance.OwnerReferences[0].UID).Should(Equal(gw.UID))
+		})
+
+		It("should translate http routes into switching rules and weighted backends", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template, class, gw, stable, canary, app).Build()
+			reconcile(cli)
+
+			backend := &configv1alpha1.Backend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-httproute-app-0"}, backend)).ShouldNot(HaveOccurred())
+			Ω(backend.Spec.ServiceRefs).Should(HaveLen(2))
+			Ω(backend.Spec.ServiceRefs[0].Name).Should(Equal("stable"))
+			Ω(backend.Spec.ServiceRefs[0].Port).Should(Equal("http"))
+			Ω(backend.Spec.ServiceRefs[0].Weight).Should(Equal(pointer.Int64(256)))
+			Ω(backend.Spec.ServiceRefs[1].Port).Should(Equal("web"))
+			Ω(backend.Spec.ServiceRefs[1].Weight).Should(Equal(pointer.Int64(28)))
+
+			frontend := &configv1alpha1.Frontend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-80"}, frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Spec.Mode).Should(Equal("http"))
+			Ω(frontend.Spec.DefaultBackend.Name).Should(Equal("gw-80-default"))
+			Ω(frontend.Spec.BackendSwitching).Should(HaveLen(3))
+			Ω(frontend.Spec.BackendSwitching[0].Condition).Should(Equal("{ req.hdr(host),field(1,:) -i -m str app.example.com } { path -m str /health }"))
+			Ω(*frontend.Spec.BackendSwitching[0].Backend.Name).Should(Equal("gw-httproute-app-1"))
+			Ω(frontend.Spec.BackendSwitching[1].Condition).Should(Equal("{ req.hdr(host),field(1,:) -i -m str app.example.com } { path -m str /api } { req.hdr(x-version) -m str 2 }"))
+			Ω(frontend.Spec.BackendSwitching[2].Condition).Should(Equal("{ req.hdr(host),field(1,:) -i -m str app.example.com } { path -m beg /api/ } { req.hdr(x-version) -m str 2 }"))
+
+			p, err := parser.New()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(frontend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring("use_backend gw-httproute-app-1 if { req.hdr(host),field(1,:) -i -m str app.example.com } { path -m str /health }\n"))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(app), app)).ShouldNot(HaveOccurred())
+			Ω(app.Status.Parents).Should(HaveLen(1))
+			Ω(string(app.Status.Parents[0].ControllerName)).Should(Equal(gateway.ControllerName))
+			Ω(meta.IsStatusConditionTrue(app.Status.Parents[0].Conditions, string(gatewayv1beta1.RouteConditionAccepted))).Should(BeTrue())
+			Ω(meta.IsStatusConditionTrue(app.Status.Parents[0].Conditions, string(gatewayv1beta1.RouteConditionResolvedRefs))).Should(BeTrue())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(gw), gw)).ShouldNot(HaveOccurred())
+			Ω(gw.Status.Listeners).Should(HaveLen(1))
+			Ω(gw.Status.Listeners[0].AttachedRoutes).Should(BeEquivalentTo(1))
+			Ω(meta.IsStatusConditionTrue(gw.Status.Conditions, string(gatewayv1beta1.GatewayConditionScheduled))).Should(BeTrue())
+		})
+
+		It("should quote match values interpreted by the configuration parser", func() {
+			app.Spec.Rules[0].Matches[0].Headers[0].Value = "a } || { always_true"
+			app.Spec.Rules[0].Matches[0].QueryParams = []gatewayv1beta1.HTTPQueryParamMatch{{Name: "q", Value: "$HOME #x"}}
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template, class, gw, stable, canary, app).Build()
+			reconcile(cli)
+
+			frontend := &configv1alpha1.Frontend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-80"}, frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Spec.BackendSwitching[1].Condition).Should(Equal("{ req.hdr(host),field(1,:) -i -m str app.example.com } { path -m str /api } { req.hdr(x-version) -m str 'a } || { always_true' } { url_param(q) -m str '$HOME #x' }"))
+		})
+
+		It("should reject matches which cannot be quoted", func() {
+			for _, mutate := range []func(match *gatewayv1beta1.HTTPRouteMatch){
+				func(match *gatewayv1beta1.HTTPRouteMatch) { match.Headers[0].Name = "x-version) } || { always_true" },
+				func(match *gatewayv1beta1.HTTPRouteMatch) { match.Headers[0].Value = "2' || '" },
+				func(match *gatewayv1beta1.HTTPRouteMatch) {
+					match.QueryParams = []gatewayv1beta1.HTTPQueryParamMatch{{Name: "q\nuse_backend evil", Value: "1"}}
+				},
+				func(match *gatewayv1beta1.HTTPRouteMatch) {
+					match.Method = (*gatewayv1beta1.HTTPMethod)(pointer.String("GET } || { always_true"))
+				},
+				func(match *gatewayv1beta1.HTTPRouteMatch) {
+					match.Path.Value = pointer.String("/api\nuse_backend evil")
+				},
+			} {
+				route := app.DeepCopy()
+				mutate(&route.Spec.Rules[0].Matches[0])
+				cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template, class, gw, stable, canary, route).Build()
+				reconcile(cli)
+
+				Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-httproute-app-0"}, &configv1alpha1.Backend{})).ShouldNot(Succeed())
+				frontend := &configv1alpha1.Frontend{}
+				Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-80"}, frontend)).ShouldNot(HaveOccurred())
+				Ω(frontend.Spec.BackendSwitching).Should(BeEmpty())
+
+				Ω(cli.Get(ctx, client.ObjectKeyFromObject(route), route)).ShouldNot(HaveOccurred())
+				accepted := meta.FindStatusCondition(route.Status.Parents[0].Conditions, string(gatewayv1beta1.RouteConditionAccepted))
+				Ω(accepted.Status).Should(Equal(metav1.ConditionFalse))
+				Ω(accepted.Reason).Should(Equal(string(gatewayv1beta1.RouteReasonUnsupportedValue)))
+			}
+		})
+
+		It("should match wildcard hostnames case-insensitively", func() {
+			app.Spec.Hostnames = []gatewayv1beta1.Hostname{"*.example.com"}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template, class, gw, stable, canary, app).Build()
+			reconcile(cli)
+
+			frontend := &configv1alpha1.Frontend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-80"}, frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Spec.BackendSwitching).Should(HaveLen(3))
+			Ω(frontend.Spec.BackendSwitching[0].Condition).Should(Equal("{ req.hdr(host),field(1,:) -i -m end .example.com } { path -m str /health }"))
+		})
+
+		It("should reject routes whose hostnames do not match the listener", func() {
+			gw.Spec.Listeners[0].Hostname = (*gatewayv1beta1.Hostname)(pointer.String("*.example.org"))
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template, class, gw, stable, canary, app).Build()
+			reconcile(cli)
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(app), app)).ShouldNot(HaveOccurred())
+			Ω(app.Status.Parents).Should(HaveLen(1))
+			condition := meta.FindStatusCondition(app.Status.Parents[0].Conditions, string(gatewayv1beta1.RouteConditionAccepted))
+			Ω(condition).ShouldNot(BeNil())
+			Ω(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Ω(condition.Reason).Should(Equal(string(gatewayv1beta1.RouteReasonNoMatchingListenerHostname)))
+
+			backends := &configv1alpha1.BackendList{}
+			Ω(cli.List(ctx, backends)).ShouldNot(HaveOccurred())
+			Ω(backends.Items).Should(HaveLen(1))
+			Ω(backends.Items[0].Name).Should(Equal("gw-80-default"))
+		})
+
+		It("should attach routes of the namespaces allowed by the listener", func() {
+			app.Namespace = "bar"
+			app.Spec.ParentRefs[0].Namespace = (*gatewayv1beta1.Namespace)(pointer.String(gw.Namespace))
+			app.Spec.Rules = app.Spec.Rules[1:]
+			app.Spec.Rules[0].BackendRefs[0].Namespace = (*gatewayv1beta1.Namespace)(pointer.String("foo"))
+			app.Spec.Rules[0].BackendRefs = append(app.Spec.Rules[0].BackendRefs, gatewayv1beta1.HTTPBackendRef{BackendRef: backendRef("web", 8080, 1)})
+			bar := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bar", Labels: map[string]string{"team": "bar"}}}
+			web := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "bar"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 8080}}},
+			}
+			grant := &gatewayv1alpha2.ReferenceGrant{
+				ObjectMeta: metav1.ObjectMeta{Name: "bar-routes", Namespace: "foo"},
+				Spec: gatewayv1alpha2.ReferenceGrantSpec{
+					From: []gatewayv1alpha2.ReferenceGrantFrom{{Group: gatewayv1beta1.GroupName, Kind: "HTTPRoute", Namespace: "bar"}},
+					To:   []gatewayv1alpha2.ReferenceGrantTo{{Kind: "Service", Name: (*gatewayv1alpha2.ObjectName)(pointer.String("stable"))}},
+				},
+			}
+
+			// routes of other namespaces are rejected by default
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template, class, gw, stable, web, bar, grant, app).Build()
+			reconcile(cli)
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(app), app)).ShouldNot(HaveOccurred())
+			Ω(app.Status.Parents).Should(HaveLen(1))
+			condition := meta.FindStatusCondition(app.Status.Parents[0].Conditions, string(gatewayv1beta1.RouteConditionAccepted))
+			Ω(condition.Reason).Should(Equal(string(gatewayv1beta1.RouteReasonNotAllowedByListeners)))
+
+			fromSelector := gatewayv1beta1.NamespacesFromSelector
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(gw), gw)).ShouldNot(HaveOccurred())
+			gw.Spec.Listeners[0].AllowedRoutes = &gatewayv1beta1.AllowedRoutes{
+				Namespaces: &gatewayv1beta1.RouteNamespaces{
+					From:     &fromSelector,
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "bar"}},
+				},
+			}
+			Ω(cli.Update(ctx, gw)).ShouldNot(HaveOccurred())
+			reconcile(cli)
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(app), app)).ShouldNot(HaveOccurred())
+			Ω(meta.IsStatusConditionTrue(app.Status.Parents[0].Conditions, string(gatewayv1beta1.RouteConditionAccepted))).Should(BeTrue())
+			Ω(meta.IsStatusConditionTrue(app.Status.Parents[0].Conditions, string(gatewayv1beta1.RouteConditionResolvedRefs))).Should(BeTrue())
+
+			backend := &configv1alpha1.Backend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-httproute-app-0"}, backend)).ShouldNot(HaveOccurred())
+			// services outside of the namespace of the gateway are referenced with their namespace
+			Ω(backend.Spec.ServiceRefs).Should(Equal([]configv1alpha1.ServiceReference{
+				{Name: "stable", Port: "http", ServerParams: configv1alpha1.ServerParams{Weight: pointer.Int64(1)}},
+				{Name: "web", Namespace: "bar", Port: "http", ServerParams: configv1alpha1.ServerParams{Weight: pointer.Int64(1)}},
+			}))
+
+			// the grant only covers the stable service
+			app.Spec.Rules[0].BackendRefs[0].Name = "canary"
+			Ω(cli.Update(ctx, app)).ShouldNot(HaveOccurred())
+			Ω(cli.Create(ctx, canary)).ShouldNot(HaveOccurred())
+			reconcile(cli)
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(app), app)).ShouldNot(HaveOccurred())
+			condition = meta.FindStatusCondition(app.Status.Parents[0].Conditions, string(gatewayv1beta1.RouteConditionResolvedRefs))
+			Ω(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Ω(condition.Reason).Should(Equal(string(gatewayv1beta1.RouteReasonRefNotPermitted)))
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-httproute-app-0"}, backend)).ShouldNot(HaveOccurred())
+			Ω(backend.Spec.ServiceRefs).Should(HaveLen(1))
+			Ω(backend.Spec.ServiceRefs[0].Name).Should(Equal("web"))
+		})
+
+		It("should report unresolved backends and certificates", func() {
+			gw.Spec.Listeners = append(gw.Spec.Listeners, gatewayv1beta1.Listener{
+				Name:     "https",
+				Port:     443,
+				Protocol: gatewayv1beta1.HTTPSProtocolType,
+				TLS: &gatewayv1beta1.GatewayTLSConfig{
+					CertificateRefs: []gatewayv1beta1.SecretObjectReference{{Name: "missing"}},
+				},
+			})
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template, class, gw, stable, app).Build()
+			reconcile(cli)
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(app), app)).ShouldNot(HaveOccurred())
+			condition := meta.FindStatusCondition(app.Status.Parents[0].Conditions, string(gatewayv1beta1.RouteConditionResolvedRefs))
+			Ω(condition).ShouldNot(BeNil())
+			Ω(condition.Reason).Should(Equal(string(gatewayv1beta1.RouteReasonBackendNotFound)))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(gw), gw)).ShouldNot(HaveOccurred())
+			Ω(gw.Status.Listeners).Should(HaveLen(2))
+			condition = meta.FindStatusCondition(gw.Status.Listeners[1].Conditions, string(gatewayv1beta1.ListenerConditionResolvedRefs))
+			Ω(condition).ShouldNot(BeNil())
+			Ω(condition.Reason).Should(Equal(string(gatewayv1beta1.ListenerReasonInvalidCertificateRef)))
+			condition = meta.FindStatusCondition(gw.Status.Conditions, string(gatewayv1beta1.GatewayConditionReady))
+			Ω(condition).ShouldNot(BeNil())
+			Ω(condition.Reason).Should(Equal(string(gatewayv1beta1.GatewayReasonListenersNotValid)))
+
+			frontends := &configv1alpha1.FrontendList{}
+			Ω(cli.List(ctx, frontends)).ShouldNot(HaveOccurred())
+			Ω(frontends.Items).Should(HaveLen(1))
+		})
+
+		It("should route tls passthrough and tcp traffic", func() {
+			passthrough := gatewayv1beta1.TLSModePassthrough
+			gw.Spec.Listeners = []gatewayv1beta1.Listener{
+				{Name: "tls", Port: 443, Protocol: gatewayv1beta1.TLSProtocolType, TLS: &gatewayv1beta1.GatewayTLSConfig{Mode: &passthrough}},
+				{Name: "tcp", Port: 5432, Protocol: gatewayv1beta1.TCPProtocolType},
+			}
+
+			tlsRoute := &gatewayv1alpha2.TLSRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "secure", Namespace: "foo"},
+				Spec: gatewayv1alpha2.TLSRouteSpec{
+					CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+						ParentRefs: []gatewayv1alpha2.ParentReference{{Name: gatewayv1alpha2.ObjectName(gw.Name), SectionName: (*gatewayv1alpha2.SectionName)(pointer.String("tls"))}},
+					},
+					Hostnames: []gatewayv1alpha2.Hostname{"secure.example.com"},
+					Rules: []gatewayv1alpha2.TLSRouteRule{
+						{BackendRefs: []gatewayv1alpha2.BackendRef{v1alpha2BackendRef("stable", 8080, 1)}},
+					},
+				},
+			}
+			tcpRoute := &gatewayv1alpha2.TCPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "foo"},
+				Spec: gatewayv1alpha2.TCPRouteSpec{
+					CommonRouteSpec: gatewayv1alpha2.CommonRouteSpec{
+						ParentRefs: []gatewayv1alpha2.ParentReference{{Name: gatewayv1alpha2.ObjectName(gw.Name)}},
+					},
+					Rules: []gatewayv1alpha2.TCPRouteRule{
+						{BackendRefs: []gatewayv1alpha2.BackendRef{v1alpha2BackendRef("canary", 8080, 1)}},
+					},
+				},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template, class, gw, stable, canary, tlsRoute, tcpRoute).Build()
+			reconcile(cli)
+
+			frontend := &configv1alpha1.Frontend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-443"}, frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Spec.Mode).Should(Equal("tcp"))
+			Ω(frontend.Spec.Binds[0].SSL).Should(BeNil())
+			Ω(frontend.Spec.TCPRequest).Should(HaveLen(2))
+			Ω(frontend.Spec.BackendSwitching).Should(HaveLen(1))
+			Ω(frontend.Spec.BackendSwitching[0].Condition).Should(Equal("{ req_ssl_sni -i -m str secure.example.com }"))
+			Ω(*frontend.Spec.BackendSwitching[0].Backend.Name).Should(Equal("gw-tlsroute-secure"))
+
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-5432"}, frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Spec.DefaultBackend.Name).Should(Equal("gw-tcproute-db"))
+
+			backend := &configv1alpha1.Backend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "gw-tcproute-db"}, backend)).ShouldNot(HaveOccurred())
+			Ω(backend.Spec.Mode).Should(Equal("tcp"))
+			Ω(backend.Spec.ServiceRefs).Should(HaveLen(1))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(tcpRoute), tcpRoute)).ShouldNot(HaveOccurred())
+			Ω(tcpRoute.Status.Parents).Should(HaveLen(1))
+			Ω(meta.IsStatusConditionTrue(tcpRoute.Status.Parents[0].Conditions, string(gatewayv1beta1.RouteConditionAccepted))).Should(BeTrue())
+		})
+
+		It("should accept gateway classes with valid parameters", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(class).Build()
+			r := gateway.GatewayClassReconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: class.Name}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(class), class)).ShouldNot(HaveOccurred())
+			condition := meta.FindStatusCondition(class.Status.Conditions, string(gatewayv1beta1.GatewayClassConditionStatusAccepted))
+			Ω(condition).ShouldNot(BeNil())
+			Ω(condition.Reason).Should(Equal(string(gatewayv1beta1.GatewayClassReasonInvalidParameters)))
+
+			Ω(cli.Create(ctx, template)).ShouldNot(HaveOccurred())
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: class.Name}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(class), class)).ShouldNot(HaveOccurred())
+			Ω(meta.IsStatusConditionTrue(class.Status.Conditions, string(gatewayv1beta1.GatewayClassConditionStatusAccepted))).Should(BeTrue())
+		})
+	})
+})
+
+func backendRef(name string, port, weight int32) gatewayv1beta1.BackendRef {
+	return gatewayv1beta1.BackendRef{
+		BackendObjectReference: gatewayv1beta1.BackendObjectReference{
+			Name: gatewayv1beta1.ObjectName(name),
+			Port: (*gatewayv1beta1.PortNumber)(&port),
+		},
+		Weight: &weight,
+	}
+}
+
+func v1alpha2BackendRef(name string, port, weight int32) gatewayv1alpha2.BackendRef {
+	return gatewayv1alpha2.BackendRef{
+		BackendObjectReference: gatewayv1alpha2.BackendObjectReference{
+			Name: gatewayv1alpha2.ObjectName(name),
+			Port: (*gatewayv1alpha2.PortNumber)(&port),
+		},
+		Weight: &weight,
+	}
+}
@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// GatewayClassReconciler accepts the GatewayClasses served by the operator.
+type GatewayClassReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/status,verbs=get;update;patch
+
+func (r *GatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	class := &gatewayv1beta1.GatewayClass{}
+	if err := r.Get(ctx, req.NamespacedName, class); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if class.Spec.ControllerName != ControllerName {
+		return reconcile.Result{}, nil
+	}
+
+	_, message, err := instanceSpec(ctx, r.Client, class)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	condition := metav1.Condition{
+		Type:               string(gatewayv1beta1.GatewayClassConditionStatusAccepted),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gatewayv1beta1.GatewayClassReasonAccepted),
+		ObservedGeneration: class.Generation,
+	}
+	if message != "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(gatewayv1beta1.GatewayClassReasonInvalidParameters)
+		condition.Message = message
+	}
+
+	if current := meta.FindStatusCondition(class.Status.Conditions, condition.Type); current != nil &&
+		current.Status == condition.Status && current.Reason == condition.Reason &&
+		current.Message == condition.Message && current.ObservedGeneration == condition.ObservedGeneration {
+		return reconcile.Result{}, nil
+	}
+
+	meta.SetStatusCondition(&class.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, class); err != nil {
+		return reconcile.Result{}, err
+	}
+	logger.Info("Status updated", "gatewayclass", class.Name)
+
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1beta1.GatewayClass{}).
+		Watches(&source.Kind{Type: &proxyv1alpha1.Instance{}}, handler.EnqueueRequestsFromMapFunc(r.findClassesForInstance)).
+		Complete(r)
+}
+
+func (r *GatewayClassReconciler) findClassesForInstance(object client.Object) []reconcile.Request {
+	classes := &gatewayv1beta1.GatewayClassList{}
+	if err := r.List(context.Background(), classes); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, class := range classes.Items {
+		params := class.Spec.ParametersRef
+		if class.Spec.ControllerName != ControllerName || params == nil || params.Namespace == nil {
+			continue
+		}
+		if string(*params.Namespace) == object.GetNamespace() && params.Name == object.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: class.Name}})
+		}
+	}
+
+	return requests
+}
+
+// instanceSpec returns the spec of the Instance referenced by the parameters of the GatewayClass, which serves as
+// template for the Instances of its Gateways. A message is returned if the parameters are invalid.
+func instanceSpec(ctx context.Context, c client.Client, class *gatewayv1beta1.GatewayClass) (*proxyv1alpha1.InstanceSpec, string, error) {
+	params := class.Spec.ParametersRef
+	if params == nil {
+		return &proxyv1alpha1.InstanceSpec{Replicas: 1, Image: defaultImage}, "", nil
+	}
+
+	if string(params.Group) != proxyv1alpha1.GroupVersion.Group || params.Kind != "Instance" {
+		return nil, "parameters must reference an Instance", nil
+	}
+	if params.Namespace == nil {
+		return nil, "parameters must specify the namespace of the Instance", nil
+	}
+
+	instance := &proxyv1alpha1.Instance{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: string(*params.Namespace), Name: params.Name}, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Sprintf("instance %s/%s not found", *params.Namespace, params.Name), nil
+		}
+
+		return nil, "", err
+	}
+
+	return &instance.Spec, "", nil
+}
@@ -0,0 +1,199 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// listenerState tracks the validation result and the attached routes of a listener.
+type listenerState struct {
+	listener    gatewayv1beta1.Listener
+	conditions  []metav1.Condition
+	kinds       []string
+	attachments []attachment
+}
+
+// attachment is a route attached to a listener, restricted to the hostnames both of them accept.
+type attachment struct {
+	route     *route
+	hostnames []string
+}
+
+func (l *listenerState) valid() bool {
+	return meta.IsStatusConditionTrue(l.conditions, string(gatewayv1beta1.ListenerConditionReady))
+}
+
+func (l *listenerState) hostname() string {
+	return string(pointerDeref(l.listener.Hostname, ""))
+}
+
+// mode groups the listener protocols which can share a frontend and therefore a port.
+func (l *listenerState) mode() string {
+	switch l.listener.Protocol {
+	case gatewayv1beta1.TLSProtocolType:
+		if l.terminatesTLS() {
+			return "tls-terminate"
+		}
+		return "tls-passthrough"
+	default:
+		return string(l.listener.Protocol)
+	}
+}
+
+func (l *listenerState) terminatesTLS() bool {
+	switch l.listener.Protocol {
+	case gatewayv1beta1.HTTPSProtocolType:
+		return true
+	case gatewayv1beta1.TLSProtocolType:
+		return l.listener.TLS != nil && pointerDeref(l.listener.TLS.Mode, gatewayv1beta1.TLSModeTerminate) == gatewayv1beta1.TLSModeTerminate
+	default:
+		return false
+	}
+}
+
+// validateListeners checks the listeners of the gateway for unsupported protocols, conflicts and unresolvable
+// certificate references.
+func (r *Reconciler) validateListeners(ctx context.Context, gateway *gatewayv1beta1.Gateway) ([]*listenerState, error) {
+	var listeners []*listenerState
+
+	modes := map[gatewayv1beta1.PortNumber]map[string]bool{}
+	for _, listener := range gateway.Spec.Listeners {
+		state := &listenerState{listener: listener}
+		listeners = append(listeners, state)
+
+		if modes[listener.Port] == nil {
+			modes[listener.Port] = map[string]bool{}
+		}
+		modes[listener.Port][state.mode()] = true
+	}
+
+	hostnames := map[string]bool{}
+	for _, state := range listeners {
+		listener := state.listener
+		condition := func(conditionType gatewayv1beta1.ListenerConditionType, status metav1.ConditionStatus, reason gatewayv1beta1.ListenerConditionReason, message string) {
+			meta.SetStatusCondition(&state.conditions, metav1.Condition{
+				Type:               string(conditionType),
+				Status:             status,
+				Reason:             string(reason),
+				Message:            message,
+				ObservedGeneration: gateway.Generation,
+			})
+		}
+
+		state.kinds = supportedKinds(listener)
+		if len(state.kinds) == 0 {
+			condition(gatewayv1beta1.ListenerConditionDetached, metav1.ConditionTrue, gatewayv1beta1.ListenerReasonUnsupportedProtocol, fmt.Sprintf("protocol %s is not supported", listener.Protocol))
+		} else {
+			condition(gatewayv1beta1.ListenerConditionDetached, metav1.ConditionFalse, gatewayv1beta1.ListenerReasonAttached, "")
+		}
+
+		key := fmt.Sprintf("%d/%s", listener.Port, state.hostname())
+		if listener.Protocol == gatewayv1beta1.TCPProtocolType {
+			key = fmt.Sprintf("%d/", listener.Port)
+		}
+		switch {
+		case len(modes[listener.Port]) > 1:
+			condition(gatewayv1beta1.ListenerConditionConflicted, metav1.ConditionTrue, gatewayv1beta1.ListenerReasonProtocolConflict, "port is used by listeners with incompatible protocols")
+		case hostnames[key]:
+			condition(gatewayv1beta1.ListenerConditionConflicted, metav1.ConditionTrue, gatewayv1beta1.ListenerReasonHostnameConflict, "hostname is used by another listener on the same port")
+		default:
+			condition(gatewayv1beta1.ListenerConditionConflicted, metav1.ConditionFalse, gatewayv1beta1.ListenerReasonNoConflicts, "")
+		}
+		hostnames[key] = true
+
+		reason, message, err := r.resolveListenerRefs(ctx, gateway, state)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			condition(gatewayv1beta1.ListenerConditionResolvedRefs, metav1.ConditionFalse, reason, message)
+		} else {
+			condition(gatewayv1beta1.ListenerConditionResolvedRefs, metav1.ConditionTrue, gatewayv1beta1.ListenerReasonResolvedRefs, "")
+		}
+
+		if meta.IsStatusConditionFalse(state.conditions, string(gatewayv1beta1.ListenerConditionDetached)) &&
+			meta.IsStatusConditionFalse(state.conditions, string(gatewayv1beta1.ListenerConditionConflicted)) &&
+			meta.IsStatusConditionTrue(state.conditions, string(gatewayv1beta1.ListenerConditionResolvedRefs)) {
+			condition(gatewayv1beta1.ListenerConditionReady, metav1.ConditionTrue, gatewayv1beta1.ListenerReasonReady, "")
+		} else {
+			condition(gatewayv1beta1.ListenerConditionReady, metav1.ConditionFalse, gatewayv1beta1.ListenerReasonInvalid, "")
+		}
+	}
+
+	return listeners, nil
+}
+
+// resolveListenerRefs checks the allowed route kinds and the certificate references of the listener. It returns the
+// reason and message of the failed check.
+func (r *Reconciler) resolveListenerRefs(ctx context.Context, gateway *gatewayv1beta1.Gateway, state *listenerState) (gatewayv1beta1.ListenerConditionReason, string, error) {
+	listener := state.listener
+
+	if listener.AllowedRoutes != nil && len(listener.AllowedRoutes.Kinds) > 0 {
+		var kinds []string
+		for _, kind := range listener.AllowedRoutes.Kinds {
+			if pointerDeref(kind.Group, gatewayv1beta1.GroupName) != gatewayv1beta1.GroupName {
+				continue
+			}
+			for _, supported := range state.kinds {
+				if string(kind.Kind) == supported {
+					kinds = append(kinds, supported)
+				}
+			}
+		}
+		if len(kinds) < len(listener.AllowedRoutes.Kinds) {
+			state.kinds = kinds
+			return gatewayv1beta1.ListenerReasonInvalidRouteKinds, "route kinds are not supported by the listener", nil
+		}
+		state.kinds = kinds
+	}
+
+	if !state.terminatesTLS() {
+		return "", "", nil
+	}
+	if listener.TLS == nil || len(listener.TLS.CertificateRefs) == 0 {
+		return gatewayv1beta1.ListenerReasonInvalidCertificateRef, "listener requires a certificate", nil
+	}
+
+	for _, ref := range listener.TLS.CertificateRefs {
+		if pointerDeref(ref.Group, "") != "" || pointerDeref(ref.Kind, "Secret") != "Secret" {
+			return gatewayv1beta1.ListenerReasonInvalidCertificateRef, fmt.Sprintf("certificate %s is not a Secret", ref.Name), nil
+		}
+		if namespace := pointerDeref(ref.Namespace, gatewayv1beta1.Namespace(gateway.Namespace)); string(namespace) != gateway.Namespace {
+			return gatewayv1beta1.ListenerReasonRefNotPermitted, fmt.Sprintf("certificate %s must be in the namespace of the gateway", ref.Name), nil
+		}
+
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: gateway.Namespace, Name: string(ref.Name)}, secret); err != nil {
+			if errors.IsNotFound(err) {
+				return gatewayv1beta1.ListenerReasonInvalidCertificateRef, fmt.Sprintf("secret %s not found", ref.Name), nil
+			}
+
+			return "", "", err
+		}
+		if _, ok := secret.Data[corev1.TLSCertKey]; !ok {
+			return gatewayv1beta1.ListenerReasonInvalidCertificateRef, fmt.Sprintf("secret %s does not contain %s", ref.Name, corev1.TLSCertKey), nil
+		}
+	}
+
+	return "", "", nil
+}
+
+func supportedKinds(listener gatewayv1beta1.Listener) []string {
+	switch listener.Protocol {
+	case gatewayv1beta1.HTTPProtocolType, gatewayv1beta1.HTTPSProtocolType:
+		return []string{kindHTTPRoute}
+	case gatewayv1beta1.TLSProtocolType:
+		return []string{kindTLSRoute}
+	case gatewayv1beta1.TCPProtocolType:
+		return []string{kindTCPRoute}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,270 @@
+package gateway
+
+import (
+	"context"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+const (
+	kindHTTPRoute = "HTTPRoute"
+	kindTLSRoute  = "TLSRoute"
+	kindTCPRoute  = "TCPRoute"
+)
+
+// route is the version independent view of an HTTPRoute, TLSRoute or TCPRoute.
+type route struct {
+	object    client.Object
+	kind      string
+	parents   []parentRef
+	hostnames []string
+	rules     []routeRule
+}
+
+type parentRef struct {
+	ref         gatewayv1beta1.ParentReference
+	group       string
+	kind        string
+	namespace   string
+	name        string
+	sectionName string
+	port        int32
+}
+
+type routeRule struct {
+	// matches are only set for HTTPRoutes
+	matches  []gatewayv1beta1.HTTPRouteMatch
+	backends []backendRef
+}
+
+type backendRef struct {
+	group     string
+	kind      string
+	namespace string
+	name      string
+	port      int32
+	weight    int32
+}
+
+// listRoutes returns the routes of all namespaces which refer to the gateway or still carry a status of it, sorted by
+// age, as older routes take precedence in case of conflicts. Whether a listener accepts routes of other namespaces is
+// decided when they are attached.
+func (r *Reconciler) listRoutes(ctx context.Context, gateway *gatewayv1beta1.Gateway) ([]*route, error) {
+	all, err := r.listAllRoutes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []*route
+	for _, rt := range all {
+		if rt.refersTo(gateway) {
+			routes = append(routes, rt)
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, b := routes[i].object.GetCreationTimestamp(), routes[j].object.GetCreationTimestamp()
+		if !a.Equal(&b) {
+			return a.Before(&b)
+		}
+		return routes[i].object.GetName() < routes[j].object.GetName()
+	})
+
+	return routes, nil
+}
+
+func (r *Reconciler) listAllRoutes(ctx context.Context) ([]*route, error) {
+	var routes []*route
+
+	httpRoutes := &gatewayv1beta1.HTTPRouteList{}
+	if err := r.List(ctx, httpRoutes); err != nil {
+		return nil, err
+	}
+	for i := range httpRoutes.Items {
+		routes = append(routes, fromHTTPRoute(&httpRoutes.Items[i]))
+	}
+
+	// TLSRoutes and TCPRoutes are part of the experimental channel and might not be installed
+	tlsRoutes := &gatewayv1alpha2.TLSRouteList{}
+	if err := r.List(ctx, tlsRoutes); err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	for i := range tlsRoutes.Items {
+		routes = append(routes, fromTLSRoute(&tlsRoutes.Items[i]))
+	}
+
+	tcpRoutes := &gatewayv1alpha2.TCPRouteList{}
+	if err := r.List(ctx, tcpRoutes); err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	for i := range tcpRoutes.Items {
+		routes = append(routes, fromTCPRoute(&tcpRoutes.Items[i]))
+	}
+
+	return routes, nil
+}
+
+// refersTo reports whether the route has a parent reference to the gateway or a parent status of the operator for it,
+// which has to be removed once the reference is gone.
+func (rt *route) refersTo(gateway *gatewayv1beta1.Gateway) bool {
+	for _, parent := range rt.parents {
+		if parent.refersTo(gateway) {
+			return true
+		}
+	}
+	for _, parent := range parentStatuses(rt.object) {
+		if parent.ControllerName == ControllerName && newParentRef(rt.object.GetNamespace(), parent.ParentRef).refersTo(gateway) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// referencesNamespace reports whether one of the backend references of the route points into the namespace.
+func (rt *route) referencesNamespace(namespace string) bool {
+	for _, rule := range rt.rules {
+		for _, ref := range rule.backends {
+			if ref.namespace == namespace {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func fromHTTPRoute(object *gatewayv1beta1.HTTPRoute) *route {
+	r := &route{object: object, kind: kindHTTPRoute}
+
+	for _, ref := range object.Spec.ParentRefs {
+		r.parents = append(r.parents, newParentRef(object.Namespace, ref))
+	}
+	for _, hostname := range object.Spec.Hostnames {
+		r.hostnames = append(r.hostnames, string(hostname))
+	}
+
+	for _, rule := range object.Spec.Rules {
+		var backends []backendRef
+		for _, ref := range rule.BackendRefs {
+			backends = append(backends, backendRef{
+				group:     string(pointerDeref(ref.Group, "")),
+				kind:      string(pointerDeref(ref.Kind, "Service")),
+				namespace: string(pointerDeref(ref.Namespace, gatewayv1beta1.Namespace(object.Namespace))),
+				name:      string(ref.Name),
+				port:      int32(pointerDeref(ref.Port, 0)),
+				weight:    pointer.Int32Deref(ref.Weight, 1),
+			})
+		}
+
+		matches := rule.Matches
+		if len(matches) == 0 {
+			pathType := gatewayv1beta1.PathMatchPathPrefix
+			matches = []gatewayv1beta1.HTTPRouteMatch{{Path: &gatewayv1beta1.HTTPPathMatch{Type: &pathType, Value: pointer.String("/")}}}
+		}
+
+		r.rules = append(r.rules, routeRule{matches: matches, backends: backends})
+	}
+
+	return r
+}
+
+func fromTLSRoute(object *gatewayv1alpha2.TLSRoute) *route {
+	r := &route{object: object, kind: kindTLSRoute, parents: fromV1alpha2ParentRefs(object.Namespace, object.Spec.ParentRefs)}
+
+	for _, hostname := range object.Spec.Hostnames {
+		r.hostnames = append(r.hostnames, string(hostname))
+	}
+	for _, rule := range object.Spec.Rules {
+		r.rules = append(r.rules, routeRule{backends: fromV1alpha2BackendRefs(object.Namespace, rule.BackendRefs)})
+	}
+
+	return r
+}
+
+func fromTCPRoute(object *gatewayv1alpha2.TCPRoute) *route {
+	r := &route{object: object, kind: kindTCPRoute, parents: fromV1alpha2ParentRefs(object.Namespace, object.Spec.ParentRefs)}
+
+	for _, rule := range object.Spec.Rules {
+		r.rules = append(r.rules, routeRule{backends: fromV1alpha2BackendRefs(object.Namespace, rule.BackendRefs)})
+	}
+
+	return r
+}
+
+func fromV1alpha2ParentRefs(namespace string, refs []gatewayv1alpha2.ParentReference) []parentRef {
+	var parents []parentRef
+	for _, ref := range refs {
+		parents = append(parents, newParentRef(namespace, fromV1alpha2ParentRef(ref)))
+	}
+
+	return parents
+}
+
+func fromV1alpha2ParentRef(ref gatewayv1alpha2.ParentReference) gatewayv1beta1.ParentReference {
+	return gatewayv1beta1.ParentReference{
+		Group:       (*gatewayv1beta1.Group)(ref.Group),
+		Kind:        (*gatewayv1beta1.Kind)(ref.Kind),
+		Namespace:   (*gatewayv1beta1.Namespace)(ref.Namespace),
+		Name:        gatewayv1beta1.ObjectName(ref.Name),
+		SectionName: (*gatewayv1beta1.SectionName)(ref.SectionName),
+		Port:        (*gatewayv1beta1.PortNumber)(ref.Port),
+	}
+}
+
+func toV1alpha2ParentRef(ref gatewayv1beta1.ParentReference) gatewayv1alpha2.ParentReference {
+	return gatewayv1alpha2.ParentReference{
+		Group:       (*gatewayv1alpha2.Group)(ref.Group),
+		Kind:        (*gatewayv1alpha2.Kind)(ref.Kind),
+		Namespace:   (*gatewayv1alpha2.Namespace)(ref.Namespace),
+		Name:        gatewayv1alpha2.ObjectName(ref.Name),
+		SectionName: (*gatewayv1alpha2.SectionName)(ref.SectionName),
+		Port:        (*gatewayv1alpha2.PortNumber)(ref.Port),
+	}
+}
+
+// newParentRef applies the defaults of the parent reference of a route in the given namespace.
+func newParentRef(namespace string, ref gatewayv1beta1.ParentReference) parentRef {
+	return parentRef{
+		ref:         ref,
+		group:       string(pointerDeref(ref.Group, gatewayv1beta1.GroupName)),
+		kind:        string(pointerDeref(ref.Kind, "Gateway")),
+		namespace:   string(pointerDeref(ref.Namespace, gatewayv1beta1.Namespace(namespace))),
+		name:        string(ref.Name),
+		sectionName: string(pointerDeref(ref.SectionName, "")),
+		port:        int32(pointerDeref(ref.Port, 0)),
+	}
+}
+
+func (p parentRef) refersTo(gateway *gatewayv1beta1.Gateway) bool {
+	return p.group == gatewayv1beta1.GroupName && p.kind == "Gateway" && p.namespace == gateway.Namespace && p.name == gateway.Name
+}
+
+func fromV1alpha2BackendRefs(namespace string, refs []gatewayv1alpha2.BackendRef) []backendRef {
+	var backends []backendRef
+	for _, ref := range refs {
+		backends = append(backends, backendRef{
+			group:     string(pointerDeref(ref.Group, "")),
+			kind:      string(pointerDeref(ref.Kind, "Service")),
+			namespace: string(pointerDeref(ref.Namespace, gatewayv1alpha2.Namespace(namespace))),
+			name:      string(ref.Name),
+			port:      int32(pointerDeref(ref.Port, 0)),
+			weight:    pointer.Int32Deref(ref.Weight, 1),
+		})
+	}
+
+	return backends
+}
+
+func pointerDeref[T any](ptr *T, def T) T {
+	if ptr != nil {
+		return *ptr
+	}
+
+	return def
+}
@@ -0,0 +1,220 @@
+package gateway
+
+import (
+	"context"
+	"sort"
+
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// updateStatus writes the addresses, the conditions and the listener states of the gateway and the parent status of
+// its routes.
+func (r *Reconciler) updateStatus(ctx context.Context, gateway *gatewayv1beta1.Gateway, instance *proxyv1alpha1.Instance, routes []*route, t *translation) error {
+	logger := log.FromContext(ctx)
+
+	addresses, err := r.addresses(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	status := gateway.Status.DeepCopy()
+	status.Addresses = addresses
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               string(gatewayv1beta1.GatewayConditionScheduled),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gatewayv1beta1.GatewayReasonScheduled),
+		ObservedGeneration: gateway.Generation,
+	})
+
+	ready := metav1.Condition{
+		Type:               string(gatewayv1beta1.GatewayConditionReady),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gatewayv1beta1.GatewayReasonReady),
+		ObservedGeneration: gateway.Generation,
+	}
+
+	listeners := make([]gatewayv1beta1.ListenerStatus, 0, len(t.listeners))
+	for _, state := range t.listeners {
+		listener := gatewayv1beta1.ListenerStatus{
+			Name:           state.listener.Name,
+			SupportedKinds: []gatewayv1beta1.RouteGroupKind{},
+			AttachedRoutes: int32(len(state.attachments)),
+		}
+		for _, current := range status.Listeners {
+			if current.Name == listener.Name {
+				listener.Conditions = current.Conditions
+			}
+		}
+		for _, condition := range state.conditions {
+			meta.SetStatusCondition(&listener.Conditions, condition)
+		}
+		for _, kind := range state.kinds {
+			group := gatewayv1beta1.Group(gatewayv1beta1.GroupName)
+			listener.SupportedKinds = append(listener.SupportedKinds, gatewayv1beta1.RouteGroupKind{Group: &group, Kind: gatewayv1beta1.Kind(kind)})
+		}
+		listeners = append(listeners, listener)
+
+		if !state.valid() {
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = string(gatewayv1beta1.GatewayReasonListenersNotValid)
+			ready.Message = "one or more listeners are invalid"
+		}
+	}
+	status.Listeners = listeners
+
+	if ready.Status == metav1.ConditionTrue && len(addresses) == 0 {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = string(gatewayv1beta1.GatewayReasonAddressNotAssigned)
+		ready.Message = "the service of the instance has no address yet"
+	}
+	meta.SetStatusCondition(&status.Conditions, ready)
+
+	if !equality.Semantic.DeepEqual(&gateway.Status, status) {
+		gateway.Status = *status
+		if err := r.Status().Update(ctx, gateway); err != nil {
+			return err
+		}
+		logger.Info("Status updated", "gateway", gateway.Name)
+	}
+
+	for _, rt := range routes {
+		if err := r.updateRouteStatus(ctx, gateway, rt, t.parents[rt]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateRouteStatus replaces the parent status entries of the operator for the gateway. Entries of other gateways or
+// controllers are preserved.
+func (r *Reconciler) updateRouteStatus(ctx context.Context, gateway *gatewayv1beta1.Gateway, rt *route, conditions map[int][]metav1.Condition) error {
+	logger := log.FromContext(ctx)
+
+	current := parentStatuses(rt.object)
+
+	var parents []gatewayv1beta1.RouteParentStatus
+	for _, parent := range current {
+		if parent.ControllerName != ControllerName || !newParentRef(rt.object.GetNamespace(), parent.ParentRef).refersTo(gateway) {
+			parents = append(parents, parent)
+		}
+	}
+
+	indexes := make([]int, 0, len(conditions))
+	for idx := range conditions {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	for _, idx := range indexes {
+		parent := gatewayv1beta1.RouteParentStatus{
+			ParentRef:      rt.parents[idx].ref,
+			ControllerName: ControllerName,
+		}
+		for _, c := range current {
+			if c.ControllerName == ControllerName && equality.Semantic.DeepEqual(c.ParentRef, parent.ParentRef) {
+				// copied, as the conditions are compared with the current ones below
+				parent.Conditions = append([]metav1.Condition(nil), c.Conditions...)
+			}
+		}
+		for _, condition := range conditions[idx] {
+			meta.SetStatusCondition(&parent.Conditions, condition)
+		}
+		parents = append(parents, parent)
+	}
+
+	if equality.Semantic.DeepEqual(current, parents) {
+		return nil
+	}
+
+	setParentStatuses(rt.object, parents)
+	if err := r.Status().Update(ctx, rt.object); err != nil {
+		return err
+	}
+	logger.Info("Status updated", rt.kind, rt.object.GetName())
+
+	return nil
+}
+
+// addresses returns the load balancer or cluster addresses of the instance service.
+func (r *Reconciler) addresses(ctx context.Context, instance *proxyv1alpha1.Instance) ([]gatewayv1beta1.GatewayAddress, error) {
+	service := &corev1.Service{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: utils.GetServiceName(instance)}, service); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	ipAddress := gatewayv1beta1.IPAddressType
+	hostname := gatewayv1beta1.HostnameAddressType
+
+	var addresses []gatewayv1beta1.GatewayAddress
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			addresses = append(addresses, gatewayv1beta1.GatewayAddress{Type: &ipAddress, Value: ingress.IP})
+		}
+		if ingress.Hostname != "" {
+			addresses = append(addresses, gatewayv1beta1.GatewayAddress{Type: &hostname, Value: ingress.Hostname})
+		}
+	}
+	if len(addresses) == 0 && service.Spec.ClusterIP != "" && service.Spec.ClusterIP != corev1.ClusterIPNone {
+		addresses = append(addresses, gatewayv1beta1.GatewayAddress{Type: &ipAddress, Value: service.Spec.ClusterIP})
+	}
+
+	return addresses, nil
+}
+
+func parentStatuses(object client.Object) []gatewayv1beta1.RouteParentStatus {
+	var parents []gatewayv1beta1.RouteParentStatus
+
+	switch o := object.(type) {
+	case *gatewayv1beta1.HTTPRoute:
+		parents = append(parents, o.Status.Parents...)
+	case *gatewayv1alpha2.TLSRoute:
+		for _, parent := range o.Status.Parents {
+			parents = append(parents, fromV1alpha2ParentStatus(parent))
+		}
+	case *gatewayv1alpha2.TCPRoute:
+		for _, parent := range o.Status.Parents {
+			parents = append(parents, fromV1alpha2ParentStatus(parent))
+		}
+	}
+
+	return parents
+}
+
+func setParentStatuses(object client.Object, parents []gatewayv1beta1.RouteParentStatus) {
+	var v1alpha2Parents []gatewayv1alpha2.RouteParentStatus
+	for _, parent := range parents {
+		v1alpha2Parents = append(v1alpha2Parents, gatewayv1alpha2.RouteParentStatus{
+			ParentRef:      toV1alpha2ParentRef(parent.ParentRef),
+			ControllerName: gatewayv1alpha2.GatewayController(parent.ControllerName),
+			Conditions:     parent.Conditions,
+		})
+	}
+
+	switch o := object.(type) {
+	case *gatewayv1beta1.HTTPRoute:
+		o.Status.Parents = parents
+	case *gatewayv1alpha2.TLSRoute:
+		o.Status.Parents = v1alpha2Parents
+	case *gatewayv1alpha2.TCPRoute:
+		o.Status.Parents = v1alpha2Parents
+	}
+}
+
+func fromV1alpha2ParentStatus(parent gatewayv1alpha2.RouteParentStatus) gatewayv1beta1.RouteParentStatus {
+	return gatewayv1beta1.RouteParentStatus{
+		ParentRef:      fromV1alpha2ParentRef(parent.ParentRef),
+		ControllerName: gatewayv1beta1.GatewayController(parent.ControllerName),
+		Conditions:     parent.Conditions,
+	}
+}
@@ -0,0 +1,826 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	"github.com/six-group/haproxy-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+const (
+	gatewayLabel = "proxy.haproxy.com/gateway"
+	inspectDelay = 5 * time.Second
+	maxWeight    = 256
+)
+
+// translation is the configuration generated for a gateway and the resulting status of its listeners and routes.
+type translation struct {
+	frontends []*configv1alpha1.Frontend
+	backends  []*configv1alpha1.Backend
+	listeners []*listenerState
+	// parents contains the conditions of each route per index of a parent reference pointing to the gateway.
+	parents map[*route]map[int][]metav1.Condition
+}
+
+// translate generates one frontend per listener port and one backend per route rule.
+func (r *Reconciler) translate(ctx context.Context, gateway *gatewayv1beta1.Gateway, routes []*route) (*translation, error) {
+	listeners, err := r.validateListeners(ctx, gateway)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &translation{
+		listeners: listeners,
+		parents:   map[*route]map[int][]metav1.Condition{},
+	}
+	objectLabels := map[string]string{gatewayLabel: gateway.Name}
+
+	namespaceLabels := map[string]map[string]string{}
+	for _, rt := range routes {
+		services, resolved, err := r.resolveBackends(ctx, gateway, rt)
+		if err != nil {
+			return nil, err
+		}
+
+		routeNamespace := rt.object.GetNamespace()
+		if _, ok := namespaceLabels[routeNamespace]; !ok {
+			namespace := &corev1.Namespace{}
+			if err := r.Get(ctx, client.ObjectKey{Name: routeNamespace}, namespace); client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			namespaceLabels[routeNamespace] = namespace.Labels
+		}
+
+		for idx, parent := range rt.parents {
+			if !parent.refersTo(gateway) {
+				continue
+			}
+
+			accepted := unsupportedMatch(rt)
+			if accepted == nil {
+				condition := attach(rt, parent, listeners, gateway.Namespace, namespaceLabels[routeNamespace])
+				accepted = &condition
+			}
+			accepted.ObservedGeneration = rt.object.GetGeneration()
+			resolved.ObservedGeneration = rt.object.GetGeneration()
+
+			if t.parents[rt] == nil {
+				t.parents[rt] = map[int][]metav1.Condition{}
+			}
+			t.parents[rt][idx] = []metav1.Condition{*accepted, resolved}
+		}
+
+		if !isAttached(rt, listeners) {
+			continue
+		}
+
+		mode := "tcp"
+		if rt.kind == kindHTTPRoute {
+			mode = "http"
+		}
+		for idx, refs := range services {
+			t.backends = append(t.backends, &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      backendName(gateway, rt, idx),
+					Namespace: gateway.Namespace,
+					Labels:    objectLabels,
+				},
+				Spec: configv1alpha1.BackendSpec{
+					BaseSpec:    configv1alpha1.BaseSpec{Mode: mode},
+					ServiceRefs: refs,
+				},
+			})
+		}
+	}
+
+	ports := map[gatewayv1beta1.PortNumber][]*listenerState{}
+	for _, state := range listeners {
+		if state.valid() {
+			ports[state.listener.Port] = append(ports[state.listener.Port], state)
+		}
+	}
+
+	for _, port := range sortedPorts(ports) {
+		states := ports[port]
+
+		defaultBackend := &configv1alpha1.Backend{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d-default", gateway.Name, port),
+				Namespace: gateway.Namespace,
+				Labels:    objectLabels,
+			},
+			Spec: configv1alpha1.BackendSpec{
+				BaseSpec: configv1alpha1.BaseSpec{Mode: "tcp"},
+			},
+		}
+
+		frontend := &configv1alpha1.Frontend{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", gateway.Name, port),
+				Namespace: gateway.Namespace,
+				Labels:    objectLabels,
+			},
+			Spec: configv1alpha1.FrontendSpec{
+				BaseSpec: configv1alpha1.BaseSpec{Mode: "tcp"},
+				Binds: []configv1alpha1.Bind{
+					{Name: string(states[0].listener.Name), Port: int64(port)},
+				},
+				DefaultBackend: corev1.LocalObjectReference{Name: defaultBackend.Name},
+			},
+		}
+
+		if states[0].terminatesTLS() {
+			frontend.Spec.Binds[0].SSL = &configv1alpha1.SSL{Enabled: true}
+			frontend.Spec.Binds[0].SSLCertificateList = &configv1alpha1.CertificateList{
+				Name:     frontend.Name,
+				Elements: certificates(gateway, states),
+			}
+		}
+
+		switch states[0].listener.Protocol {
+		case gatewayv1beta1.HTTPProtocolType, gatewayv1beta1.HTTPSProtocolType:
+			frontend.Spec.Mode = "http"
+			frontend.Spec.BackendSwitching = httpSwitchingRules(gateway, routes, states)
+
+			defaultBackend.Spec.Mode = "http"
+			defaultBackend.Spec.HTTPRequest = &configv1alpha1.HTTPRequestRules{
+				Return: &configv1alpha1.HTTPReturn{
+					Status: pointer.Int64(404),
+					Content: configv1alpha1.HTTPReturnContent{
+						Type:   "text/plain",
+						Format: "string",
+						Value:  "Not Found",
+					},
+				},
+			}
+		case gatewayv1beta1.TLSProtocolType:
+			fetch := "ssl_fc_sni"
+			if !states[0].terminatesTLS() {
+				fetch = "req_ssl_sni"
+				frontend.Spec.TCPRequest = []configv1alpha1.TCPRequestRule{
+					{Type: "inspect-delay", Timeout: &metav1.Duration{Duration: inspectDelay}},
+					{Type: "content", Action: pointer.String("accept"), Rule: configv1alpha1.Rule{ConditionType: "if", Condition: "{ req_ssl_hello_type 1 }"}},
+				}
+			}
+			frontend.Spec.BackendSwitching = sniSwitchingRules(gateway, routes, states, fetch)
+		case gatewayv1beta1.TCPProtocolType:
+			if attachments := states[0].attachments; len(attachments) > 0 {
+				frontend.Spec.DefaultBackend.Name = backendName(gateway, attachments[0].route, 0)
+				defaultBackend = nil
+			}
+		}
+
+		t.frontends = append(t.frontends, frontend)
+		if defaultBackend != nil {
+			t.backends = append(t.backends, defaultBackend)
+		}
+	}
+
+	return t, nil
+}
+
+// unsupportedMatch returns the Accepted condition of a route with a match which cannot be translated, or nil.
+func unsupportedMatch(rt *route) *metav1.Condition {
+	for _, rule := range rt.rules {
+		for _, match := range rule.matches {
+			if message := validateMatch(match); message != "" {
+				return &metav1.Condition{
+					Type:    string(gatewayv1beta1.RouteConditionAccepted),
+					Status:  metav1.ConditionFalse,
+					Reason:  string(gatewayv1beta1.RouteReasonUnsupportedValue),
+					Message: message,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// attach attaches the route to the listeners selected by the parent reference and returns the Accepted condition.
+func attach(rt *route, parent parentRef, listeners []*listenerState, gatewayNamespace string, namespaceLabels map[string]string) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    string(gatewayv1beta1.RouteConditionAccepted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(gatewayv1beta1.RouteReasonNotAllowedByListeners),
+		Message: "no listener of the gateway accepts the route",
+	}
+
+	for _, state := range listeners {
+		listener := state.listener
+		if parent.sectionName != "" && parent.sectionName != string(listener.Name) {
+			continue
+		}
+		if parent.port != 0 && parent.port != int32(listener.Port) {
+			continue
+		}
+		if !state.valid() || !containsString(state.kinds, rt.kind) || !namespaceAllowed(listener, gatewayNamespace, rt.object.GetNamespace(), namespaceLabels) {
+			continue
+		}
+
+		hostnames := rt.hostnames
+		if rt.kind != kindTCPRoute {
+			var ok bool
+			if hostnames, ok = intersectHostnames(state.hostname(), rt.hostnames); !ok {
+				condition.Reason = string(gatewayv1beta1.RouteReasonNoMatchingListenerHostname)
+				condition.Message = "no hostname of the route matches the listeners"
+				continue
+			}
+		}
+
+		if attached(state, rt) {
+			condition.Status = metav1.ConditionTrue
+			continue
+		}
+		if rt.kind == kindTCPRoute && len(state.attachments) > 0 {
+			condition.Reason = string(gatewayv1beta1.RouteReasonUnsupportedValue)
+			condition.Message = "the listener already forwards to another TCPRoute"
+			continue
+		}
+
+		state.attachments = append(state.attachments, attachment{route: rt, hostnames: hostnames})
+		condition.Status = metav1.ConditionTrue
+	}
+
+	if condition.Status == metav1.ConditionTrue {
+		condition.Reason = string(gatewayv1beta1.RouteReasonAccepted)
+		condition.Message = ""
+	}
+
+	return condition
+}
+
+// resolveBackends resolves the Service references of each rule and returns them together with the ResolvedRefs
+// condition. References which cannot be resolved are skipped. Services in other namespaces than the route require a
+// ReferenceGrant, Services in other namespaces than the gateway are referenced with their namespace.
+func (r *Reconciler) resolveBackends(ctx context.Context, gateway *gatewayv1beta1.Gateway, rt *route) ([][]configv1alpha1.ServiceReference, metav1.Condition, error) {
+	condition := metav1.Condition{
+		Type:   string(gatewayv1beta1.RouteConditionResolvedRefs),
+		Status: metav1.ConditionTrue,
+		Reason: string(gatewayv1beta1.RouteReasonResolvedRefs),
+	}
+	fail := func(reason gatewayv1beta1.RouteConditionReason, message string) {
+		if condition.Status == metav1.ConditionTrue {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = string(reason)
+			condition.Message = message
+		}
+	}
+
+	var services [][]configv1alpha1.ServiceReference
+	for _, rule := range rt.rules {
+		var refs []configv1alpha1.ServiceReference
+		var weights []int32
+
+		for _, ref := range rule.backends {
+			switch {
+			case ref.group != "" || ref.kind != "Service":
+				fail(gatewayv1beta1.RouteReasonInvalidKind, fmt.Sprintf("backend %s is not a Service", ref.name))
+				continue
+			case ref.port == 0:
+				fail(gatewayv1beta1.RouteReasonBackendNotFound, fmt.Sprintf("backend %s requires a port", ref.name))
+				continue
+			case ref.weight == 0:
+				continue
+			}
+
+			if ref.namespace != rt.object.GetNamespace() {
+				granted, err := r.referenceGranted(ctx, rt, ref)
+				if err != nil {
+					return nil, condition, err
+				}
+				if !granted {
+					fail(gatewayv1beta1.RouteReasonRefNotPermitted, fmt.Sprintf("backend %s/%s is not permitted by a ReferenceGrant", ref.namespace, ref.name))
+					continue
+				}
+			}
+
+			service := &corev1.Service{}
+			if err := r.Get(ctx, client.ObjectKey{Namespace: ref.namespace, Name: ref.name}, service); err != nil {
+				if errors.IsNotFound(err) {
+					fail(gatewayv1beta1.RouteReasonBackendNotFound, fmt.Sprintf("service %s not found", ref.name))
+					continue
+				}
+
+				return nil, condition, err
+			}
+
+			found := false
+			for _, port := range service.Spec.Ports {
+				if port.Port == ref.port {
+					// the endpoint slices only carry the name of the service port
+					serviceRef := configv1alpha1.ServiceReference{Name: ref.name, Port: port.Name}
+					if ref.namespace != gateway.Namespace {
+						serviceRef.Namespace = ref.namespace
+					}
+					refs = append(refs, serviceRef)
+					weights = append(weights, ref.weight)
+					found = true
+					break
+				}
+			}
+			if !found {
+				fail(gatewayv1beta1.RouteReasonBackendNotFound, fmt.Sprintf("service %s has no port %d", ref.name, ref.port))
+			}
+		}
+
+		for idx, weight := range scaleWeights(weights) {
+			refs[idx].Weight = pointer.Int64(int64(weight))
+		}
+
+		services = append(services, refs)
+	}
+
+	// TLSRoutes and TCPRoutes forward to a single backend
+	if rt.kind != kindHTTPRoute {
+		var refs []configv1alpha1.ServiceReference
+		for _, rule := range services {
+			refs = append(refs, rule...)
+		}
+		services = [][]configv1alpha1.ServiceReference{refs}
+	}
+
+	return services, condition, nil
+}
+
+// referenceGranted reports whether a ReferenceGrant in the namespace of the Service permits the reference of the route.
+func (r *Reconciler) referenceGranted(ctx context.Context, rt *route, ref backendRef) (bool, error) {
+	grants := &gatewayv1alpha2.ReferenceGrantList{}
+	if err := r.List(ctx, grants, client.InNamespace(ref.namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, grant := range grants.Items {
+		from := false
+		for _, f := range grant.Spec.From {
+			if string(f.Group) == gatewayv1beta1.GroupName && string(f.Kind) == rt.kind && string(f.Namespace) == rt.object.GetNamespace() {
+				from = true
+				break
+			}
+		}
+		if !from {
+			continue
+		}
+
+		for _, to := range grant.Spec.To {
+			if to.Group == "" && to.Kind == "Service" && (to.Name == nil || string(*to.Name) == ref.name) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// httpSwitchingRules returns the use_backend rules of the HTTPRoutes attached to the listeners, ordered by the
+// precedence defined by the Gateway API.
+func httpSwitchingRules(gateway *gatewayv1beta1.Gateway, routes []*route, states []*listenerState) []configv1alpha1.BackendSwitchingRule {
+	type entry struct {
+		host    string
+		match   gatewayv1beta1.HTTPRouteMatch
+		backend string
+		order   []int
+	}
+
+	var entries []entry
+	for _, state := range states {
+		for _, attachment := range state.attachments {
+			hostnames := attachment.hostnames
+			if len(hostnames) == 0 {
+				hostnames = []string{""}
+			}
+
+			for ruleIdx, rule := range attachment.route.rules {
+				for matchIdx, match := range rule.matches {
+					for _, host := range hostnames {
+						entries = append(entries, entry{
+							host:    host,
+							match:   match,
+							backend: backendName(gateway, attachment.route, ruleIdx),
+							order:   []int{indexOf(routes, attachment.route), ruleIdx, matchIdx},
+						})
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if c := compareHosts(a.host, b.host); c != 0 {
+			return c < 0
+		}
+		if c := comparePaths(a.match.Path, b.match.Path); c != 0 {
+			return c < 0
+		}
+		if (a.match.Method != nil) != (b.match.Method != nil) {
+			return a.match.Method != nil
+		}
+		if len(a.match.Headers) != len(b.match.Headers) {
+			return len(a.match.Headers) > len(b.match.Headers)
+		}
+		if len(a.match.QueryParams) != len(b.match.QueryParams) {
+			return len(a.match.QueryParams) > len(b.match.QueryParams)
+		}
+		for k := range a.order {
+			if a.order[k] != b.order[k] {
+				return a.order[k] < b.order[k]
+			}
+		}
+		return false
+	})
+
+	var rules []configv1alpha1.BackendSwitchingRule
+	for _, e := range entries {
+		for _, conditions := range matchConditions(e.match) {
+			if host := hostCondition("req.hdr(host),field(1,:)", e.host); host != "" {
+				conditions = append([]string{host}, conditions...)
+			}
+			rules = append(rules, switchingRule(e.backend, conditions))
+		}
+	}
+
+	return rules
+}
+
+// sniSwitchingRules returns the use_backend rules of the TLSRoutes attached to the listeners based on the server name
+// indication of the TLS handshake.
+func sniSwitchingRules(gateway *gatewayv1beta1.Gateway, routes []*route, states []*listenerState, fetch string) []configv1alpha1.BackendSwitchingRule {
+	type entry struct {
+		host    string
+		backend string
+		order   int
+	}
+
+	var entries []entry
+	for _, state := range states {
+		for _, attachment := range state.attachments {
+			hostnames := attachment.hostnames
+			if len(hostnames) == 0 {
+				hostnames = []string{""}
+			}
+
+			for _, host := range hostnames {
+				entries = append(entries, entry{host: host, backend: backendName(gateway, attachment.route, 0), order: indexOf(routes, attachment.route)})
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if c := compareHosts(entries[i].host, entries[j].host); c != 0 {
+			return c < 0
+		}
+		return entries[i].order < entries[j].order
+	})
+
+	var rules []configv1alpha1.BackendSwitchingRule
+	for _, e := range entries {
+		var conditions []string
+		if host := hostCondition(fetch, e.host); host != "" {
+			conditions = append(conditions, host)
+		}
+		rules = append(rules, switchingRule(e.backend, conditions))
+	}
+
+	return rules
+}
+
+// matchConditions returns the alternative condition lists for an HTTPRoute match. Path prefixes have to match whole
+// path elements, which requires an exact and a prefix comparison. The match must have been checked by validateMatch.
+func matchConditions(match gatewayv1beta1.HTTPRouteMatch) [][]string {
+	var common []string
+	if match.Method != nil {
+		common = append(common, fmt.Sprintf("{ method %s }", *match.Method))
+	}
+	for _, header := range match.Headers {
+		common = append(common, fmt.Sprintf("{ req.hdr(%s) -m %s %s }", header.Name, matchMethod(header.Type != nil && *header.Type == gatewayv1beta1.HeaderMatchRegularExpression), utils.QuoteACLValue(header.Value)))
+	}
+	for _, param := range match.QueryParams {
+		common = append(common, fmt.Sprintf("{ url_param(%s) -m %s %s }", param.Name, matchMethod(param.Type != nil && *param.Type == gatewayv1beta1.QueryParamMatchRegularExpression), utils.QuoteACLValue(param.Value)))
+	}
+
+	var paths [][]string
+	if match.Path != nil {
+		value := pointer.StringDeref(match.Path.Value, "/")
+		switch pointerDeref(match.Path.Type, gatewayv1beta1.PathMatchPathPrefix) {
+		case gatewayv1beta1.PathMatchExact:
+			paths = append(paths, []string{fmt.Sprintf("{ path -m str %s }", utils.QuoteACLValue(value))})
+		case gatewayv1beta1.PathMatchRegularExpression:
+			paths = append(paths, []string{fmt.Sprintf("{ path -m reg %s }", utils.QuoteACLValue(value))})
+		default:
+			if prefix := strings.TrimSuffix(value, "/"); prefix != "" {
+				paths = append(paths, []string{fmt.Sprintf("{ path -m str %s }", utils.QuoteACLValue(prefix))}, []string{fmt.Sprintf("{ path -m beg %s }", utils.QuoteACLValue(prefix+"/"))})
+			}
+		}
+	}
+	if len(paths) == 0 {
+		paths = [][]string{nil}
+	}
+
+	result := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		result = append(result, append(path, common...))
+	}
+
+	return result
+}
+
+// httpMethods are the methods supported by HTTPRoute matches.
+var httpMethods = map[gatewayv1beta1.HTTPMethod]bool{
+	gatewayv1beta1.HTTPMethodGet: true, gatewayv1beta1.HTTPMethodHead: true, gatewayv1beta1.HTTPMethodPost: true,
+	gatewayv1beta1.HTTPMethodPut: true, gatewayv1beta1.HTTPMethodDelete: true, gatewayv1beta1.HTTPMethodConnect: true,
+	gatewayv1beta1.HTTPMethodOptions: true, gatewayv1beta1.HTTPMethodTrace: true, gatewayv1beta1.HTTPMethodPatch: true,
+}
+
+// aclArgumentPattern matches the header and query parameter names which can be passed unquoted to sample fetches.
+var aclArgumentPattern = regexp.MustCompile("^[A-Za-z0-9!#%&*+.^_|~-]+$")
+
+// validateMatch returns why an HTTPRoute match cannot be expressed as an HAProxy condition, or an empty string.
+func validateMatch(match gatewayv1beta1.HTTPRouteMatch) string {
+	if match.Method != nil && !httpMethods[*match.Method] {
+		return fmt.Sprintf("method %q is not supported", *match.Method)
+	}
+	for _, header := range match.Headers {
+		if !aclArgumentPattern.MatchString(string(header.Name)) {
+			return fmt.Sprintf("header name %q is not supported", header.Name)
+		}
+		if header.Value == "" || !utils.ValidACLValue(header.Value) {
+			return fmt.Sprintf("value %q of header %s is not supported", header.Value, header.Name)
+		}
+	}
+	for _, param := range match.QueryParams {
+		if !aclArgumentPattern.MatchString(param.Name) {
+			return fmt.Sprintf("query parameter name %q is not supported", param.Name)
+		}
+		if param.Value == "" || !utils.ValidACLValue(param.Value) {
+			return fmt.Sprintf("value %q of query parameter %s is not supported", param.Value, param.Name)
+		}
+	}
+	if match.Path != nil {
+		if value := pointer.StringDeref(match.Path.Value, "/"); value == "" || !utils.ValidACLValue(value) {
+			return fmt.Sprintf("path %q is not supported", value)
+		}
+	}
+
+	return ""
+}
+
+func matchMethod(regex bool) string {
+	if regex {
+		return "reg"
+	}
+
+	return "str"
+}
+
+// hostCondition matches the host case-insensitively, as are host names.
+func hostCondition(fetch, host string) string {
+	switch {
+	case host == "":
+		return ""
+	case strings.HasPrefix(host, "*."):
+		return fmt.Sprintf("{ %s -i -m end %s }", fetch, strings.TrimPrefix(host, "*"))
+	default:
+		return fmt.Sprintf("{ %s -i -m str %s }", fetch, host)
+	}
+}
+
+func switchingRule(backend string, conditions []string) configv1alpha1.BackendSwitchingRule {
+	rule := configv1alpha1.BackendSwitchingRule{
+		Backend: configv1alpha1.BackendReference{Name: pointer.String(backend)},
+	}
+	if len(conditions) > 0 {
+		rule.ConditionType = "if"
+		rule.Condition = strings.Join(conditions, " ")
+	}
+
+	return rule
+}
+
+// compareHosts orders exact hostnames before wildcards, longer wildcards before shorter ones and routes without
+// hostname last.
+func compareHosts(a, b string) int {
+	rank := func(host string) int {
+		switch {
+		case host == "":
+			return 0
+		case strings.HasPrefix(host, "*."):
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	if rank(a) != rank(b) {
+		return rank(b) - rank(a)
+	}
+	if rank(a) == 1 && len(a) != len(b) {
+		return len(b) - len(a)
+	}
+
+	return 0
+}
+
+// comparePaths orders exact paths before prefixes, longer prefixes before shorter ones and regular expressions last.
+func comparePaths(a, b *gatewayv1beta1.HTTPPathMatch) int {
+	rank := func(path *gatewayv1beta1.HTTPPathMatch) (int, int) {
+		if path == nil {
+			return 1, 1
+		}
+
+		value := pointer.StringDeref(path.Value, "/")
+		switch pointerDeref(path.Type, gatewayv1beta1.PathMatchPathPrefix) {
+		case gatewayv1beta1.PathMatchExact:
+			return 2, len(value)
+		case gatewayv1beta1.PathMatchRegularExpression:
+			return 0, 0
+		default:
+			return 1, len(value)
+		}
+	}
+
+	ra, la := rank(a)
+	rb, lb := rank(b)
+	if ra != rb {
+		return rb - ra
+	}
+
+	return lb - la
+}
+
+// intersectHostnames returns the hostnames matched by both the listener and the route, and false if there are none.
+func intersectHostnames(listener string, hostnames []string) ([]string, bool) {
+	if listener == "" {
+		return hostnames, true
+	}
+	if len(hostnames) == 0 {
+		return []string{listener}, true
+	}
+
+	var result []string
+	for _, hostname := range hostnames {
+		switch {
+		case hostnameMatches(listener, hostname):
+			result = append(result, hostname)
+		case hostnameMatches(hostname, listener):
+			result = append(result, listener)
+		}
+	}
+
+	return result, len(result) > 0
+}
+
+func hostnameMatches(pattern, hostname string) bool {
+	if pattern == hostname {
+		return true
+	}
+
+	return strings.HasPrefix(pattern, "*.") && strings.HasSuffix(hostname, strings.TrimPrefix(pattern, "*"))
+}
+
+// namespaceAllowed reports whether the listener accepts routes of the namespace with the given labels.
+func namespaceAllowed(listener gatewayv1beta1.Listener, gatewayNamespace, routeNamespace string, namespaceLabels map[string]string) bool {
+	from := gatewayv1beta1.NamespacesFromSame
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil {
+		from = pointerDeref(listener.AllowedRoutes.Namespaces.From, gatewayv1beta1.NamespacesFromSame)
+	}
+
+	switch from {
+	case gatewayv1beta1.NamespacesFromAll:
+		return true
+	case gatewayv1beta1.NamespacesFromSame:
+		return routeNamespace == gatewayNamespace
+	}
+
+	namespaces := listener.AllowedRoutes.Namespaces
+	if namespaces.Selector == nil {
+		return false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(namespaces.Selector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(namespaceLabels))
+}
+
+// scaleWeights scales the weights of the backend references down to the maximum server weight of HAProxy.
+func scaleWeights(weights []int32) []int32 {
+	var highest int32
+	for _, weight := range weights {
+		if weight > highest {
+			highest = weight
+		}
+	}
+	if highest <= maxWeight {
+		return weights
+	}
+
+	scaled := make([]int32, len(weights))
+	for idx, weight := range weights {
+		scaled[idx] = int32(int64(weight) * maxWeight / int64(highest))
+		if scaled[idx] == 0 {
+			scaled[idx] = 1
+		}
+	}
+
+	return scaled
+}
+
+func certificates(gateway *gatewayv1beta1.Gateway, states []*listenerState) []configv1alpha1.CertificateListElement {
+	var elements []configv1alpha1.CertificateListElement
+	for _, state := range states {
+		for _, ref := range state.listener.TLS.CertificateRefs {
+			elements = append(elements, configv1alpha1.CertificateListElement{
+				Certificate: configv1alpha1.SSLCertificate{
+					Name: fmt.Sprintf("%s-%s", gateway.Namespace, ref.Name),
+					ValueFrom: []configv1alpha1.SSLCertificateValueFrom{
+						{SecretKeyRef: utils.SecretKeySelector(string(ref.Name), corev1.TLSCertKey)},
+						{SecretKeyRef: utils.SecretKeySelector(string(ref.Name), corev1.TLSPrivateKeyKey)},
+					},
+				},
+				SNIFilter: state.hostname(),
+			})
+		}
+	}
+
+	return elements
+}
+
+func backendName(gateway *gatewayv1beta1.Gateway, rt *route, rule int) string {
+	if rt.kind != kindHTTPRoute {
+		return fmt.Sprintf("%s-%s-%s", gateway.Name, strings.ToLower(rt.kind), rt.object.GetName())
+	}
+
+	return fmt.Sprintf("%s-%s-%s-%d", gateway.Name, strings.ToLower(rt.kind), rt.object.GetName(), rule)
+}
+
+func isAttached(rt *route, listeners []*listenerState) bool {
+	for _, state := range listeners {
+		if attached(state, rt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func attached(state *listenerState, rt *route) bool {
+	for _, attachment := range state.attachments {
+		if attachment.route == rt {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedPorts(ports map[gatewayv1beta1.PortNumber][]*listenerState) []gatewayv1beta1.PortNumber {
+	result := make([]gatewayv1beta1.PortNumber, 0, len(ports))
+	for port := range ports {
+		result = append(result, port)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+
+	return result
+}
+
+func indexOf(routes []*route, rt *route) int {
+	for idx := range routes {
+		if routes[idx] == rt {
+			return idx
+		}
+	}
+
+	return len(routes)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
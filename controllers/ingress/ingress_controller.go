@@ -0,0 +1,266 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// ControllerName is the value of spec.controller of the IngressClasses served by the operator.
+	ControllerName = "proxy.haproxy.com/ingress-controller"
+
+	ingressClassLabel      = "proxy.haproxy.com/ingress-class"
+	ingressClassAnnotation = "kubernetes.io/ingress.class"
+)
+
+// Reconciler translates the Ingresses of an IngressClass bound to an Instance into a Frontend and Backends.
+type Reconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingressclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=config.haproxy.com,resources=frontends;backends,verbs=get;list;watch;create;update;patch;delete
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	class := &networkingv1.IngressClass{}
+	if err := r.Get(ctx, req.NamespacedName, class); err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, r.cleanup(ctx, req.Name, nil, nil)
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	if class.Spec.Controller != ControllerName {
+		return reconcile.Result{}, r.cleanup(ctx, class.Name, nil, nil)
+	}
+
+	instance, err := r.getInstance(ctx, class)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if instance == nil {
+		return reconcile.Result{}, r.cleanup(ctx, class.Name, nil, nil)
+	}
+
+	ingresses, err := r.listIngresses(ctx, class, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(ingresses) == 0 {
+		return reconcile.Result{}, r.cleanup(ctx, class.Name, nil, nil)
+	}
+
+	frontend, backends, err := r.translate(ctx, class, instance, ingresses)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for _, backend := range backends {
+		object := &configv1alpha1.Backend{ObjectMeta: metav1.ObjectMeta{Name: backend.Name, Namespace: backend.Namespace}}
+		if err := r.apply(ctx, instance, object, func() {
+			object.Labels = backend.Labels
+			object.Spec = backend.Spec
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	object := &configv1alpha1.Frontend{ObjectMeta: metav1.ObjectMeta{Name: frontend.Name, Namespace: frontend.Namespace}}
+	if err := r.apply(ctx, instance, object, func() {
+		object.Labels = frontend.Labels
+		object.Spec = frontend.Spec
+	}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.cleanup(ctx, class.Name, frontend, backends); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, r.updateStatus(ctx, instance, ingresses)
+}
+
+// getInstance resolves the Instance referenced by the parameters of the IngressClass.
+func (r *Reconciler) getInstance(ctx context.Context, class *networkingv1.IngressClass) (*proxyv1alpha1.Instance, error) {
+	logger := log.FromContext(ctx)
+
+	params := class.Spec.Parameters
+	if params == nil || pointer.StringDeref(params.APIGroup, "") != proxyv1alpha1.GroupVersion.Group || params.Kind != "Instance" {
+		logger.Info("IngressClass parameters must reference an Instance", "ingressclass", class.Name)
+		return nil, nil
+	}
+	if params.Namespace == nil {
+		logger.Info("IngressClass parameters must specify the namespace of the Instance", "ingressclass", class.Name)
+		return nil, nil
+	}
+
+	instance := &proxyv1alpha1.Instance{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: *params.Namespace, Name: params.Name}, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// listIngresses returns the Ingresses of the class. Only Ingresses in the namespace of the Instance are served, as the
+// Instance discovers the endpoints of the referenced Services in its own namespace.
+func (r *Reconciler) listIngresses(ctx context.Context, class *networkingv1.IngressClass, instance *proxyv1alpha1.Instance) ([]networkingv1.Ingress, error) {
+	list := &networkingv1.IngressList{}
+	if err := r.List(ctx, list, client.InNamespace(instance.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var ingresses []networkingv1.Ingress
+	for _, ingress := range list.Items {
+		if ingress.DeletionTimestamp == nil && className(&ingress, class) == class.Name {
+			ingresses = append(ingresses, ingress)
+		}
+	}
+
+	return ingresses, nil
+}
+
+// className returns the name of the class the Ingress belongs to. Ingresses without a class belong to the
+// given class if it is marked as default.
+func className(ingress *networkingv1.Ingress, class *networkingv1.IngressClass) string {
+	if ingress.Spec.IngressClassName != nil {
+		return *ingress.Spec.IngressClassName
+	}
+	if name, ok := ingress.Annotations[ingressClassAnnotation]; ok {
+		return name
+	}
+	if class != nil && class.Annotations[networkingv1.AnnotationIsDefaultIngressClass] == "true" {
+		return class.Name
+	}
+
+	return ""
+}
+
+func (r *Reconciler) apply(ctx context.Context, instance *proxyv1alpha1.Instance, object configv1alpha1.Object, mutate func()) error {
+	logger := log.FromContext(ctx)
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, object, func() error {
+		mutate()
+		return controllerutil.SetControllerReference(instance, object, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		logger.Info(fmt.Sprintf("Object %s", result), reflect.TypeOf(object).Elem().Name(), object.GetName())
+	}
+
+	return nil
+}
+
+// cleanup deletes the objects generated for the class which are not part of the desired state.
+func (r *Reconciler) cleanup(ctx context.Context, class string, frontend *configv1alpha1.Frontend, backends []*configv1alpha1.Backend) error {
+	desired := map[string]bool{}
+	if frontend != nil {
+		desired["Frontend/"+client.ObjectKeyFromObject(frontend).String()] = true
+	}
+	for _, backend := range backends {
+		desired["Backend/"+client.ObjectKeyFromObject(backend).String()] = true
+	}
+
+	var objects []client.Object
+
+	frontends := &configv1alpha1.FrontendList{}
+	if err := r.List(ctx, frontends, client.MatchingLabels{ingressClassLabel: class}); err != nil {
+		return err
+	}
+	for i := range frontends.Items {
+		if !desired["Frontend/"+client.ObjectKeyFromObject(&frontends.Items[i]).String()] {
+			objects = append(objects, &frontends.Items[i])
+		}
+	}
+
+	backendList := &configv1alpha1.BackendList{}
+	if err := r.List(ctx, backendList, client.MatchingLabels{ingressClassLabel: class}); err != nil {
+		return err
+	}
+	for i := range backendList.Items {
+		if !desired["Backend/"+client.ObjectKeyFromObject(&backendList.Items[i]).String()] {
+			objects = append(objects, &backendList.Items[i])
+		}
+	}
+
+	var errs error
+	for _, object := range objects {
+		if err := r.Delete(ctx, object); client.IgnoreNotFound(err) != nil {
+			errs = multierr.Append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1.IngressClass{}).
+		Watches(&source.Kind{Type: &networkingv1.Ingress{}}, handler.EnqueueRequestsFromMapFunc(r.findClassesForIngress)).
+		Watches(&source.Kind{Type: &proxyv1alpha1.Instance{}}, handler.EnqueueRequestsFromMapFunc(r.findClassesForNamespace)).
+		Watches(&source.Kind{Type: &corev1.Service{}}, handler.EnqueueRequestsFromMapFunc(r.findClassesForNamespace)).
+		Complete(r)
+}
+
+func (r *Reconciler) findClassesForIngress(object client.Object) []reconcile.Request {
+	ingress, ok := object.(*networkingv1.Ingress)
+	if !ok {
+		return nil
+	}
+
+	if name := className(ingress, nil); name != "" {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+	}
+
+	return r.findClassesForNamespace(object)
+}
+
+// findClassesForNamespace enqueues the classes served by the operator whose Instance lives in the namespace of the object.
+func (r *Reconciler) findClassesForNamespace(object client.Object) []reconcile.Request {
+	classes := &networkingv1.IngressClassList{}
+	if err := r.List(context.Background(), classes); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, class := range classes.Items {
+		if class.Spec.Controller != ControllerName || class.Spec.Parameters == nil {
+			continue
+		}
+		if pointer.StringDeref(class.Spec.Parameters.Namespace, "") == object.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: class.Name}})
+		}
+	}
+
+	return requests
+}
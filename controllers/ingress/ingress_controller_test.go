@@ -0,0 +1,275 @@
+package ingress_test
+
+import (
+	"context"
+
+	parser "github.com/haproxytech/config-parser/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/controllers/ingress"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Reconcile", Label("controller"), func() {
+	Context("Reconcile", func() {
+		var (
+			scheme  *runtime.Scheme
+			ctx     context.Context
+			proxy   *proxyv1alpha1.Instance
+			class   *networkingv1.IngressClass
+			service *corev1.Service
+			app     *networkingv1.Ingress
+		)
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Ω(clientgoscheme.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+			Ω(configv1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+			Ω(proxyv1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+
+			ctx = context.Background()
+
+			proxy = &proxyv1alpha1.Instance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar-foo",
+					Namespace: "foo",
+					UID:       uuid.NewUUID(),
+				},
+				Spec: proxyv1alpha1.InstanceSpec{
+					Configuration: proxyv1alpha1.Configuration{
+						LabelSelector: metav1.LabelSelector{
+							MatchLabels: map[string]string{"label-test": "ok"},
+						},
+					},
+					Network: proxyv1alpha1.Network{
+						Service: proxyv1alpha1.ServiceSpec{Enabled: true},
+					},
+				},
+			}
+
+			class = &networkingv1.IngressClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "haproxy",
+				},
+				Spec: networkingv1.IngressClassSpec{
+					Controller: ingress.ControllerName,
+					Parameters: &networkingv1.IngressClassParametersReference{
+						APIGroup:  pointer.String(proxyv1alpha1.GroupVersion.Group),
+						Kind:      "Instance",
+						Name:      proxy.Name,
+						Namespace: pointer.String(proxy.Namespace),
+						Scope:     pointer.String(networkingv1.IngressClassParametersReferenceScopeNamespace),
+					},
+				},
+			}
+
+			service = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app",
+					Namespace: "foo",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Name: "http", Port: 8080}},
+				},
+			}
+
+			exact := networkingv1.PathTypeExact
+			prefix := networkingv1.PathTypePrefix
+			app = &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app",
+					Namespace: "foo",
+				},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: pointer.String(class.Name),
+					Rules: []networkingv1.IngressRule{
+						{
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
+										{
+											Path:     "/",
+											PathType: &prefix,
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{Name: "app", Port: networkingv1.ServiceBackendPort{Name: "http"}},
+											},
+										},
+									},
+								},
+							},
+						},
+						{
+							Host: "app.example.com",
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
+										{
+											Path:     "/api/",
+											PathType: &prefix,
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{Name: "app", Port: networkingv1.ServiceBackendPort{Number: 8080}},
+											},
+										},
+										{
+											Path:     "/health",
+											PathType: &exact,
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{Name: "app", Port: networkingv1.ServiceBackendPort{Name: "http"}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		reconcile := func(cli client.Client) {
+			r := ingress.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: class.Name}})
+			Ω(err).ShouldNot(HaveOccurred())
+		}
+
+		It("should translate the ingress rules into a frontend and backends", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy, class, service, app).Build()
+			reconcile(cli)
+
+			backends := &configv1alpha1.BackendList{}
+			Ω(cli.List(ctx, backends)).ShouldNot(HaveOccurred())
+			Ω(backends.Items).Should(HaveLen(3))
+
+			backend := &configv1alpha1.Backend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "ingress-app-app-8080"}, backend)).ShouldNot(HaveOccurred())
+			Ω(backend.Labels).Should(HaveKeyWithValue("label-test", "ok"))
+			Ω(backend.OwnerReferences).Should(HaveLen(1))
+			Ω(backend.OwnerReferences[0].UID).Should(Equal(proxy.UID))
+			Ω(backend.Spec.ServiceRef).ShouldNot(BeNil())
+			Ω(backend.Spec.ServiceRef.Name).Should(Equal("app"))
+			Ω(backend.Spec.ServiceRef.Port).Should(Equal("http"))
+
+			frontend := &configv1alpha1.Frontend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "ingress-haproxy"}, frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Spec.Binds).Should(HaveLen(1))
+			Ω(frontend.Spec.DefaultBackend.Name).Should(Equal("ingress-haproxy-default"))
+			Ω(frontend.Spec.BackendSwitching).Should(HaveLen(3))
+			Ω(frontend.Spec.BackendSwitching[0].Condition).Should(Equal("{ req.hdr(host),field(1,:) -i -m str app.example.com } { path -m str /health }"))
+			Ω(frontend.Spec.BackendSwitching[1].Condition).Should(Equal("{ req.hdr(host),field(1,:) -i -m str app.example.com } { path -m str /api } || { req.hdr(host),field(1,:) -i -m str app.example.com } { path -m beg /api/ }"))
+			Ω(frontend.Spec.BackendSwitching[2].Condition).Should(BeEmpty())
+			Ω(*frontend.Spec.BackendSwitching[2].Backend.Name).Should(Equal("ingress-app-app-http"))
+
+			p, err := parser.New()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(frontend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring("use_backend ingress-app-app-http\n"))
+		})
+
+		It("should match wildcard hosts on a single label and quote the paths", func() {
+			app.Spec.Rules[1].Host = "*.example.com"
+			app.Spec.Rules[1].HTTP.Paths[0].Path = "/a b"
+			app.Spec.Rules[1].HTTP.Paths[1].Path = "/it's"
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy, class, service, app).Build()
+			reconcile(cli)
+
+			frontend := &configv1alpha1.Frontend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "ingress-haproxy"}, frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Spec.BackendSwitching).Should(HaveLen(2))
+			Ω(frontend.Spec.BackendSwitching[0].Condition).Should(Equal("{ req.hdr(host),field(1,:),regsub(^[^.]+,) -i -m str .example.com } { path -m str '/a b' } || { req.hdr(host),field(1,:),regsub(^[^.]+,) -i -m str .example.com } { path -m beg '/a b/' }"))
+			Ω(frontend.Spec.BackendSwitching[1].Condition).Should(BeEmpty())
+
+			p, err := parser.New()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(frontend.AddToParser(p)).ShouldNot(HaveOccurred())
+			Ω(p.String()).Should(ContainSubstring("use_backend ingress-app-app-8080 if { req.hdr(host),field(1,:),regsub(^[^.]+,) -i -m str .example.com } { path -m str '/a b' } ||"))
+		})
+
+		It("should add the TLS secrets to the certificate list of a https bind", func() {
+			app.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{"app.example.com"}, SecretName: "app-tls"}}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy, class, service, app).Build()
+			reconcile(cli)
+
+			frontend := &configv1alpha1.Frontend{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: "foo", Name: "ingress-haproxy"}, frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Spec.Binds).Should(HaveLen(2))
+
+			bind := frontend.Spec.Binds[1]
+			Ω(bind.Port).Should(BeEquivalentTo(443))
+			Ω(bind.SSL.Enabled).Should(BeTrue())
+			Ω(bind.SSLCertificateList.Elements).Should(HaveLen(1))
+			Ω(bind.SSLCertificateList.Elements[0].SNIFilter).Should(Equal("app.example.com"))
+			Ω(bind.SSLCertificateList.Elements[0].Certificate.ValueFrom).Should(HaveLen(2))
+			Ω(bind.SSLCertificateList.Elements[0].Certificate.ValueFrom[0].SecretKeyRef.Name).Should(Equal("app-tls"))
+			Ω(bind.SSLCertificateList.Elements[0].Certificate.ValueFrom[1].SecretKeyRef.Key).Should(Equal(corev1.TLSPrivateKeyKey))
+		})
+
+		It("should ignore ingresses of other classes", func() {
+			app.Spec.IngressClassName = pointer.String("other")
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy, class, service, app).Build()
+			reconcile(cli)
+
+			frontends := &configv1alpha1.FrontendList{}
+			Ω(cli.List(ctx, frontends)).ShouldNot(HaveOccurred())
+			Ω(frontends.Items).Should(BeEmpty())
+		})
+
+		It("should delete the generated objects which are no longer needed", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy, class, service, app).Build()
+			reconcile(cli)
+
+			app.Spec.Rules = app.Spec.Rules[:1]
+			Ω(cli.Update(ctx, app)).ShouldNot(HaveOccurred())
+			reconcile(cli)
+
+			backends := &configv1alpha1.BackendList{}
+			Ω(cli.List(ctx, backends)).ShouldNot(HaveOccurred())
+			Ω(backends.Items).Should(HaveLen(2))
+
+			Ω(cli.Delete(ctx, app)).ShouldNot(HaveOccurred())
+			reconcile(cli)
+
+			Ω(cli.List(ctx, backends)).ShouldNot(HaveOccurred())
+			Ω(backends.Items).Should(BeEmpty())
+
+			frontends := &configv1alpha1.FrontendList{}
+			Ω(cli.List(ctx, frontends)).ShouldNot(HaveOccurred())
+			Ω(frontends.Items).Should(BeEmpty())
+		})
+
+		It("should publish the service address in the ingress status", func() {
+			haproxyService := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar-foo-haproxy",
+					Namespace: "foo",
+				},
+				Spec: corev1.ServiceSpec{
+					ClusterIP: "10.0.0.10",
+				},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy, class, service, app, haproxyService).Build()
+			reconcile(cli)
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(app), app)).ShouldNot(HaveOccurred())
+			Ω(app.Status.LoadBalancer.Ingress).Should(Equal([]corev1.LoadBalancerIngress{{IP: "10.0.0.10"}}))
+		})
+	})
+})
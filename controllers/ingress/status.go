@@ -0,0 +1,88 @@
+package ingress
+
+import (
+	"context"
+	"reflect"
+
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// updateStatus publishes the addresses the instance is reachable at in the status of the ingresses.
+func (r *Reconciler) updateStatus(ctx context.Context, instance *proxyv1alpha1.Instance, ingresses []networkingv1.Ingress) error {
+	logger := log.FromContext(ctx)
+
+	addresses, err := r.loadBalancerAddresses(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	for i := range ingresses {
+		ingress := &ingresses[i]
+
+		if reflect.DeepEqual(ingress.Status.LoadBalancer.Ingress, addresses) {
+			continue
+		}
+
+		ingress.Status.LoadBalancer.Ingress = addresses
+		if err := r.Status().Update(ctx, ingress); err != nil {
+			return err
+		}
+		logger.Info("Status updated", "ingress", ingress.Name)
+	}
+
+	return nil
+}
+
+// loadBalancerAddresses returns the configured host IPs, the addresses of the instance service or the host IPs of the
+// running instance pods, in this order of precedence.
+func (r *Reconciler) loadBalancerAddresses(ctx context.Context, instance *proxyv1alpha1.Instance) ([]corev1.LoadBalancerIngress, error) {
+	ips := sets.NewString()
+
+	switch {
+	case len(instance.Spec.Network.HostIPs) > 0:
+		for _, ip := range instance.Spec.Network.HostIPs {
+			ips.Insert(ip)
+		}
+	case instance.Spec.Network.Service.Enabled:
+		service := &corev1.Service{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: utils.GetServiceName(instance)}, service); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		if len(service.Status.LoadBalancer.Ingress) > 0 {
+			return service.Status.LoadBalancer.Ingress, nil
+		}
+		if service.Spec.ClusterIP != "" && service.Spec.ClusterIP != corev1.ClusterIPNone {
+			ips.Insert(service.Spec.ClusterIP)
+		}
+	default:
+		pods := &corev1.PodList{}
+		if err := r.List(ctx, pods, client.InNamespace(instance.Namespace), client.MatchingLabels(utils.GetAppSelectorLabels(instance))); err != nil {
+			return nil, err
+		}
+
+		for _, pod := range pods.Items {
+			if pod.Status.Phase == corev1.PodRunning && pod.Status.HostIP != "" {
+				ips.Insert(pod.Status.HostIP)
+			}
+		}
+	}
+
+	var addresses []corev1.LoadBalancerIngress
+	for _, ip := range ips.List() {
+		addresses = append(addresses, corev1.LoadBalancerIngress{IP: ip})
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,288 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	httpPort  = 80
+	httpsPort = 443
+)
+
+// route is a single host/path rule of an Ingress pointing to a generated backend.
+type route struct {
+	host     string
+	path     string
+	pathType networkingv1.PathType
+	backend  string
+}
+
+// translate generates the frontend and backends serving the given ingresses.
+func (r *Reconciler) translate(ctx context.Context, class *networkingv1.IngressClass, instance *proxyv1alpha1.Instance, ingresses []networkingv1.Ingress) (*configv1alpha1.Frontend, []*configv1alpha1.Backend, error) {
+	labels, err := configLabels(class, instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		routes         []route
+		elements       []configv1alpha1.CertificateListElement
+		defaultBackend string
+	)
+	backends := map[string]*configv1alpha1.Backend{}
+
+	for i := range ingresses {
+		ingress := &ingresses[i]
+
+		if ingress.Spec.DefaultBackend != nil && ingress.Spec.DefaultBackend.Service != nil && defaultBackend == "" {
+			backend, err := r.backend(ctx, ingress, ingress.Spec.DefaultBackend.Service, labels)
+			if err != nil {
+				return nil, nil, err
+			}
+			backends[backend.Name] = backend
+			defaultBackend = backend.Name
+		}
+
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service == nil {
+					continue
+				}
+				if !utils.ValidACLValue(path.Path) {
+					log.FromContext(ctx).Info("skipping ingress path which cannot be matched by HAProxy", "ingress", client.ObjectKeyFromObject(ingress), "path", path.Path)
+					continue
+				}
+
+				backend, err := r.backend(ctx, ingress, path.Backend.Service, labels)
+				if err != nil {
+					return nil, nil, err
+				}
+				backends[backend.Name] = backend
+
+				pathType := networkingv1.PathTypePrefix
+				if path.PathType != nil && *path.PathType == networkingv1.PathTypeExact {
+					pathType = networkingv1.PathTypeExact
+				}
+
+				routes = append(routes, route{
+					host:     rule.Host,
+					path:     path.Path,
+					pathType: pathType,
+					backend:  backend.Name,
+				})
+			}
+		}
+
+		for _, tls := range ingress.Spec.TLS {
+			if tls.SecretName == "" {
+				continue
+			}
+
+			elements = append(elements, configv1alpha1.CertificateListElement{
+				Certificate: configv1alpha1.SSLCertificate{
+					Name: fmt.Sprintf("%s-%s", ingress.Namespace, tls.SecretName),
+					ValueFrom: []configv1alpha1.SSLCertificateValueFrom{
+						{SecretKeyRef: utils.SecretKeySelector(tls.SecretName, corev1.TLSCertKey)},
+						{SecretKeyRef: utils.SecretKeySelector(tls.SecretName, corev1.TLSPrivateKeyKey)},
+					},
+				},
+				SNIFilter: strings.Join(tls.Hosts, " "),
+			})
+		}
+	}
+
+	if defaultBackend == "" {
+		backend := &configv1alpha1.Backend{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("ingress-%s-default", class.Name),
+				Namespace: instance.Namespace,
+				Labels:    labels,
+			},
+			Spec: configv1alpha1.BackendSpec{
+				BaseSpec: configv1alpha1.BaseSpec{Mode: "http"},
+			},
+		}
+		backends[backend.Name] = backend
+		defaultBackend = backend.Name
+	}
+
+	frontend := &configv1alpha1.Frontend{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("ingress-%s", class.Name),
+			Namespace: instance.Namespace,
+			Labels:    labels,
+		},
+		Spec: configv1alpha1.FrontendSpec{
+			BaseSpec: configv1alpha1.BaseSpec{Mode: "http"},
+			Binds: []configv1alpha1.Bind{
+				{Name: "http", Port: httpPort},
+			},
+			BackendSwitching: switchingRules(routes),
+			DefaultBackend:   corev1.LocalObjectReference{Name: defaultBackend},
+		},
+	}
+
+	if len(elements) > 0 {
+		frontend.Spec.Binds = append(frontend.Spec.Binds, configv1alpha1.Bind{
+			Name: "https",
+			Port: httpsPort,
+			SSL:  &configv1alpha1.SSL{Enabled: true},
+			SSLCertificateList: &configv1alpha1.CertificateList{
+				Name:     frontend.Name,
+				Elements: elements,
+			},
+		})
+	}
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]*configv1alpha1.Backend, 0, len(names))
+	for _, name := range names {
+		result = append(result, backends[name])
+	}
+
+	return frontend, result, nil
+}
+
+// backend generates a backend discovering its servers from the referenced service.
+func (r *Reconciler) backend(ctx context.Context, ingress *networkingv1.Ingress, ref *networkingv1.IngressServiceBackend, labels map[string]string) (*configv1alpha1.Backend, error) {
+	port := ref.Port.Name
+	suffix := ref.Port.Name
+	if port == "" {
+		suffix = strconv.Itoa(int(ref.Port.Number))
+
+		// the endpoint slices only carry the name of the service port
+		service := &corev1.Service{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: ingress.Namespace, Name: ref.Name}, service)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		for _, p := range service.Spec.Ports {
+			if p.Port == ref.Port.Number {
+				port = p.Name
+				break
+			}
+		}
+	}
+
+	return &configv1alpha1.Backend{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("ingress-%s-%s-%s", ingress.Name, ref.Name, suffix),
+			Namespace: ingress.Namespace,
+			Labels:    labels,
+		},
+		Spec: configv1alpha1.BackendSpec{
+			BaseSpec: configv1alpha1.BaseSpec{Mode: "http"},
+			ServiceRef: &configv1alpha1.ServiceReference{
+				Name: ref.Name,
+				Port: port,
+			},
+		},
+	}, nil
+}
+
+// switchingRules orders the routes from the most to the least specific match, as the first matching
+// use_backend rule wins in HAProxy.
+func switchingRules(routes []route) []configv1alpha1.BackendSwitchingRule {
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, b := routes[i], routes[j]
+		if (a.host != "") != (b.host != "") {
+			return a.host != ""
+		}
+		if strings.HasPrefix(a.host, "*.") != strings.HasPrefix(b.host, "*.") {
+			return !strings.HasPrefix(a.host, "*.")
+		}
+		if a.host != b.host {
+			return a.host < b.host
+		}
+		if a.pathType != b.pathType {
+			return a.pathType == networkingv1.PathTypeExact
+		}
+		return len(a.path) > len(b.path)
+	})
+
+	rules := make([]configv1alpha1.BackendSwitchingRule, 0, len(routes))
+	for _, route := range routes {
+		var host string
+		switch {
+		case strings.HasPrefix(route.host, "*."):
+			// the wildcard matches exactly one label, so the host without its first label must equal the suffix
+			host = fmt.Sprintf("{ req.hdr(host),field(1,:),regsub(^[^.]+,) -i -m str %s }", strings.TrimPrefix(route.host, "*"))
+		case route.host != "":
+			// host names are case-insensitive
+			host = fmt.Sprintf("{ req.hdr(host),field(1,:) -i -m str %s }", route.host)
+		}
+
+		// a prefix matches the path itself and the paths continuing with a slash, so /api does not match /apis
+		var paths []string
+		switch path := strings.TrimSuffix(route.path, "/"); {
+		case route.pathType == networkingv1.PathTypeExact:
+			paths = append(paths, fmt.Sprintf("{ path -m str %s }", utils.QuoteACLValue(route.path)))
+		case path != "":
+			paths = append(paths,
+				fmt.Sprintf("{ path -m str %s }", utils.QuoteACLValue(path)),
+				fmt.Sprintf("{ path -m beg %s }", utils.QuoteACLValue(path+"/")),
+			)
+		}
+
+		var conditions []string
+		for _, path := range paths {
+			conditions = append(conditions, strings.TrimSpace(host+" "+path))
+		}
+		if len(conditions) == 0 && host != "" {
+			conditions = append(conditions, host)
+		}
+
+		rule := configv1alpha1.BackendSwitchingRule{
+			Backend: configv1alpha1.BackendReference{Name: pointer.String(route.backend)},
+		}
+		if len(conditions) > 0 {
+			rule.ConditionType = "if"
+			rule.Condition = strings.Join(conditions, " || ")
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// configLabels returns the labels of the generated objects. They must be selected by the instance and
+// identify the ingress class the objects were generated for.
+func configLabels(class *networkingv1.IngressClass, instance *proxyv1alpha1.Instance) (map[string]string, error) {
+	if len(instance.Spec.Configuration.LabelSelector.MatchExpressions) > 0 {
+		return nil, fmt.Errorf("instance %s/%s uses match expressions in its label selector which are not supported for ingress classes", instance.Namespace, instance.Name)
+	}
+
+	labels := map[string]string{
+		ingressClassLabel: class.Name,
+	}
+	for key, value := range instance.Spec.Configuration.LabelSelector.MatchLabels {
+		labels[key] = value
+	}
+
+	return labels, nil
+}
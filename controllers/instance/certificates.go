@@ -0,0 +1,188 @@
+package instance
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// DefaultCertificateExpiryWarning is the default time before the expiry of a certificate from which on warning
+// events are published.
+const DefaultCertificateExpiryWarning = 30 * 24 * time.Hour
+
+func init() {
+	metrics.Registry.MustRegister(certificateExpiry)
+}
+
+// certificateStatuses returns the first certificate of each certificate file of a config object. Files which do not
+// contain a PEM encoded certificate are skipped.
+func certificateStatuses(object client.Object, files map[string][]byte) []configv1alpha1.CertificateStatus {
+	var statuses []configv1alpha1.CertificateStatus
+
+	seen := map[string]bool{}
+	for _, certificate := range objectCertificates(object) {
+		file := certificate.FilePath()
+		if seen[file] {
+			continue
+		}
+		seen[file] = true
+
+		parsed := parseCertificate(files[filepath.Base(file)])
+		if parsed == nil {
+			continue
+		}
+
+		statuses = append(statuses, configv1alpha1.CertificateStatus{
+			File:     file,
+			Subject:  parsed.Subject.String(),
+			SANs:     subjectAlternativeNames(parsed),
+			Issuer:   parsed.Issuer.String(),
+			NotAfter: metav1.NewTime(parsed.NotAfter),
+		})
+	}
+
+	return statuses
+}
+
+func parseCertificate(data []byte) *x509.Certificate {
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			return nil
+		}
+		if block.Type == "CERTIFICATE" {
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil
+			}
+			return certificate
+		}
+		data = rest
+	}
+}
+
+func subjectAlternativeNames(certificate *x509.Certificate) []string {
+	var names []string
+	names = append(names, certificate.DNSNames...)
+	for _, ip := range certificate.IPAddresses {
+		names = append(names, ip.String())
+	}
+	names = append(names, certificate.EmailAddresses...)
+	for _, uri := range certificate.URIs {
+		names = append(names, uri.String())
+	}
+
+	return names
+}
+
+// warnExpiringCertificates publishes a warning event on the config object for each certificate expiring within the
+// configured warning period.
+func (r *Reconciler) warnExpiringCertificates(object client.Object, certificates []configv1alpha1.CertificateStatus) {
+	if r.Recorder == nil {
+		return
+	}
+
+	warning := r.certificateExpiryWarning()
+	for _, certificate := range certificates {
+		remaining := time.Until(certificate.NotAfter.Time)
+		switch {
+		case remaining <= 0:
+			r.Recorder.Eventf(object, corev1.EventTypeWarning, "CertificateExpired", "Certificate %s (%s) expired at %s", certificate.File, certificate.Subject, certificate.NotAfter.UTC().Format(time.RFC3339))
+		case remaining < warning:
+			r.Recorder.Eventf(object, corev1.EventTypeWarning, "CertificateExpiring", "Certificate %s (%s) expires at %s", certificate.File, certificate.Subject, certificate.NotAfter.UTC().Format(time.RFC3339))
+		}
+	}
+}
+
+func (r *Reconciler) certificateExpiryWarning() time.Duration {
+	if r.CertificateExpiryWarning == 0 {
+		return DefaultCertificateExpiryWarning
+	}
+
+	return r.CertificateExpiryWarning
+}
+
+// certificateRequeueAfter returns the time until the next certificate enters the warning period or expires, so that
+// the events are published without waiting for other changes, or zero if there is none.
+func (r *Reconciler) certificateRequeueAfter(certificates []certificateExpiryMetric) time.Duration {
+	warning := r.certificateExpiryWarning()
+	now := time.Now()
+
+	var next time.Time
+	for _, certificate := range certificates {
+		for _, t := range []time.Time{certificate.notAfter.Add(-warning), certificate.notAfter} {
+			if t.After(now) && (next.IsZero() || t.Before(next)) {
+				next = t
+			}
+		}
+	}
+	if next.IsZero() {
+		return 0
+	}
+
+	return next.Sub(now)
+}
+
+// certificateExpiry exports the expiry of the certificates of all instances.
+var certificateExpiry = &certificateExpiryCollector{
+	desc: prometheus.NewDesc(
+		"haproxy_operator_certificate_expiry_seconds",
+		"Expiry of the certificate in a certificate file as Unix timestamp.",
+		[]string{"namespace", "instance", "kind", "name", "file"},
+		nil,
+	),
+	certificates: map[types.NamespacedName][]certificateExpiryMetric{},
+}
+
+type certificateExpiryMetric struct {
+	kind     string
+	name     string
+	file     string
+	notAfter time.Time
+}
+
+// certificateExpiryCollector holds the certificates per instance, which drops the series of removed certificates and
+// objects whenever the certificates of an instance are replaced.
+type certificateExpiryCollector struct {
+	desc *prometheus.Desc
+
+	mu           sync.Mutex
+	certificates map[types.NamespacedName][]certificateExpiryMetric
+}
+
+func (c *certificateExpiryCollector) set(instance types.NamespacedName, certificates []certificateExpiryMetric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(certificates) == 0 {
+		delete(c.certificates, instance)
+		return
+	}
+	c.certificates[instance] = certificates
+}
+
+func (c *certificateExpiryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *certificateExpiryCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for instance, certificates := range c.certificates {
+		for _, certificate := range certificates {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(certificate.notAfter.Unix()),
+				instance.Namespace, instance.Name, certificate.kind, certificate.name, certificate.file)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package instance
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	haproxy "github.com/haproxytech/client-native/v4/configuration/options"
@@ -11,106 +13,204 @@ import (
 	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
 	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
 	"github.com/six-group/haproxy-operator/pkg/defaults"
+	"github.com/six-group/haproxy-operator/pkg/hash"
 	"github.com/six-group/haproxy-operator/pkg/utils"
 	"go.uber.org/multierr"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-func (r *Reconciler) reconcileConfig(ctx context.Context, instance *proxyv1alpha1.Instance, listens *configv1alpha1.ListenList, frontends *configv1alpha1.FrontendList, backends *configv1alpha1.BackendList, resolvers *configv1alpha1.ResolverList) error {
+// caCertKey is the key of the CA certificate in Secrets of type kubernetes.io/tls issued by cert-manager.
+const caCertKey = "ca.crt"
+
+var certManagerCertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// certManagerCertificateNameAnnotation is set by cert-manager on the Secrets of Certificates.
+const certManagerCertificateNameAnnotation = "cert-manager.io/certificate-name"
+
+// reconcileConfig publishes the configuration files in the configuration Secret and returns them.
+func (r *Reconciler) reconcileConfig(ctx context.Context, instance *proxyv1alpha1.Instance, listens *configv1alpha1.ListenList, frontends *configv1alpha1.FrontendList, backends *configv1alpha1.BackendList, resolvers *configv1alpha1.ResolverList, peers *configv1alpha1.PeersList, userlists *configv1alpha1.UserlistList) (map[string][]byte, error) {
 	logger := log.FromContext(ctx)
 
-	config, err := r.generateHAPProxyConfiguration(ctx, instance, listens, frontends, backends, resolvers)
+	data, runtimeFiles, err := r.generateConfigFiles(ctx, instance, listens, frontends, backends, resolvers, peers, userlists)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if r.ConfigValidator != nil {
+		if err := r.ConfigValidator.Validate(ctx, validationFiles(instance, peers, data)); err != nil {
+			return nil, err
+		}
+	}
+
+	configSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.GetConfigSecretName(instance),
+			Namespace: instance.Namespace,
+		},
+	}
+	hash := configChecksum(data)
+
+	var previous map[string][]byte
+	var runtimeCommands []string
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, configSecret, func() error {
+		if err := controllerutil.SetOwnerReference(instance, configSecret, r.Scheme); err != nil {
+			return err
+		}
+
+		previous = configSecret.Data
+		configSecret.Data = data
+
+		if instance.Spec.Configuration.Global.RuntimeSyncEnabled() {
+			reloadChecksum := hash
+			if commands, ok := r.planRuntimeSync(ctx, previous, data, runtimeFiles); ok {
+				reloadChecksum = string(previous[reloadChecksumFile])
+				runtimeCommands = commands
+			}
+			configSecret.Data[reloadChecksumFile] = []byte(reloadChecksum)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result != controllerutil.OperationResultNone {
+		logger.Info(fmt.Sprintf("Object %s", result), "secret", configSecret.Name)
+	}
+
+	if instance.Spec.Configuration.Global.RuntimeSyncEnabled() {
+		if err := r.syncRuntime(ctx, instance, configSecret, previous, runtimeCommands, hash); err != nil {
+			return nil, err
+		}
+	}
+
+	if previous != nil && configChecksum(previous) != hash && r.Recorder != nil {
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "ConfigChanged", configChangeMessage(configChecksum(previous), hash, configDiff(previous, data)))
+	}
+	if instance.Status.ConfigHash != hash || instance.Status.LastAppliedTime == nil {
+		now := metav1.Now()
+		instance.Status.LastAppliedTime = &now
+	}
+	instance.Status.ConfigHash = hash
+	instance.Status.AppliedGenerations = appliedGenerations(listens, frontends, backends, resolvers, peers, userlists)
+
+	return data, nil
+}
+
+// generateConfigFiles returns the files of the configuration Secret keyed by file name, and the map, certificate and
+// OCSP response files keyed by path which can be updated through the runtime API.
+func (r *Reconciler) generateConfigFiles(ctx context.Context, instance *proxyv1alpha1.Instance, listens *configv1alpha1.ListenList, frontends *configv1alpha1.FrontendList, backends *configv1alpha1.BackendList, resolvers *configv1alpha1.ResolverList, peers *configv1alpha1.PeersList, userlists *configv1alpha1.UserlistList) (map[string][]byte, map[string]string, error) {
+	config, err := r.generateHAPProxyConfiguration(ctx, instance, listens, frontends, backends, resolvers, peers, userlists)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	certificates, err := r.generateCertificates(ctx, instance, listens, frontends, backends)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	envs, err := r.generateEnvs(ctx, instance, listens)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	mappings, err := r.generateBackendMappingFiles(ctx, instance, frontends)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	errorFiles, err := r.generateErrorFiles(ctx, instance, frontends, backends)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	customCerts, err := r.generateCustomCertificatesFile(ctx, instance, frontends, listens)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	ocspResponses, err := r.generateOCSPResponses(ctx, instance, frontends, listens)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	aclValueFiles := r.generateACLValuesFiles(ctx, listens, frontends, backends)
 
-	configSecret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      utils.GetConfigSecretName(instance),
-			Namespace: instance.Namespace,
-		},
+	data := map[string][]byte{
+		filepath.Base(haproxy.DefaultConfigurationFile): []byte(config),
 	}
-	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, configSecret, func() error {
-		if err := controllerutil.SetOwnerReference(instance, configSecret, r.Scheme); err != nil {
-			return err
-		}
 
-		configSecret.Data = map[string][]byte{
-			filepath.Base(haproxy.DefaultConfigurationFile): []byte(config),
+	if instance.Spec.Configuration.Global.RuntimeSyncEnabled() {
+		secret, err := r.reconcileRuntimeTLS(ctx, instance)
+		if err != nil {
+			return nil, nil, err
 		}
-
-		if hasLocalLoggingTarget(instance) {
-			configSecret.Data["rsyslog.conf"] = []byte(fmt.Sprintf(utils.RsyslogConfigFormat, instance.Spec.Configuration.Global.Logging.Address))
+		for file, value := range runtimeTLSFiles(secret) {
+			data[file] = value
 		}
+	}
 
-		for file, certificate := range certificates {
-			configSecret.Data[filepath.Base(file)] = []byte(certificate)
-		}
+	if hasLocalLoggingTarget(instance) {
+		data["rsyslog.conf"] = []byte(fmt.Sprintf(utils.RsyslogConfigFormat, instance.Spec.Configuration.Global.Logging.Address))
+	}
 
-		if len(envs) > 0 {
-			configSecret.Data["env"] = []byte(strings.Join(envs, "/n"))
-		}
+	for file, certificate := range certificates {
+		data[filepath.Base(file)] = []byte(certificate)
+	}
 
-		for file, data := range mappings {
-			configSecret.Data[filepath.Base(file)] = []byte(data)
-		}
+	if len(envs) > 0 {
+		data["env"] = []byte(strings.Join(envs, "\n"))
+	}
 
-		for file, data := range errorFiles {
-			configSecret.Data[filepath.Base(file)] = []byte(data)
-		}
+	for file, value := range mappings {
+		data[filepath.Base(file)] = []byte(value)
+	}
 
-		for file, data := range customCerts {
-			configSecret.Data[filepath.Base(file)] = []byte(data)
-		}
+	for file, value := range errorFiles {
+		data[filepath.Base(file)] = []byte(value)
+	}
 
-		for file, data := range aclValueFiles {
-			configSecret.Data[filepath.Base(file)] = []byte(data)
-		}
+	for file, value := range customCerts {
+		data[filepath.Base(file)] = []byte(value)
+	}
 
-		return nil
-	})
-	if err != nil {
-		return err
+	for file, value := range aclValueFiles {
+		data[filepath.Base(file)] = []byte(value)
 	}
-	if result != controllerutil.OperationResultNone {
-		logger.Info(fmt.Sprintf("Object %s", result), "secret", configSecret.Name)
+
+	for file, value := range ocspResponses {
+		data[filepath.Base(file)] = []byte(value)
 	}
 
-	return nil
+	runtimeFiles := map[string]string{}
+	for file, value := range mappings {
+		runtimeFiles[file] = value
+	}
+	for file, value := range ocspResponses {
+		runtimeFiles[file] = value
+	}
+	// certificates referenced by a CertificateRef are renewed through the runtime API
+	for _, files := range []map[string]string{certificates, customCerts} {
+		for file, value := range files {
+			if filepath.Ext(file) == ".pem" {
+				runtimeFiles[file] = value
+			}
+		}
+	}
+
+	return data, runtimeFiles, nil
 }
 
-func (r *Reconciler) generateHAPProxyConfiguration(ctx context.Context, instance *proxyv1alpha1.Instance, listens *configv1alpha1.ListenList, frontends *configv1alpha1.FrontendList, backends *configv1alpha1.BackendList, resolvers *configv1alpha1.ResolverList) (string, error) {
+func (r *Reconciler) generateHAPProxyConfiguration(ctx context.Context, instance *proxyv1alpha1.Instance, listens *configv1alpha1.ListenList, frontends *configv1alpha1.FrontendList, backends *configv1alpha1.BackendList, resolvers *configv1alpha1.ResolverList, peers *configv1alpha1.PeersList, userlists *configv1alpha1.UserlistList) (string, error) {
 	p, err := parser.New()
 	if err != nil {
 		return "", err
@@ -127,6 +227,14 @@ func (r *Reconciler) generateHAPProxyConfiguration(ctx context.Context, instance
 		listen := &listens.Items[i]
 
 		if err = checkNameKind(nameKindMap, listen); err == nil {
+			err = r.loadServiceEndpoints(ctx, instance, listen, listen.Spec.ServiceRef)
+		}
+		for idx := range listen.Spec.ServiceRefs {
+			if err == nil {
+				err = r.loadServiceEndpoints(ctx, instance, listen, &listen.Spec.ServiceRefs[idx])
+			}
+		}
+		if err == nil {
 			err = listen.AddToParser(p)
 		}
 
@@ -156,6 +264,14 @@ func (r *Reconciler) generateHAPProxyConfiguration(ctx context.Context, instance
 		backend := &backends.Items[i]
 
 		if err = checkNameKind(nameKindMap, backend); err == nil {
+			err = r.loadServiceEndpoints(ctx, instance, backend, backend.Spec.ServiceRef)
+		}
+		for idx := range backend.Spec.ServiceRefs {
+			if err == nil {
+				err = r.loadServiceEndpoints(ctx, instance, backend, &backend.Spec.ServiceRefs[idx])
+			}
+		}
+		if err == nil {
 			err = backend.AddToParser(p)
 		}
 
@@ -180,6 +296,38 @@ func (r *Reconciler) generateHAPProxyConfiguration(ctx context.Context, instance
 		}
 	}
 
+	for i := range peers.Items {
+		peer := &peers.Items[i]
+
+		if err = checkNameKind(nameKindMap, peer); err == nil {
+			peer.Spec.Replicas = replicaPeers(instance, peer)
+			err = peer.AddToParser(p)
+		}
+
+		if err != nil {
+			peer.Status.Phase = configv1alpha1.StatusPhaseInternalError
+			peer.Status.Error = err.Error()
+			return "", multierr.Combine(err, r.Status().Update(ctx, peer))
+		}
+	}
+
+	for i := range userlists.Items {
+		userlist := &userlists.Items[i]
+
+		if err = checkNameKind(nameKindMap, userlist); err == nil {
+			err = r.loadUserPasswords(ctx, instance, userlist)
+		}
+		if err == nil {
+			err = userlist.AddToParser(p)
+		}
+
+		if err != nil {
+			userlist.Status.Phase = configv1alpha1.StatusPhaseInternalError
+			userlist.Status.Error = err.Error()
+			return "", multierr.Combine(err, r.Status().Update(ctx, userlist))
+		}
+	}
+
 	if instance.Spec.Metrics != nil {
 		if err := instance.Spec.Metrics.AddToParser(p); err != nil {
 			return "", err
@@ -189,6 +337,45 @@ func (r *Reconciler) generateHAPProxyConfiguration(ctx context.Context, instance
 	return p.String(), nil
 }
 
+func (r *Reconciler) loadServiceEndpoints(ctx context.Context, instance *proxyv1alpha1.Instance, object client.Object, ref *configv1alpha1.ServiceReference) error {
+	if ref == nil {
+		return nil
+	}
+
+	namespace := instance.Namespace
+	if ref.Namespace != "" && ref.Namespace != instance.Namespace {
+		if !isGatewayObject(instance, object) {
+			return fmt.Errorf("service %s/%s: services in other namespaces are only supported for backends generated for gateways", ref.Namespace, ref.Name)
+		}
+		namespace = ref.Namespace
+	}
+
+	slices := &discoveryv1.EndpointSliceList{}
+	if err := r.Client.List(ctx, slices, client.InNamespace(namespace), client.MatchingLabels{discoveryv1.LabelServiceName: ref.Name}); err != nil {
+		return err
+	}
+
+	sort.Slice(slices.Items, func(i, j int) bool {
+		return slices.Items[i].Name < slices.Items[j].Name
+	})
+	ref.EndpointSlices = slices.Items
+
+	return nil
+}
+
+// isGatewayObject reports whether the config object was generated by the gateway controller, i.e. it is controlled by
+// the instance of a Gateway. The gateway controller checks the ReferenceGrants of the routes before it references
+// Services in other namespaces.
+func isGatewayObject(instance *proxyv1alpha1.Instance, object client.Object) bool {
+	owner := metav1.GetControllerOf(instance)
+	if owner == nil || owner.Kind != "Gateway" || !strings.HasPrefix(owner.APIVersion, "gateway.networking.k8s.io/") {
+		return false
+	}
+
+	owner = metav1.GetControllerOf(object)
+	return owner != nil && owner.UID == instance.UID
+}
+
 func (r *Reconciler) generateCertificates(ctx context.Context, instance *proxyv1alpha1.Instance, listens *configv1alpha1.ListenList, frontends *configv1alpha1.FrontendList, backends *configv1alpha1.BackendList) (map[string]string, error) {
 	certificates := map[string]string{}
 
@@ -270,22 +457,35 @@ func (r *Reconciler) generateEnvs(ctx context.Context, instance *proxyv1alpha1.I
 	for i := range listens.Items {
 		listen := listens.Items[i]
 
+		var headerRules []configv1alpha1.HTTPHeaderRule
 		if listen.Spec.HTTPRequest != nil {
-			for _, headers := range listen.Spec.HTTPRequest.SetHeader {
-				envValues, err := r.headerEnvValue(ctx, instance, headers, listen)
-				if err != nil {
-					return nil, err
+			headerRules = append(headerRules, listen.Spec.HTTPRequest.SetHeader...)
+			headerRules = append(headerRules, listen.Spec.HTTPRequest.AddHeader...)
+			for _, rule := range listen.Spec.HTTPRequest.Rules {
+				if rule.SetHeader != nil {
+					headerRules = append(headerRules, *rule.SetHeader)
 				}
-				envs = append(envs, envValues...)
-			}
-			for _, headers := range listen.Spec.HTTPRequest.AddHeader {
-				envValues, err := r.headerEnvValue(ctx, instance, headers, listen)
-				if err != nil {
-					return nil, err
+				if rule.AddHeader != nil {
+					headerRules = append(headerRules, *rule.AddHeader)
 				}
-				envs = append(envs, envValues...)
 			}
 		}
+		if listen.Spec.HTTPResponse != nil {
+			headerRules = append(headerRules, listen.Spec.HTTPResponse.SetHeader...)
+			headerRules = append(headerRules, listen.Spec.HTTPResponse.AddHeader...)
+		}
+		if listen.Spec.HTTPAfterResponse != nil {
+			headerRules = append(headerRules, listen.Spec.HTTPAfterResponse.SetHeader...)
+			headerRules = append(headerRules, listen.Spec.HTTPAfterResponse.AddHeader...)
+		}
+
+		for _, headers := range headerRules {
+			envValues, err := r.headerEnvValue(ctx, instance, headers, listen)
+			if err != nil {
+				return nil, err
+			}
+			envs = append(envs, envValues...)
+		}
 	}
 
 	return envs, nil
@@ -360,6 +560,37 @@ func (r *Reconciler) generateBackendMappingFiles(ctx context.Context, instance *
 				files[rules.Backend.RegexMapping.FilePath()] = strings.Join(mappings, "\n")
 			}
 		}
+
+		if split := frontend.Spec.TrafficSplit; split != nil {
+			selector, err := metav1.LabelSelectorAsSelector(&instance.Spec.Configuration.LabelSelector)
+			if err != nil {
+				return files, err
+			}
+
+			for _, weighted := range split.Backends {
+				backend := &configv1alpha1.Backend{}
+				err := r.Client.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: weighted.Name}, backend)
+				if err != nil {
+					err = fmt.Errorf("traffic split backend %s/%s: %w", instance.Namespace, weighted.Name, err)
+				} else if !selector.Matches(labels.Set(backend.Labels)) {
+					err = fmt.Errorf("traffic split backend %s/%s is not selected by the instance", instance.Namespace, weighted.Name)
+				}
+				if err != nil {
+					frontend.Status.Phase = configv1alpha1.StatusPhaseInternalError
+					frontend.Status.Error = err.Error()
+					return files, multierr.Combine(err, r.Status().Update(ctx, &frontend))
+				}
+			}
+
+			mapping, err := split.Map()
+			if err != nil {
+				frontend.Status.Phase = configv1alpha1.StatusPhaseInternalError
+				frontend.Status.Error = err.Error()
+				return files, multierr.Combine(err, r.Status().Update(ctx, &frontend))
+			}
+
+			files[split.FilePath(frontend.Name)] = mapping
+		}
 	}
 
 	return files, nil
@@ -438,6 +669,10 @@ func (r *Reconciler) loadSSLCertificateValueData(ctx context.Context, instance *
 		return *certificate.Value, nil
 	}
 
+	if certificate.CertificateRef != nil {
+		return r.loadCertificateRefData(ctx, instance, certificate.CertificateRef)
+	}
+
 	var items []string
 
 	for _, ref := range certificate.ValueFrom {
@@ -473,6 +708,81 @@ func (r *Reconciler) loadSSLCertificateValueData(ctx context.Context, instance *
 	return strings.Join(items, "\n"), nil
 }
 
+// loadCertificateRefData returns the PEM bundle of the certificate, the private key and the CA in the referenced
+// Secret.
+func (r *Reconciler) loadCertificateRefData(ctx context.Context, instance *proxyv1alpha1.Instance, ref *configv1alpha1.CertificateRef) (string, error) {
+	secretName, err := r.certificateRefSecretName(ctx, instance, ref)
+	if err != nil {
+		return "", err
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: secretName, Namespace: instance.Namespace}, secret); err != nil {
+		return "", err
+	}
+
+	var items []string
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, caCertKey} {
+		data, ok := secret.Data[key]
+		if !ok && key != caCertKey {
+			return "", fmt.Errorf("key %s not found in TLS secret: %s/%s", key, instance.Namespace, secretName)
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			items = append(items, strings.TrimSpace(string(data)))
+		}
+	}
+
+	return strings.Join(items, "\n"), nil
+}
+
+// certificateRefSecretName returns the name of the Secret of a certificate reference, which is the spec.secretName of
+// cert-manager Certificates.
+func (r *Reconciler) certificateRefSecretName(ctx context.Context, instance *proxyv1alpha1.Instance, ref *configv1alpha1.CertificateRef) (string, error) {
+	if ref.Kind != configv1alpha1.CertificateRefKindCertificate {
+		return ref.Name, nil
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certManagerCertificateGVK)
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: instance.Namespace}, certificate); err != nil {
+		return "", err
+	}
+
+	secretName, found, err := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	if err != nil {
+		return "", err
+	}
+	if !found || secretName == "" {
+		return "", fmt.Errorf("certificate %s/%s has no secretName", instance.Namespace, ref.Name)
+	}
+
+	return secretName, nil
+}
+
+func (r *Reconciler) loadUserPasswords(ctx context.Context, instance *proxyv1alpha1.Instance, userlist *configv1alpha1.Userlist) error {
+	for idx := range userlist.Spec.Users {
+		user := &userlist.Spec.Users[idx]
+
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: user.Password.Name, Namespace: instance.Namespace}, secret); err != nil {
+			return err
+		}
+
+		data, ok := secret.Data[user.Password.Key]
+		if !ok {
+			return fmt.Errorf("key %s not found in password secret: %s/%s", user.Password.Key, instance.Namespace, user.Password.Name)
+		}
+
+		password := strings.TrimSpace(string(data))
+		if password == "" || strings.ContainsAny(password, " \t\n") {
+			return fmt.Errorf("password of user %s must not be empty or contain whitespaces", user.Name)
+		}
+		user.PasswordValue = password
+	}
+
+	return nil
+}
+
 func extractSLCCertificatesFromFrontend(frontend *configv1alpha1.Frontend) []*configv1alpha1.SSLCertificate {
 	var certificates []*configv1alpha1.SSLCertificate
 
@@ -487,6 +797,14 @@ func extractSLCCertificatesFromFrontend(frontend *configv1alpha1.Frontend) []*co
 		if bind.SSL.CACertificate != nil {
 			certificates = append(certificates, bind.SSL.CACertificate)
 		}
+		if mtls := bind.SSL.MutualTLS; mtls != nil {
+			if mtls.CAVerifyCertificate != nil {
+				certificates = append(certificates, mtls.CAVerifyCertificate)
+			}
+			if mtls.CRL != nil {
+				certificates = append(certificates, mtls.CRL)
+			}
+		}
 	}
 
 	return certificates
@@ -511,6 +829,54 @@ func extractSLCCertificatesFromBackend(backend *configv1alpha1.Backend) []*confi
 	return certificates
 }
 
+// replicaPeers returns a peer for each replica of the instance. The peer names match the pod names, which HAProxy uses
+// as local peer name.
+func replicaPeers(instance *proxyv1alpha1.Instance, peers *configv1alpha1.Peers) []configv1alpha1.Peer {
+	var replicas []configv1alpha1.Peer
+	for idx, host := range replicaPeerHosts(instance) {
+		replicas = append(replicas, configv1alpha1.Peer{
+			Name:    fmt.Sprintf("%s-%d", utils.GetServiceName(instance), idx),
+			Address: host,
+			Port:    peers.Spec.Port,
+		})
+	}
+
+	return replicas
+}
+
+// replicaPeerHosts returns the DNS names of the replicas in the headless peers service. A name only resolves once the
+// pod of the replica got an IP address.
+func replicaPeerHosts(instance *proxyv1alpha1.Instance) []string {
+	var hosts []string
+	for idx := int32(0); idx < instance.Spec.Replicas; idx++ {
+		hosts = append(hosts, fmt.Sprintf("%s-%d.%s.%s.svc", utils.GetServiceName(instance), idx, utils.GetPeersServiceName(instance), instance.Namespace))
+	}
+
+	return hosts
+}
+
+// validationFiles returns the configuration files to validate. HAProxy resolves the peer addresses while parsing, but
+// the replicas do not exist before the configuration is published, so they are validated with a local address.
+func validationFiles(instance *proxyv1alpha1.Instance, peers *configv1alpha1.PeersList, data map[string][]byte) map[string][]byte {
+	if len(peers.Items) == 0 {
+		return data
+	}
+
+	files := make(map[string][]byte, len(data))
+	for file, value := range data {
+		files[file] = value
+	}
+
+	file := filepath.Base(haproxy.DefaultConfigurationFile)
+	config := string(data[file])
+	for _, host := range replicaPeerHosts(instance) {
+		config = strings.ReplaceAll(config, " "+host+":", " 127.0.0.1:")
+	}
+	files[file] = []byte(config)
+
+	return files
+}
+
 func checkNameKind(nameKindMap map[string]string, object client.Object) error {
 	if val, ok := nameKindMap[object.GetName()]; ok {
 		return fmt.Errorf("name %s already used by resource of kind %s", object.GetName(), val)
@@ -527,13 +893,10 @@ func (r *Reconciler) generateCustomCertificatesFile(ctx context.Context, instanc
 		frontend := frontends.Items[i]
 
 		for _, bind := range frontend.Spec.Binds {
-			if bind.SSLCertificateList != nil {
-				var elements []configv1alpha1.CertificateListElement
-				if len(bind.SSLCertificateList.Elements) > 0 {
-					elements = append(elements, bind.SSLCertificateList.Elements...)
-				}
+			if bind.CertificateListPath() != "" {
+				elements := bindCertificateListElements(bind)
 
-				if bind.SSLCertificateList.LabelSelector != nil {
+				if bind.SSLCertificateList != nil && bind.SSLCertificateList.LabelSelector != nil {
 					selector, err := metav1.LabelSelectorAsSelector(bind.SSLCertificateList.LabelSelector)
 					if err != nil {
 						frontend.Status.Phase = configv1alpha1.StatusPhaseInternalError
@@ -555,18 +918,25 @@ func (r *Reconciler) generateCustomCertificatesFile(ctx context.Context, instanc
 					}
 				}
 
+				var lines []string
 				for _, element := range elements {
-					files[element.Certificate.FilePath()] = *element.Certificate.Value
-
-					var alpn string
-					if len(element.Alpn) > 0 {
-						alpn = fmt.Sprintf("[alpn %s]", strings.Join(element.Alpn, ","))
+					data, err := r.loadSSLCertificateValueData(ctx, instance, &element.Certificate)
+					if err != nil {
+						frontend.Status.Phase = configv1alpha1.StatusPhaseInternalError
+						frontend.Status.Error = err.Error()
+						return files, multierr.Combine(err, r.Status().Update(ctx, &frontend))
 					}
+					files[element.Certificate.FilePath()] = data
 
-					mappings = append(mappings, strings.Join([]string{element.Certificate.FilePath(), alpn, element.SNIFilter, "\n"}, " "))
+					lines = append(lines, certificateListLine(element, bind.OCSPUpdate()))
 				}
 
-				files[bind.SSLCertificateList.FilePath()] = strings.Join(mappings, "")
+				// the crt-list of a certificate with OCSP updates only contains the certificate
+				if bind.SSLCertificateList != nil {
+					mappings = append(mappings, lines...)
+					lines = mappings
+				}
+				files[bind.CertificateListPath()] = strings.Join(lines, "")
 			}
 		}
 	}
@@ -575,31 +945,145 @@ func (r *Reconciler) generateCustomCertificatesFile(ctx context.Context, instanc
 		listen := listens.Items[i]
 
 		for _, bind := range listen.Spec.Binds {
-			if bind.SSLCertificateList != nil {
-				var elements []configv1alpha1.CertificateListElement
-				if len(bind.SSLCertificateList.Elements) > 0 {
-					elements = append(elements, bind.SSLCertificateList.Elements...)
-				}
+			if bind.CertificateListPath() != "" {
+				elements := bindCertificateListElements(bind)
 
-				if listen.Spec.HostCertificate != nil {
+				if bind.SSLCertificateList != nil && listen.Spec.HostCertificate != nil {
 					elements = append(elements, *listen.Spec.HostCertificate)
 				}
 
+				var lines []string
 				for _, element := range elements {
-					files[element.Certificate.FilePath()] = *element.Certificate.Value
-
-					var alpn string
-					if len(element.Alpn) > 0 {
-						alpn = fmt.Sprintf("[alpn %s]", strings.Join(element.Alpn, ","))
+					data, err := r.loadSSLCertificateValueData(ctx, instance, &element.Certificate)
+					if err != nil {
+						listen.Status.Phase = configv1alpha1.StatusPhaseInternalError
+						listen.Status.Error = err.Error()
+						return files, multierr.Combine(err, r.Status().Update(ctx, &listen))
 					}
+					files[element.Certificate.FilePath()] = data
 
-					mappings = append(mappings, strings.Join([]string{element.Certificate.FilePath(), alpn, element.SNIFilter, "\n"}, " "))
+					lines = append(lines, certificateListLine(element, bind.OCSPUpdate()))
 				}
 
-				files[bind.SSLCertificateList.FilePath()] = strings.Join(mappings, "")
+				// the crt-list of a certificate with OCSP updates only contains the certificate
+				if bind.SSLCertificateList != nil {
+					mappings = append(mappings, lines...)
+					lines = mappings
+				}
+				files[bind.CertificateListPath()] = strings.Join(lines, "")
 			}
 		}
 	}
 
 	return files, nil
 }
+
+// generateOCSPResponses returns the OCSP responses of the bind certificates keyed by the path next to the certificate.
+func (r *Reconciler) generateOCSPResponses(ctx context.Context, instance *proxyv1alpha1.Instance, frontends *configv1alpha1.FrontendList, listens *configv1alpha1.ListenList) (map[string]string, error) {
+	files := map[string]string{}
+
+	for i := range frontends.Items {
+		frontend := frontends.Items[i]
+
+		for _, bind := range frontend.Spec.Binds {
+			file, data, err := r.loadOCSPResponse(ctx, instance, bind)
+			if err != nil {
+				frontend.Status.Phase = configv1alpha1.StatusPhaseInternalError
+				frontend.Status.Error = err.Error()
+				return files, multierr.Combine(err, r.Status().Update(ctx, &frontend))
+			}
+			if file != "" {
+				files[file] = data
+			}
+		}
+	}
+
+	for i := range listens.Items {
+		listen := listens.Items[i]
+
+		for _, bind := range listen.Spec.Binds {
+			file, data, err := r.loadOCSPResponse(ctx, instance, bind)
+			if err != nil {
+				listen.Status.Phase = configv1alpha1.StatusPhaseInternalError
+				listen.Status.Error = err.Error()
+				return files, multierr.Combine(err, r.Status().Update(ctx, &listen))
+			}
+			if file != "" {
+				files[file] = data
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// loadOCSPResponse returns the path and the content of the OCSP response of the bind certificate, or an empty path if
+// the bind has no OCSP response.
+func (r *Reconciler) loadOCSPResponse(ctx context.Context, instance *proxyv1alpha1.Instance, bind configv1alpha1.Bind) (string, string, error) {
+	if bind.SSL == nil || !bind.SSL.Enabled || bind.SSL.Certificate == nil || bind.SSL.OCSP == nil || bind.SSL.OCSP.Response == nil {
+		return "", "", nil
+	}
+	ref := bind.SSL.OCSP.Response
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: instance.Namespace}, secret); err != nil {
+		return "", "", err
+	}
+
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", "", fmt.Errorf("key %s not found in OCSP response secret: %s/%s", ref.Key, instance.Namespace, ref.Name)
+	}
+
+	return bind.SSL.Certificate.OCSPFilePath(), string(data), nil
+}
+
+// bindCertificateListElements returns the elements of the crt-list of a bind. With OCSP updates, the certificate of
+// the bind is listed first, as it is the default certificate of the bind.
+func bindCertificateListElements(bind configv1alpha1.Bind) []configv1alpha1.CertificateListElement {
+	var elements []configv1alpha1.CertificateListElement
+	if bind.OCSPUpdate() && bind.SSL.Certificate != nil {
+		elements = append(elements, configv1alpha1.CertificateListElement{Certificate: *bind.SSL.Certificate})
+	}
+	if bind.SSLCertificateList != nil {
+		elements = append(elements, bind.SSLCertificateList.Elements...)
+	}
+
+	return elements
+}
+
+func certificateListLine(element configv1alpha1.CertificateListElement, ocspUpdate bool) string {
+	var options []string
+	if len(element.Alpn) > 0 {
+		options = append(options, fmt.Sprintf("alpn %s", strings.Join(element.Alpn, ",")))
+	}
+	if ocspUpdate {
+		options = append(options, "ocsp-update on")
+	}
+
+	var sslOptions string
+	if len(options) > 0 {
+		sslOptions = fmt.Sprintf("[%s]", strings.Join(options, " "))
+	}
+
+	return strings.Join([]string{element.Certificate.FilePath(), sslOptions, element.SNIFilter, "\n"}, " ")
+}
+
+// configChecksum returns a hash over the config secret data.
+func configChecksum(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		if key != reloadChecksumFile {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		buf.WriteString(key)
+		buf.Write(data[key])
+	}
+
+	return hash.GetMD5Hash(buf.String())
+}
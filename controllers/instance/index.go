@@ -0,0 +1,324 @@
+package instance
+
+import (
+	"context"
+
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Field indexes of the Instances and config objects holding the names of the referenced objects.
+const (
+	secretRefIndex      = "spec.secretRefs"
+	configMapRefIndex   = "spec.configMapRefs"
+	certificateRefIndex = "spec.certificateRefs"
+	// serviceRefIndex holds the Services in other namespaces as <namespace>/<name>.
+	serviceRefIndex = "spec.serviceRefs"
+)
+
+// indexedObjects are the kinds which may reference Secrets, ConfigMaps or cert-manager Certificates.
+func indexedObjects() []client.Object {
+	return []client.Object{
+		&proxyv1alpha1.Instance{},
+		&configv1alpha1.Listen{},
+		&configv1alpha1.Frontend{},
+		&configv1alpha1.Backend{},
+		&configv1alpha1.Userlist{},
+	}
+}
+
+// fieldIndexes returns the values of each field index from the references of an object.
+var fieldIndexes = map[string]func(*references) []string{
+	secretRefIndex:      func(r *references) []string { return r.secrets },
+	configMapRefIndex:   func(r *references) []string { return r.configMaps },
+	certificateRefIndex: func(r *references) []string { return r.certificates },
+	serviceRefIndex:     func(r *references) []string { return r.services },
+}
+
+func setupIndexes(ctx context.Context, mgr ctrl.Manager) error {
+	for _, object := range indexedObjects() {
+		for index, values := range fieldIndexes {
+			values := values
+			if err := mgr.GetFieldIndexer().IndexField(ctx, object, index, func(object client.Object) []string {
+				return values(objectReferences(object))
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// references holds the names of the objects referenced by an Instance or a config object.
+type references struct {
+	secrets      []string
+	configMaps   []string
+	certificates []string
+	services     []string
+}
+
+func objectReferences(object client.Object) *references {
+	refs := &references{}
+	refs.addCertificates(objectCertificates(object)...)
+
+	switch object := object.(type) {
+	case *configv1alpha1.Listen:
+		refs.addBaseSpec(&object.Spec.BaseSpec)
+		refs.addBinds(object.Spec.Binds)
+	case *configv1alpha1.Frontend:
+		refs.addBaseSpec(&object.Spec.BaseSpec)
+		refs.addBinds(object.Spec.Binds)
+	case *configv1alpha1.Backend:
+		refs.addBaseSpec(&object.Spec.BaseSpec)
+		for _, ref := range object.Spec.ServiceRefs {
+			if ref.Namespace != "" {
+				refs.services = append(refs.services, ref.Namespace+"/"+ref.Name)
+			}
+		}
+	case *configv1alpha1.Userlist:
+		for _, user := range object.Spec.Users {
+			refs.secrets = append(refs.secrets, user.Password.Name)
+		}
+	}
+
+	return refs
+}
+
+// objectCertificates returns the certificates of the binds and servers of a config object, including the
+// certificates of certificate lists, or the additional certificates of an Instance.
+func objectCertificates(object client.Object) []*configv1alpha1.SSLCertificate {
+	var certificates []*configv1alpha1.SSLCertificate
+
+	switch object := object.(type) {
+	case *proxyv1alpha1.Instance:
+		for idx := range object.Spec.Configuration.Global.AdditionalCertificates {
+			certificates = append(certificates, &object.Spec.Configuration.Global.AdditionalCertificates[idx])
+		}
+	case *configv1alpha1.Listen:
+		certificates = append(certificates, extractSLCCertificatesFromFrontend(object.ToFrontend())...)
+		certificates = append(certificates, extractSLCCertificatesFromBackend(object.ToBackend())...)
+		certificates = append(certificates, certificateListCertificates(object.Spec.Binds)...)
+		if object.Spec.HostCertificate != nil {
+			certificates = append(certificates, &object.Spec.HostCertificate.Certificate)
+		}
+	case *configv1alpha1.Frontend:
+		certificates = append(certificates, extractSLCCertificatesFromFrontend(object)...)
+		certificates = append(certificates, certificateListCertificates(object.Spec.Binds)...)
+	case *configv1alpha1.Backend:
+		certificates = append(certificates, extractSLCCertificatesFromBackend(object)...)
+		if object.Spec.HostCertificate != nil {
+			certificates = append(certificates, &object.Spec.HostCertificate.Certificate)
+		}
+	}
+
+	return certificates
+}
+
+func (r *references) addCertificates(certificates ...*configv1alpha1.SSLCertificate) {
+	for _, certificate := range certificates {
+		for _, ref := range certificate.ValueFrom {
+			if ref.ConfigMapKeyRef != nil {
+				r.configMaps = append(r.configMaps, ref.ConfigMapKeyRef.Name)
+			}
+			if ref.SecretKeyRef != nil {
+				r.secrets = append(r.secrets, ref.SecretKeyRef.Name)
+			}
+		}
+
+		if ref := certificate.CertificateRef; ref != nil {
+			if ref.Kind == configv1alpha1.CertificateRefKindCertificate {
+				r.certificates = append(r.certificates, ref.Name)
+			} else {
+				r.secrets = append(r.secrets, ref.Name)
+			}
+		}
+	}
+}
+
+func (r *references) addBaseSpec(spec *configv1alpha1.BaseSpec) {
+	for _, file := range spec.ErrorFiles {
+		if file != nil && file.File.ValueFrom.ConfigMapKeyRef != nil {
+			r.configMaps = append(r.configMaps, file.File.ValueFrom.ConfigMapKeyRef.Name)
+		}
+	}
+
+	var headers []configv1alpha1.HTTPHeaderRule
+	if spec.HTTPRequest != nil {
+		headers = append(headers, spec.HTTPRequest.SetHeader...)
+		headers = append(headers, spec.HTTPRequest.AddHeader...)
+		for _, rule := range spec.HTTPRequest.Rules {
+			if rule.SetHeader != nil {
+				headers = append(headers, *rule.SetHeader)
+			}
+			if rule.AddHeader != nil {
+				headers = append(headers, *rule.AddHeader)
+			}
+		}
+	}
+	if spec.HTTPResponse != nil {
+		headers = append(headers, spec.HTTPResponse.SetHeader...)
+		headers = append(headers, spec.HTTPResponse.AddHeader...)
+	}
+	if spec.HTTPAfterResponse != nil {
+		headers = append(headers, spec.HTTPAfterResponse.SetHeader...)
+		headers = append(headers, spec.HTTPAfterResponse.AddHeader...)
+	}
+
+	for _, header := range headers {
+		if header.Value.Env == nil || header.Value.Env.ValueFrom == nil {
+			continue
+		}
+		if ref := header.Value.Env.ValueFrom.SecretKeyRef; ref != nil {
+			r.secrets = append(r.secrets, ref.Name)
+		}
+		if ref := header.Value.Env.ValueFrom.ConfigMapKeyRef; ref != nil {
+			r.configMaps = append(r.configMaps, ref.Name)
+		}
+	}
+}
+
+func (r *references) addBinds(binds []configv1alpha1.Bind) {
+	for _, bind := range binds {
+		if bind.SSL != nil && bind.SSL.OCSP != nil && bind.SSL.OCSP.Response != nil {
+			r.secrets = append(r.secrets, bind.SSL.OCSP.Response.Name)
+		}
+	}
+}
+
+func certificateListCertificates(binds []configv1alpha1.Bind) []*configv1alpha1.SSLCertificate {
+	var certificates []*configv1alpha1.SSLCertificate
+	for _, bind := range binds {
+		if bind.SSLCertificateList == nil {
+			continue
+		}
+		for idx := range bind.SSLCertificateList.Elements {
+			certificates = append(certificates, &bind.SSLCertificateList.Elements[idx].Certificate)
+		}
+	}
+
+	return certificates
+}
+
+// findInstancesForSecret returns the instances referencing the Secret directly, through one of their config objects
+// or through the cert-manager Certificate which issued it.
+func (r *Reconciler) findInstancesForSecret(object client.Object) []reconcile.Request {
+	requests := r.findInstancesReferencing(object.GetNamespace(), secretRefIndex, object.GetName())
+	if certificate, ok := object.GetAnnotations()[certManagerCertificateNameAnnotation]; ok {
+		requests = append(requests, r.findInstancesReferencing(object.GetNamespace(), certificateRefIndex, certificate)...)
+	}
+
+	return uniqueRequests(requests)
+}
+
+// findInstancesForConfigMap returns the instances referencing the ConfigMap directly or through one of their config
+// objects.
+func (r *Reconciler) findInstancesForConfigMap(object client.Object) []reconcile.Request {
+	return r.findInstancesReferencing(object.GetNamespace(), configMapRefIndex, object.GetName())
+}
+
+func (r *Reconciler) findInstancesReferencing(namespace, index, name string) []reconcile.Request {
+	ctx := context.Background()
+	opts := []client.ListOption{client.InNamespace(namespace), client.MatchingFields{index: name}}
+
+	instances := &proxyv1alpha1.InstanceList{}
+	if err := r.List(ctx, instances, opts...); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range instances.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&instances.Items[i])})
+	}
+
+	var objects []client.Object
+
+	listens := &configv1alpha1.ListenList{}
+	if err := r.List(ctx, listens, opts...); err != nil {
+		return nil
+	}
+	for i := range listens.Items {
+		objects = append(objects, &listens.Items[i])
+	}
+
+	frontends := &configv1alpha1.FrontendList{}
+	if err := r.List(ctx, frontends, opts...); err != nil {
+		return nil
+	}
+	for i := range frontends.Items {
+		objects = append(objects, &frontends.Items[i])
+	}
+
+	backends := &configv1alpha1.BackendList{}
+	if err := r.List(ctx, backends, opts...); err != nil {
+		return nil
+	}
+	for i := range backends.Items {
+		objects = append(objects, &backends.Items[i])
+	}
+
+	userlists := &configv1alpha1.UserlistList{}
+	if err := r.List(ctx, userlists, opts...); err != nil {
+		return nil
+	}
+	for i := range userlists.Items {
+		objects = append(objects, &userlists.Items[i])
+	}
+
+	return uniqueRequests(append(requests, r.configObjectRequests(ctx, namespace, objects)...))
+}
+
+// configObjectRequests returns the instances of the config objects. The instance becomes the controller of a config
+// object once it has published its configuration, before that the config object is matched by the selectors.
+func (r *Reconciler) configObjectRequests(ctx context.Context, namespace string, objects []client.Object) []reconcile.Request {
+	requests := ownerInstanceRequests(objects)
+
+	var unowned []client.Object
+	for _, object := range objects {
+		if metav1.GetControllerOf(object) == nil {
+			unowned = append(unowned, object)
+		}
+	}
+	if len(unowned) == 0 {
+		return requests
+	}
+
+	instances := &proxyv1alpha1.InstanceList{}
+	if err := r.List(ctx, instances, client.InNamespace(namespace)); err != nil {
+		return requests
+	}
+	for i := range instances.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&instances.Items[i].Spec.Configuration.LabelSelector)
+		if err != nil {
+			continue
+		}
+		for _, object := range unowned {
+			if selector.Matches(labels.Set(object.GetLabels())) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&instances.Items[i])})
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
+func uniqueRequests(requests []reconcile.Request) []reconcile.Request {
+	var unique []reconcile.Request
+
+	seen := map[types.NamespacedName]bool{}
+	for _, request := range requests {
+		if !seen[request.NamespacedName] {
+			seen[request.NamespacedName] = true
+			unique = append(unique, request)
+		}
+	}
+
+	return unique
+}
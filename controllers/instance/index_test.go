@@ -0,0 +1,202 @@
+package instance
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Index", Label("controller"), func() {
+	var (
+		scheme   *runtime.Scheme
+		proxy    *proxyv1alpha1.Instance
+		other    *proxyv1alpha1.Instance
+		frontend *configv1alpha1.Frontend
+		backend  *configv1alpha1.Backend
+		listen   *configv1alpha1.Listen
+		userlist *configv1alpha1.Userlist
+		r        Reconciler
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Ω(clientgoscheme.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Ω(configv1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Ω(proxyv1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+
+		proxy = &proxyv1alpha1.Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", UID: uuid.NewUUID()},
+			Spec: proxyv1alpha1.InstanceSpec{
+				Configuration: proxyv1alpha1.Configuration{
+					Global: proxyv1alpha1.GlobalConfiguration{
+						AdditionalCertificates: []configv1alpha1.SSLCertificate{{
+							Name:      "client",
+							ValueFrom: []configv1alpha1.SSLCertificateValueFrom{{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "client-tls"}, Key: "tls.crt"}}},
+						}},
+					},
+					LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"instance": "foo"}},
+				},
+			},
+		}
+		other = &proxyv1alpha1.Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "bar", UID: uuid.NewUUID()},
+			Spec: proxyv1alpha1.InstanceSpec{
+				Configuration: proxyv1alpha1.Configuration{
+					LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"instance": "other"}},
+				},
+			},
+		}
+
+		owner := []metav1.OwnerReference{*metav1.NewControllerRef(proxy, proxyv1alpha1.GroupVersion.WithKind("Instance"))}
+		frontend = &configv1alpha1.Frontend{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "bar", Labels: map[string]string{"instance": "foo"}, OwnerReferences: owner},
+			Spec: configv1alpha1.FrontendSpec{
+				Binds: []configv1alpha1.Bind{{
+					Name: "https",
+					Port: 443,
+					SSL: &configv1alpha1.SSL{
+						Enabled: true,
+						Certificate: &configv1alpha1.SSLCertificate{
+							Name:           "web",
+							CertificateRef: &configv1alpha1.CertificateRef{Kind: configv1alpha1.CertificateRefKindCertificate, Name: "web"},
+						},
+					},
+				}},
+			},
+		}
+		backend = &configv1alpha1.Backend{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "bar", Labels: map[string]string{"instance": "foo"}, OwnerReferences: owner},
+			Spec: configv1alpha1.BackendSpec{
+				BaseSpec: configv1alpha1.BaseSpec{
+					ErrorFiles: []*configv1alpha1.ErrorFile{{
+						Code: 503,
+						File: configv1alpha1.StaticHTTPFile{
+							Name:      "503",
+							ValueFrom: configv1alpha1.ErrorFileValueFrom{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "errors"}, Key: "503.http"}},
+						},
+					}},
+				},
+			},
+		}
+		listen = &configv1alpha1.Listen{
+			ObjectMeta: metav1.ObjectMeta{Name: "tcp", Namespace: "bar", Labels: map[string]string{"instance": "foo"}, OwnerReferences: owner},
+			Spec: configv1alpha1.ListenSpec{
+				Binds:       []configv1alpha1.Bind{{Name: "tcp", Port: 5432}},
+				ServiceRefs: []configv1alpha1.ServiceReference{{Name: "stable"}, {Name: "canary"}},
+			},
+		}
+		userlist = &configv1alpha1.Userlist{
+			ObjectMeta: metav1.ObjectMeta{Name: "admins", Namespace: "bar", Labels: map[string]string{"instance": "other"}},
+			Spec: configv1alpha1.UserlistSpec{
+				Users: []configv1alpha1.User{{
+					Name:     "admin",
+					Password: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "admin-password"}, Key: "password"},
+				}},
+			},
+		}
+
+		r = Reconciler{
+			Client: &indexedClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy, other, frontend, backend, listen, userlist).Build()},
+			Scheme: scheme,
+		}
+	})
+
+	secret := func(name string, annotations map[string]string) client.Object {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "bar", Annotations: annotations}}
+	}
+
+	It("should enqueue the instance referencing a Secret", func() {
+		Ω(r.findInstancesForSecret(secret("client-tls", nil))).Should(Equal(requests("foo")))
+	})
+	It("should enqueue the instance of the config object referencing a ConfigMap", func() {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "errors", Namespace: "bar"}}
+		Ω(r.findInstancesForConfigMap(configMap)).Should(Equal(requests("foo")))
+	})
+	It("should enqueue the instance of the config object referencing the cert-manager Certificate of a Secret", func() {
+		Ω(r.findInstancesForSecret(secret("web-tls", map[string]string{certManagerCertificateNameAnnotation: "web"}))).Should(Equal(requests("foo")))
+		Ω(r.findInstancesForSecret(secret("web-tls", nil))).Should(BeEmpty())
+	})
+	It("should enqueue the instance selecting the Userlist referencing a password Secret", func() {
+		Ω(r.findInstancesForSecret(secret("admin-password", nil))).Should(Equal(requests("other")))
+	})
+	It("should enqueue the instance of the listen referencing the Service of an EndpointSlice", func() {
+		slice := func(service string) client.Object {
+			return &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Name: service + "-x7k2p", Namespace: "bar", Labels: map[string]string{discoveryv1.LabelServiceName: service}}}
+		}
+		Ω(r.findInstancesForEndpointSlice(slice("canary"))).Should(Equal(requests("foo")))
+		Ω(r.findInstancesForEndpointSlice(slice("unknown"))).Should(BeEmpty())
+	})
+	It("should not enqueue instances for unreferenced objects", func() {
+		Ω(r.findInstancesForSecret(secret("unknown", nil))).Should(BeEmpty())
+		Ω(r.findInstancesForConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "errors", Namespace: "other"}})).Should(BeEmpty())
+	})
+})
+
+func requests(names ...string) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, name := range names {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "bar", Name: name}})
+	}
+
+	return requests
+}
+
+// indexedClient filters the listed objects by the field indexes of the manager, which the fake client ignores.
+type indexedClient struct {
+	client.Client
+}
+
+func (c *indexedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+
+	options := client.ListOptions{}
+	options.ApplyOptions(opts)
+	if options.FieldSelector == nil {
+		return nil
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	var filtered []runtime.Object
+	for _, item := range items {
+		refs := objectReferences(item.(client.Object))
+		matches := true
+		for _, requirement := range options.FieldSelector.Requirements() {
+			matches = matches && contains(fieldIndexes[requirement.Field](refs), requirement.Value)
+		}
+		if matches {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return meta.SetList(list, filtered)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,74 +2,82 @@ package instance
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
 	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/pkg/runtimeapi"
+	"github.com/six-group/haproxy-operator/pkg/validation"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // Reconciler reconciles a Instance object
 type Reconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// RuntimeAPI creates the clients used to apply changes through the runtime API of the HAProxy pods.
+	RuntimeAPI runtimeapi.Factory
+	// ConfigValidator checks the generated configuration before it is published. Validation is skipped if not set.
+	ConfigValidator validation.ConfigValidator
+	// Recorder publishes an event with the diff of every configuration change and warnings about expiring
+	// certificates. No events are published if not set.
+	Recorder record.EventRecorder
+	// CertificateExpiryWarning is the time before the expiry of a certificate from which on warning events are
+	// published. Defaults to DefaultCertificateExpiryWarning.
+	CertificateExpiryWarning time.Duration
 }
 
 //+kubebuilder:rbac:groups=proxy.haproxy.com,resources=instances,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=proxy.haproxy.com,resources=instances/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=proxy.haproxy.com,resources=instances/finalizers,verbs=update
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	instance := &proxyv1alpha1.Instance{}
 	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
 		if errors.IsNotFound(err) {
+			certificateExpiry.set(req.NamespacedName, nil)
 			return reconcile.Result{}, nil
 		}
 
 		return reconcile.Result{}, err
 	}
 
-	selector, err := metav1.LabelSelectorAsSelector(&instance.Spec.Configuration.LabelSelector)
+	listens, frontends, backends, resolvers, peers, userlists, err := r.listConfigObjects(ctx, instance)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	listens := &configv1alpha1.ListenList{}
-	if err := r.List(ctx, listens, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	frontends := &configv1alpha1.FrontendList{}
-	if err := r.List(ctx, frontends, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	backends := &configv1alpha1.BackendList{}
-	if err := r.List(ctx, backends, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	resolvers := &configv1alpha1.ResolverList{}
-	if err := r.List(ctx, resolvers, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
-		return reconcile.Result{}, err
-	}
-
 	if len(listens.Items) == 0 && len(frontends.Items) == 0 {
-		instance.Status = proxyv1alpha1.InstanceStatus{
-			Phase: proxyv1alpha1.InstancePhasePending,
-			Error: "at least one listen or frontend must exist with the instance as owner",
-		}
+		instance.Status.Phase = proxyv1alpha1.InstancePhasePending
+		instance.Status.Error = "at least one listen or frontend must exist with the instance as owner"
 
 		return reconcile.Result{}, r.Status().Update(ctx, instance)
 	}
 
-	if err := r.reconcileConfig(ctx, instance, listens, frontends, backends, resolvers); err != nil {
+	files, err := r.reconcileConfig(ctx, instance, listens, frontends, backends, resolvers, peers, userlists)
+	if err != nil {
 		return reconcile.Result{}, r.handleError(ctx, instance, err)
 	}
 
@@ -79,6 +87,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 
+	if len(peers.Items) > 0 {
+		if err := r.reconcilePeersService(ctx, instance, peers); err != nil {
+			return reconcile.Result{}, r.handleError(ctx, instance, err)
+		}
+	}
+
 	if instance.Spec.Network.Route.Enabled {
 		if err := r.reconcileRoute(ctx, instance, listens, frontends); err != nil {
 			return reconcile.Result{}, r.handleError(ctx, instance, err)
@@ -91,54 +105,121 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 
-	if err := r.reconcileStatefulSet(ctx, instance); err != nil {
+	result, err := r.reconcileStatefulSet(ctx, instance, len(peers.Items) > 0, instance.Status.ConfigHash)
+	if err != nil {
 		return reconcile.Result{}, r.handleError(ctx, instance, err)
 	}
+	if !result.IsZero() {
+		return result, nil
+	}
 
-	instance.Status = proxyv1alpha1.InstanceStatus{
-		Phase: proxyv1alpha1.InstancePhaseRunning,
+	pods, err := r.podConfigStatus(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, r.handleError(ctx, instance, err)
 	}
+
+	instance.Status.Phase = proxyv1alpha1.InstancePhaseRunning
+	instance.Status.Error = ""
+	instance.Status.Pods = pods
 	if err := r.Status().Update(ctx, instance); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	r.updateConfig(ctx, instance, listens, frontends, backends, resolvers)
+	requeueAfter := r.updateConfig(ctx, instance, files, listens, frontends, backends, resolvers, peers, userlists)
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-func (r *Reconciler) handleError(ctx context.Context, instance *proxyv1alpha1.Instance, err error) error {
-	instance.Status = proxyv1alpha1.InstanceStatus{
-		Phase: proxyv1alpha1.InstancePhaseInternalError,
-		Error: err.Error(),
+// listConfigObjects returns the config objects selected by the instance.
+func (r *Reconciler) listConfigObjects(ctx context.Context, instance *proxyv1alpha1.Instance) (*configv1alpha1.ListenList, *configv1alpha1.FrontendList, *configv1alpha1.BackendList, *configv1alpha1.ResolverList, *configv1alpha1.PeersList, *configv1alpha1.UserlistList, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&instance.Spec.Configuration.LabelSelector)
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+	opts := []client.ListOption{client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}}
+
+	listens := &configv1alpha1.ListenList{}
+	frontends := &configv1alpha1.FrontendList{}
+	backends := &configv1alpha1.BackendList{}
+	resolvers := &configv1alpha1.ResolverList{}
+	peers := &configv1alpha1.PeersList{}
+	userlists := &configv1alpha1.UserlistList{}
+	for _, list := range []client.ObjectList{listens, frontends, backends, resolvers, peers, userlists} {
+		if err := r.List(ctx, list, opts...); err != nil {
+			return nil, nil, nil, nil, nil, nil, err
+		}
 	}
 
+	return listens, frontends, backends, resolvers, peers, userlists, nil
+}
+
+func (r *Reconciler) handleError(ctx context.Context, instance *proxyv1alpha1.Instance, err error) error {
+	instance.Status.Phase = proxyv1alpha1.InstancePhaseInternalError
+	instance.Status.Error = err.Error()
+
 	return r.Status().Update(ctx, instance)
 }
 
-func (r *Reconciler) updateConfig(ctx context.Context, instance *proxyv1alpha1.Instance, listens *configv1alpha1.ListenList, frontends *configv1alpha1.FrontendList, backends *configv1alpha1.BackendList, resolvers *configv1alpha1.ResolverList) {
+// updateConfig sets the instance as controller of the config objects, updates their status and exports the expiry
+// of their certificates found in the files of the configuration Secret. It returns the time after which the
+// certificates must be checked again.
+func (r *Reconciler) updateConfig(ctx context.Context, instance *proxyv1alpha1.Instance, files map[string][]byte, listens *configv1alpha1.ListenList, frontends *configv1alpha1.FrontendList, backends *configv1alpha1.BackendList, resolvers *configv1alpha1.ResolverList, peers *configv1alpha1.PeersList, userlists *configv1alpha1.UserlistList) time.Duration {
+	var objects []configv1alpha1.Object
 	for i := range listens.Items {
-		listen := listens.Items[i]
-		_ = r.updateConfigObject(ctx, instance, &listen)
+		objects = append(objects, &listens.Items[i])
 	}
-
 	for i := range frontends.Items {
-		frontend := frontends.Items[i]
-		_ = r.updateConfigObject(ctx, instance, &frontend)
+		objects = append(objects, &frontends.Items[i])
 	}
-
 	for i := range backends.Items {
-		backend := backends.Items[i]
-		_ = r.updateConfigObject(ctx, instance, &backend)
+		objects = append(objects, &backends.Items[i])
 	}
-
 	for i := range resolvers.Items {
-		resolvers := resolvers.Items[i]
-		_ = r.updateConfigObject(ctx, instance, &resolvers)
+		objects = append(objects, &resolvers.Items[i])
+	}
+	for i := range peers.Items {
+		objects = append(objects, &peers.Items[i])
+	}
+	for i := range userlists.Items {
+		objects = append(objects, &userlists.Items[i])
+	}
+
+	// the additional certificates of the instance are only exported and warned about, as there is no status to
+	// report them in
+	var certificates []certificateExpiryMetric
+	for _, certificate := range certificateStatuses(instance, files) {
+		certificates = append(certificates, certificateExpiryMetric{
+			kind:     "Instance",
+			name:     instance.Name,
+			file:     certificate.File,
+			notAfter: certificate.NotAfter.Time,
+		})
+		r.warnExpiringCertificates(instance, []configv1alpha1.CertificateStatus{certificate})
+	}
+
+	for _, object := range objects {
+		_ = r.updateConfigObject(ctx, instance, object, files)
+
+		gvk, err := apiutil.GVKForObject(object, r.Scheme)
+		if err != nil {
+			continue
+		}
+		for _, certificate := range object.GetStatus().Certificates {
+			certificates = append(certificates, certificateExpiryMetric{
+				kind:     gvk.Kind,
+				name:     object.GetName(),
+				file:     certificate.File,
+				notAfter: certificate.NotAfter.Time,
+			})
+		}
 	}
+
+	certificateExpiry.set(client.ObjectKeyFromObject(instance), certificates)
+
+	return r.certificateRequeueAfter(certificates)
 }
 
-func (r *Reconciler) updateConfigObject(ctx context.Context, instance *proxyv1alpha1.Instance, object configv1alpha1.Object) error {
+func (r *Reconciler) updateConfigObject(ctx context.Context, instance *proxyv1alpha1.Instance, object configv1alpha1.Object, files map[string][]byte) error {
 	logger := log.FromContext(ctx)
 
 	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, object, func() error {
@@ -149,10 +230,19 @@ func (r *Reconciler) updateConfigObject(ctx context.Context, instance *proxyv1al
 		return err
 	}
 
-	object.SetStatus(configv1alpha1.Status{
+	status := configv1alpha1.Status{
 		Phase:              configv1alpha1.StatusPhaseActive,
 		ObservedGeneration: object.GetGeneration(),
-	})
+	}
+	status.Certificates = certificateStatuses(object, files)
+	r.warnExpiringCertificates(object, status.Certificates)
+	object.SetStatus(status)
+	if frontend, ok := object.(*configv1alpha1.Frontend); ok {
+		frontend.Status.TrafficSplit = nil
+		if frontend.Spec.TrafficSplit != nil {
+			frontend.Status.TrafficSplit, _ = frontend.Spec.TrafficSplit.EffectiveWeights()
+		}
+	}
 	if err := r.Status().Update(ctx, object); err != nil {
 		logger.Error(err, "Unable to update status", object.GetObjectKind().GroupVersionKind().Kind, object.GetName())
 		return err
@@ -163,11 +253,112 @@ func (r *Reconciler) updateConfigObject(ctx context.Context, instance *proxyv1al
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := setupIndexes(context.Background(), mgr); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&proxyv1alpha1.Instance{}).
 		Owns(&configv1alpha1.Listen{}).
 		Owns(&configv1alpha1.Frontend{}).
 		Owns(&configv1alpha1.Backend{}).
 		Owns(&configv1alpha1.Resolver{}).
+		Owns(&configv1alpha1.Peers{}).
+		Owns(&configv1alpha1.Userlist{}).
+		Watches(&source.Kind{Type: &discoveryv1.EndpointSlice{}}, handler.EnqueueRequestsFromMapFunc(r.findInstancesForEndpointSlice)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.findInstancesForSecret)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.findInstancesForConfigMap)).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(findInstanceForPod), builder.WithPredicates(predicate.AnnotationChangedPredicate{})).
 		Complete(r)
 }
+
+func (r *Reconciler) findInstancesForEndpointSlice(object client.Object) []reconcile.Request {
+	ctx := context.Background()
+
+	service, ok := object.GetLabels()[discoveryv1.LabelServiceName]
+	if !ok {
+		return nil
+	}
+
+	var objects []client.Object
+
+	listens := &configv1alpha1.ListenList{}
+	if err := r.List(ctx, listens, client.InNamespace(object.GetNamespace())); err != nil {
+		return nil
+	}
+	for i := range listens.Items {
+		if ref := listens.Items[i].Spec.ServiceRef; ref != nil && ref.Name == service {
+			objects = append(objects, &listens.Items[i])
+			continue
+		}
+		for _, ref := range listens.Items[i].Spec.ServiceRefs {
+			if ref.Name == service && (ref.Namespace == "" || ref.Namespace == object.GetNamespace()) {
+				objects = append(objects, &listens.Items[i])
+				break
+			}
+		}
+	}
+
+	backends := &configv1alpha1.BackendList{}
+	if err := r.List(ctx, backends, client.InNamespace(object.GetNamespace())); err != nil {
+		return nil
+	}
+	for i := range backends.Items {
+		if ref := backends.Items[i].Spec.ServiceRef; ref != nil && ref.Name == service {
+			objects = append(objects, &backends.Items[i])
+			continue
+		}
+		for _, ref := range backends.Items[i].Spec.ServiceRefs {
+			if ref.Name == service && (ref.Namespace == "" || ref.Namespace == object.GetNamespace()) {
+				objects = append(objects, &backends.Items[i])
+				break
+			}
+		}
+	}
+
+	// backends generated for gateways may reference Services in other namespaces
+	backends = &configv1alpha1.BackendList{}
+	if err := r.List(ctx, backends, client.MatchingFields{serviceRefIndex: object.GetNamespace() + "/" + service}); err != nil {
+		return nil
+	}
+	for i := range backends.Items {
+		for _, ref := range backends.Items[i].Spec.ServiceRefs {
+			if ref.Namespace == object.GetNamespace() && ref.Name == service && backends.Items[i].Namespace != object.GetNamespace() {
+				objects = append(objects, &backends.Items[i])
+				break
+			}
+		}
+	}
+
+	return ownerInstanceRequests(objects)
+}
+
+// findInstanceForPod returns the instance of an HAProxy pod to update the configuration status of its pods.
+func findInstanceForPod(object client.Object) []reconcile.Request {
+	name, ok := object.GetLabels()["app.kubernetes.io/name"]
+	if !ok || !strings.HasSuffix(name, "-haproxy") {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: object.GetNamespace(), Name: strings.TrimSuffix(name, "-haproxy")}}}
+}
+
+func ownerInstanceRequests(objects []client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	seen := map[types.NamespacedName]bool{}
+	for _, object := range objects {
+		owner := metav1.GetControllerOf(object)
+		if owner == nil || owner.Kind != "Instance" || owner.APIVersion != proxyv1alpha1.GroupVersion.String() {
+			continue
+		}
+
+		name := types.NamespacedName{Namespace: object.GetNamespace(), Name: owner.Name}
+		if !seen[name] {
+			seen[name] = true
+			requests = append(requests, reconcile.Request{NamespacedName: name})
+		}
+	}
+
+	return requests
+}
@@ -1,27 +1,46 @@
 package instance_test
 
 import (
+	"bufio"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"net"
 	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
 	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
 	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
 	"github.com/six-group/haproxy-operator/controllers/instance"
+	"github.com/six-group/haproxy-operator/pkg/runtimeapi"
+	"github.com/six-group/haproxy-operator/pkg/validation"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var _ = Describe("Reconcile", Label("controller"), func() {
@@ -454,5 +473,920 @@ var _ = Describe("Reconcile", Label("controller"), func() {
 			Ω(secret.Data["route.name.tcp.crt"]).Should(Equal([]byte("Key2\n\nCertificate2\n\nCAcertificate2")))
 			Ω(secret.Data["route.name4.crt"]).Should(Equal([]byte("Key\n\nCertificate\n\nCAcertificate")))
 		})
+		It("should discover backend servers from service endpoints", func() {
+			backend.Spec.Servers = nil
+			backend.Spec.ServiceRef = &configv1alpha1.ServiceReference{Name: "app"}
+			slice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app-x7k2p",
+					Namespace: proxy.Namespace,
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "app"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports:       []discoveryv1.EndpointPort{{Port: pointer.Int32(8080)}},
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{"10.0.0.1"},
+						TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "app-0"},
+					},
+				},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, slice)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result).ShouldNot(BeNil())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			Ω(proxy.Status.Phase).Should(Equal(proxyv1alpha1.InstancePhaseRunning))
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("backend foo-back\n  server app-0 10.0.0.1:8080\n"))
+		})
+		It("should discover listen servers from the endpoints of several services", func() {
+			listen.Spec.Servers = nil
+			listen.Spec.ServiceRefs = []configv1alpha1.ServiceReference{
+				{Name: "stable", ServerParams: configv1alpha1.ServerParams{Weight: pointer.Int64(90)}},
+				{Name: "canary", ServerParams: configv1alpha1.ServerParams{Weight: pointer.Int64(10)}},
+			}
+			slice := func(service, address string) *discoveryv1.EndpointSlice {
+				return &discoveryv1.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      service + "-x7k2p",
+						Namespace: proxy.Namespace,
+						Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+					},
+					AddressType: discoveryv1.AddressTypeIPv4,
+					Ports:       []discoveryv1.EndpointPort{{Port: pointer.Int32(8080)}},
+					Endpoints: []discoveryv1.Endpoint{
+						{
+							Addresses: []string{address},
+							TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: service + "-0"},
+						},
+					},
+				}
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, slice("stable", "10.0.0.1"), slice("canary", "10.0.0.2"))...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			Ω(proxy.Status.Phase).Should(Equal(proxyv1alpha1.InstancePhaseRunning))
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("  server stable-stable-0 10.0.0.1:8080 weight 90\n  server canary-canary-0 10.0.0.2:8080 weight 10\n"))
+		})
+		It("should only discover servers in other namespaces for gateways", func() {
+			backend.Spec.Servers = nil
+			backend.Spec.ServiceRefs = []configv1alpha1.ServiceReference{{Name: "app", Namespace: "bar"}}
+			slice := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app-x7k2p",
+					Namespace: "bar",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "app"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports:       []discoveryv1.EndpointPort{{Port: pointer.Int32(8080)}},
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses: []string{"10.0.0.1"},
+						TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "app-0"},
+					},
+				},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, slice)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			Ω(proxy.Status.Phase).Should(Equal(proxyv1alpha1.InstancePhaseInternalError))
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(backend), backend)).ShouldNot(HaveOccurred())
+			Ω(backend.Status.Error).Should(ContainSubstring("services in other namespaces are only supported for backends generated for gateways"))
+
+			// the gateway controller checks the ReferenceGrants before it references services in other namespaces
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			proxy.OwnerReferences = []metav1.OwnerReference{
+				{APIVersion: "gateway.networking.k8s.io/v1beta1", Kind: "Gateway", Name: proxy.Name, UID: uuid.NewUUID(), Controller: pointer.Bool(true)},
+			}
+			Ω(cli.Update(ctx, proxy)).ShouldNot(HaveOccurred())
+			backend.OwnerReferences = []metav1.OwnerReference{
+				{APIVersion: proxyv1alpha1.GroupVersion.String(), Kind: "Instance", Name: proxy.Name, UID: proxy.UID, Controller: pointer.Bool(true)},
+			}
+			Ω(cli.Update(ctx, backend)).ShouldNot(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("backend foo-back\n  server bar-app-app-0 10.0.0.1:8080\n"))
+		})
+		It("should keep the previous config if validation fails", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			validator := &validation.FakeValidator{}
+			r := instance.Reconciler{
+				Client:          cli,
+				Scheme:          scheme,
+				ConfigValidator: validator,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(validator.Files).Should(HaveKey("haproxy.cfg"))
+			Ω(validator.Files).Should(HaveKey("cert_list.map"))
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			config := secret.Data["haproxy.cfg"]
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(backend), backend)).ShouldNot(HaveOccurred())
+			backend.Spec.Redispatch = pointer.Bool(true)
+			Ω(cli.Update(ctx, backend)).ShouldNot(HaveOccurred())
+
+			validator.Err = &validation.ConfigError{Line: 21, Text: "option redispatch", Message: "unknown keyword"}
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			Ω(proxy.Status.Phase).Should(Equal(proxyv1alpha1.InstancePhaseInternalError))
+			Ω(proxy.Status.Error).Should(Equal("invalid configuration at line 21 'option redispatch': unknown keyword"))
+
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(secret.Data["haproxy.cfg"]).Should(Equal(config))
+		})
+
+		It("should apply server changes through the runtime API", func() {
+			proxy.Spec.Configuration.Global.Reload = true
+			proxy.Spec.Configuration.Global.RuntimeSync = &proxyv1alpha1.RuntimeSyncConfiguration{Enabled: true}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar-foo-haproxy-0",
+					Namespace: proxy.Namespace,
+					Labels:    map[string]string{"app.kubernetes.io/name": "bar-foo-haproxy"},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.1.0.1"},
+			}
+
+			commands := map[string][]string{}
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, pod)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+				RuntimeAPI: func(address string) runtimeapi.Client {
+					return &fakeRuntimeClient{address: address, commands: commands}
+				},
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(commands).Should(BeEmpty())
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("stats socket ipv4@0.0.0.0:9999 crt /usr/local/etc/haproxy/runtime-api.pem ca-file /usr/local/etc/haproxy/runtime-api-ca.crt ssl verify required level admin\n"))
+
+			// the runtime API only accepts the client certificate of the operator
+			tlsSecret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-runtime-tls"}, tlsSecret)).ShouldNot(HaveOccurred())
+			Ω(secret.Data["runtime-api.pem"]).Should(Equal(tlsSecret.Data["server.pem"]))
+			Ω(secret.Data["runtime-api-ca.crt"]).Should(Equal(tlsSecret.Data["ca.crt"]))
+			pool := x509.NewCertPool()
+			Ω(pool.AppendCertsFromPEM(tlsSecret.Data["ca.crt"])).Should(BeTrue())
+			block, _ := pem.Decode(tlsSecret.Data["client.crt"])
+			clientCert, err := x509.ParseCertificate(block.Bytes)
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = clientCert.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+			Ω(err).ShouldNot(HaveOccurred())
+			checksum := secret.Data["reload.checksum"]
+			Ω(checksum).ShouldNot(BeEmpty())
+
+			statefulSet := &appsv1.StatefulSet{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy"}, statefulSet)).ShouldNot(HaveOccurred())
+			Ω(statefulSet.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{Name: "WATCH_PATH", Value: "/usr/local/etc/haproxy/reload.checksum"}))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(backend), backend)).ShouldNot(HaveOccurred())
+			backend.Spec.Servers[0].Weight = pointer.Int64(10)
+			backend.Spec.Servers = append(backend.Spec.Servers, configv1alpha1.Server{Name: "server2", Address: "localhost", Port: 81})
+			Ω(cli.Update(ctx, backend)).ShouldNot(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(commands["10.1.0.1:9999"]).Should(Equal([]string{
+				"set server foo-back/server weight 10",
+				"add server foo-back/server2 localhost:81",
+				"set server foo-back/server2 state ready",
+			}))
+
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(secret.Data["reload.checksum"]).Should(Equal(checksum))
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("server server2 localhost:81"))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(backend), backend)).ShouldNot(HaveOccurred())
+			backend.Spec.Redispatch = pointer.Bool(true)
+			Ω(cli.Update(ctx, backend)).ShouldNot(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(commands["10.1.0.1:9999"]).Should(HaveLen(3))
+
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(secret.Data["reload.checksum"]).ShouldNot(Equal(checksum))
+		})
+		It("should apply changes through the TLS runtime API with the certificates of the instance", func() {
+			// the listener is secured with the certificates of the instance once they are created
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Ω(err).ShouldNot(HaveOccurred())
+			DeferCleanup(listener.Close)
+			port := listener.Addr().(*net.TCPAddr).Port
+
+			proxy.Spec.Configuration.Global.Reload = true
+			proxy.Spec.Configuration.Global.RuntimeSync = &proxyv1alpha1.RuntimeSyncConfiguration{Enabled: true, Port: pointer.Int64(int64(port))}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar-foo-haproxy-0",
+					Namespace: proxy.Namespace,
+					Labels:    map[string]string{"app.kubernetes.io/name": "bar-foo-haproxy"},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "127.0.0.1"},
+			}
+
+			// the factory is not set, like in main
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, pod)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			tlsSecret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-runtime-tls"}, tlsSecret)).ShouldNot(HaveOccurred())
+			serverCert, err := tls.X509KeyPair(tlsSecret.Data["server.pem"], tlsSecret.Data["server.pem"])
+			Ω(err).ShouldNot(HaveOccurred())
+			pool := x509.NewCertPool()
+			Ω(pool.AppendCertsFromPEM(tlsSecret.Data["ca.crt"])).Should(BeTrue())
+			tlsListener := tls.NewListener(listener, &tls.Config{
+				Certificates: []tls.Certificate{serverCert},
+				ClientCAs:    pool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				MinVersion:   tls.VersionTLS12,
+			})
+
+			commands := make(chan string, 10)
+			go func() {
+				for {
+					conn, err := tlsListener.Accept()
+					if err != nil {
+						return
+					}
+					if command, err := bufio.NewReader(conn).ReadString('\n'); err == nil {
+						commands <- strings.TrimSpace(command)
+						_, _ = conn.Write([]byte("\n"))
+					}
+					conn.Close()
+				}
+			}()
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			checksum := secret.Data["reload.checksum"]
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(backend), backend)).ShouldNot(HaveOccurred())
+			backend.Spec.Servers[0].Weight = pointer.Int64(10)
+			Ω(cli.Update(ctx, backend)).ShouldNot(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(commands).Should(Receive(Equal("set server foo-back/server weight 10")))
+
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(secret.Data["reload.checksum"]).Should(Equal(checksum))
+		})
+		It("should fall back to a reload if the runtime API fails", func() {
+			proxy.Spec.Configuration.Global.Reload = true
+			proxy.Spec.Configuration.Global.RuntimeSync = &proxyv1alpha1.RuntimeSyncConfiguration{Enabled: true}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar-foo-haproxy-0",
+					Namespace: proxy.Namespace,
+					Labels:    map[string]string{"app.kubernetes.io/name": "bar-foo-haproxy"},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.1.0.1"},
+			}
+
+			commands := map[string][]string{}
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, pod)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+				RuntimeAPI: func(address string) runtimeapi.Client {
+					// the config secret must already contain the new configuration when the commands are applied
+					secret := &corev1.Secret{}
+					Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+					Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("server server2 localhost:81"))
+
+					return &fakeRuntimeClient{address: address, commands: commands, err: fmt.Errorf("connection refused")}
+				},
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			checksum := secret.Data["reload.checksum"]
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(backend), backend)).ShouldNot(HaveOccurred())
+			backend.Spec.Servers = append(backend.Spec.Servers, configv1alpha1.Server{Name: "server2", Address: "localhost", Port: 81})
+			Ω(cli.Update(ctx, backend)).ShouldNot(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(commands["10.1.0.1:9999"]).Should(HaveLen(1))
+
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(secret.Data["reload.checksum"]).ShouldNot(Equal(checksum))
+		})
+		It("should report the applied configuration in the status", func() {
+			proxy.Spec.Configuration.Global.Reload = true
+			proxy.Spec.Configuration.Global.RuntimeSync = &proxyv1alpha1.RuntimeSyncConfiguration{Enabled: true}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar-foo-haproxy-0",
+					Namespace: proxy.Namespace,
+					Labels:    map[string]string{"app.kubernetes.io/name": "bar-foo-haproxy"},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.1.0.1"},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, pod)...).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := instance.Reconciler{
+				Client:   cli,
+				Scheme:   scheme,
+				Recorder: recorder,
+				RuntimeAPI: func(address string) runtimeapi.Client {
+					return &fakeRuntimeClient{address: address, commands: map[string][]string{}}
+				},
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(recorder.Events).Should(BeEmpty())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			hash := proxy.Status.ConfigHash
+			Ω(hash).ShouldNot(BeEmpty())
+			Ω(proxy.Status.LastAppliedTime).ShouldNot(BeNil())
+			Ω(proxy.Status.AppliedGenerations).Should(ContainElement(proxyv1alpha1.AppliedGeneration{Kind: "Backend", Name: backend.Name, Generation: backend.Generation}))
+			Ω(proxy.Status.Pods).Should(BeEmpty())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(backend), backend)).ShouldNot(HaveOccurred())
+			backend.Spec.Servers[0].Weight = pointer.Int64(10)
+			Ω(cli.Update(ctx, backend)).ShouldNot(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			Ω(proxy.Status.ConfigHash).ShouldNot(Equal(hash))
+			Ω(proxy.Status.Pods).Should(Equal([]proxyv1alpha1.PodConfigStatus{{Name: pod.Name, ConfigHash: proxy.Status.ConfigHash, UpToDate: true}}))
+
+			Ω(recorder.Events).Should(HaveLen(1))
+			event := <-recorder.Events
+			Ω(event).Should(HavePrefix(fmt.Sprintf("Normal ConfigChanged Configuration changed from %s to %s\n", hash, proxy.Status.ConfigHash)))
+			Ω(event).Should(ContainSubstring("--- a/haproxy.cfg\n+++ b/haproxy.cfg\n"))
+			Ω(event).Should(ContainSubstring("\n+  server server localhost:80 check ssl alpn h2,http/1.0 ca-file /usr/local/etc/haproxy/test-ca.crt inter 5000 verify required verifyhost routername.namespace.svc weight 10\n"))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(backend), backend)).ShouldNot(HaveOccurred())
+			backend.Spec.Redispatch = pointer.Bool(true)
+			Ω(cli.Update(ctx, backend)).ShouldNot(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			Ω(proxy.Status.Pods).Should(BeEmpty())
+		})
+		It("should bundle referenced TLS secrets and renew them through the runtime API", func() {
+			proxy.Spec.Configuration.Global.Reload = true
+			proxy.Spec.Configuration.Global.RuntimeSync = &proxyv1alpha1.RuntimeSyncConfiguration{Enabled: true}
+			frontend.Spec.Binds = []configv1alpha1.Bind{{
+				Name: "https",
+				Port: 443,
+				SSL: &configv1alpha1.SSL{
+					Enabled: true,
+					Certificate: &configv1alpha1.SSLCertificate{
+						Name:           "web",
+						CertificateRef: &configv1alpha1.CertificateRef{Kind: configv1alpha1.CertificateRefKindCertificate, Name: "web"},
+					},
+				},
+			}}
+			backend.Spec.Servers[0].SSL.Certificate = &configv1alpha1.SSLCertificate{
+				Name:           "client",
+				CertificateRef: &configv1alpha1.CertificateRef{Name: "client-tls"},
+			}
+
+			certificate := &unstructured.Unstructured{}
+			certificate.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"})
+			certificate.SetName("web")
+			certificate.SetNamespace(proxy.Namespace)
+			Ω(unstructured.SetNestedField(certificate.Object, "web-tls", "spec", "secretName")).ShouldNot(HaveOccurred())
+			webSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-tls", Namespace: proxy.Namespace},
+				Type:       corev1.SecretTypeTLS,
+				Data:       map[string][]byte{"tls.crt": []byte("web-cert\n"), "tls.key": []byte("web-key\n"), "ca.crt": []byte("web-ca\n")},
+			}
+			clientSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "client-tls", Namespace: proxy.Namespace},
+				Type:       corev1.SecretTypeTLS,
+				Data:       map[string][]byte{"tls.crt": []byte("client-cert"), "tls.key": []byte("client-key")},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar-foo-haproxy-0",
+					Namespace: proxy.Namespace,
+					Labels:    map[string]string{"app.kubernetes.io/name": "bar-foo-haproxy"},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.1.0.1"},
+			}
+
+			commands := map[string][]string{}
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, certificate, webSecret, clientSecret, pod)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+				RuntimeAPI: func(address string) runtimeapi.Client {
+					return &fakeRuntimeClient{address: address, commands: commands}
+				},
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("  bind :443 name https crt /usr/local/etc/haproxy/web.pem ssl\n"))
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring(" crt /usr/local/etc/haproxy/client.pem "))
+			Ω(string(secret.Data["web.pem"])).Should(Equal("web-cert\nweb-key\nweb-ca"))
+			Ω(string(secret.Data["client.pem"])).Should(Equal("client-cert\nclient-key"))
+			checksum := secret.Data["reload.checksum"]
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(webSecret), webSecret)).ShouldNot(HaveOccurred())
+			webSecret.Data["tls.crt"] = []byte("renewed-cert")
+			Ω(cli.Update(ctx, webSecret)).ShouldNot(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(commands["10.1.0.1:9999"]).Should(Equal([]string{
+				"set ssl cert /usr/local/etc/haproxy/web.pem <<\nrenewed-cert\nweb-key\nweb-ca\n",
+				"commit ssl cert /usr/local/etc/haproxy/web.pem",
+			}))
+
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["web.pem"])).Should(Equal("renewed-cert\nweb-key\nweb-ca"))
+			Ω(secret.Data["reload.checksum"]).Should(Equal(checksum))
+		})
+		It("should fail if a referenced TLS secret has no private key", func() {
+			frontend.Spec.Binds = []configv1alpha1.Bind{{
+				Name: "https",
+				Port: 443,
+				SSL: &configv1alpha1.SSL{
+					Enabled:     true,
+					Certificate: &configv1alpha1.SSLCertificate{Name: "web", CertificateRef: &configv1alpha1.CertificateRef{Name: "web-tls"}},
+				},
+			}}
+			webSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-tls", Namespace: proxy.Namespace},
+				Data:       map[string][]byte{"tls.crt": []byte("web-cert")},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, webSecret)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(frontend), frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Status.Phase).Should(Equal(configv1alpha1.StatusPhaseInternalError))
+			Ω(frontend.Status.Error).Should(Equal("key tls.key not found in TLS secret: foo/web-tls"))
+		})
+		It("should load certificates with OCSP updates through a crt-list", func() {
+			certificate := "web-cert"
+			frontend.Spec.Binds = []configv1alpha1.Bind{{
+				Name: "https",
+				Port: 443,
+				SSL: &configv1alpha1.SSL{
+					Enabled:     true,
+					Certificate: &configv1alpha1.SSLCertificate{Name: "web", Value: &certificate},
+					OCSP:        &configv1alpha1.OCSP{Update: true},
+				},
+			}}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("  bind :443 name https ssl crt-list /usr/local/etc/haproxy/web.crt-list\n"))
+			Ω(string(secret.Data["web.crt-list"])).Should(Equal("/usr/local/etc/haproxy/web.crt [ocsp-update on]  \n"))
+			Ω(string(secret.Data["web.crt"])).Should(Equal("web-cert"))
+		})
+		It("should staple OCSP responses from secrets and refresh them through the runtime API", func() {
+			proxy.Spec.Configuration.Global.Reload = true
+			proxy.Spec.Configuration.Global.RuntimeSync = &proxyv1alpha1.RuntimeSyncConfiguration{Enabled: true}
+			certificate := "web-cert"
+			frontend.Spec.Binds = []configv1alpha1.Bind{{
+				Name: "https",
+				Port: 443,
+				SSL: &configv1alpha1.SSL{
+					Enabled:     true,
+					Certificate: &configv1alpha1.SSLCertificate{Name: "web", Value: &certificate},
+					OCSP: &configv1alpha1.OCSP{
+						Response: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "web-ocsp"}, Key: "response.der"},
+					},
+				},
+			}}
+			ocspSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-ocsp", Namespace: proxy.Namespace},
+				Data:       map[string][]byte{"response.der": {0x30, 0x82, 0x01}},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar-foo-haproxy-0",
+					Namespace: proxy.Namespace,
+					Labels:    map[string]string{"app.kubernetes.io/name": "bar-foo-haproxy"},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.1.0.1"},
+			}
+
+			commands := map[string][]string{}
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, ocspSecret, pod)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+				RuntimeAPI: func(address string) runtimeapi.Client {
+					return &fakeRuntimeClient{address: address, commands: commands}
+				},
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("  bind :443 name https crt /usr/local/etc/haproxy/web.crt ssl\n"))
+			Ω(secret.Data["web.crt.ocsp"]).Should(Equal([]byte{0x30, 0x82, 0x01}))
+			checksum := secret.Data["reload.checksum"]
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(ocspSecret), ocspSecret)).ShouldNot(HaveOccurred())
+			ocspSecret.Data["response.der"] = []byte{0x30, 0x82, 0x02}
+			Ω(cli.Update(ctx, ocspSecret)).ShouldNot(HaveOccurred())
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(commands["10.1.0.1:9999"]).Should(Equal([]string{"set ssl ocsp-response <<\nMIIC\n"}))
+
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(secret.Data["web.crt.ocsp"]).Should(Equal([]byte{0x30, 0x82, 0x02}))
+			Ω(secret.Data["reload.checksum"]).Should(Equal(checksum))
+		})
+		It("should write the CAs and CRLs of client certificate policies", func() {
+			ca := "clients-ca"
+			frontend.Spec.Binds = []configv1alpha1.Bind{{
+				Name: "https",
+				Port: 443,
+				SSL: &configv1alpha1.SSL{
+					Enabled:       true,
+					CACertificate: &configv1alpha1.SSLCertificate{Name: "clients-ca", Value: &ca},
+					MutualTLS: &configv1alpha1.MutualTLS{
+						CRL: &configv1alpha1.SSLCertificate{
+							Name: "clients-crl",
+							ValueFrom: []configv1alpha1.SSLCertificateValueFrom{{
+								ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "clients"}, Key: "crl.pem"},
+							}},
+						},
+					},
+				},
+			}}
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "clients", Namespace: proxy.Namespace},
+				Data:       map[string]string{"crl.pem": "clients-crl\n"},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, configMap)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("  bind :443 name https ca-file /usr/local/etc/haproxy/clients-ca.crt ssl verify required crl-file /usr/local/etc/haproxy/clients-crl.crt\n"))
+			Ω(string(secret.Data["clients-ca.crt"])).Should(Equal("clients-ca"))
+			Ω(string(secret.Data["clients-crl.crt"])).Should(Equal("clients-crl"))
+		})
+		It("should report the certificates and warn before they expire", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Ω(err).ShouldNot(HaveOccurred())
+			notAfter := time.Now().Add(10 * 24 * time.Hour).Truncate(time.Second)
+			template := &x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: "example.com"},
+				DNSNames:     []string{"example.com", "www.example.com"},
+				NotBefore:    time.Now().Add(-time.Hour),
+				NotAfter:     notAfter,
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+			Ω(err).ShouldNot(HaveOccurred())
+			certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+			frontend.Spec.Binds = []configv1alpha1.Bind{{
+				Name: "https",
+				Port: 443,
+				SSL: &configv1alpha1.SSL{
+					Enabled:     true,
+					Certificate: &configv1alpha1.SSLCertificate{Name: "example", Value: &certificate},
+				},
+			}}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := instance.Reconciler{
+				Client:   cli,
+				Scheme:   scheme,
+				Recorder: recorder,
+			}
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result.RequeueAfter).Should(BeNumerically("~", time.Until(notAfter), time.Minute))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(frontend), frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Status.Certificates).Should(HaveLen(1))
+			Ω(frontend.Status.Certificates[0].File).Should(Equal("/usr/local/etc/haproxy/example.crt"))
+			Ω(frontend.Status.Certificates[0].Subject).Should(Equal("CN=example.com"))
+			Ω(frontend.Status.Certificates[0].Issuer).Should(Equal("CN=example.com"))
+			Ω(frontend.Status.Certificates[0].SANs).Should(Equal([]string{"example.com", "www.example.com"}))
+			Ω(frontend.Status.Certificates[0].NotAfter.Time).Should(BeTemporally("==", notAfter))
+
+			Ω(recorder.Events).Should(Receive(Equal(fmt.Sprintf("Warning CertificateExpiring Certificate /usr/local/etc/haproxy/example.crt (CN=example.com) expires at %s", notAfter.UTC().Format(time.RFC3339)))))
+
+			families, err := metrics.Registry.Gather()
+			Ω(err).ShouldNot(HaveOccurred())
+			var expiry *dto.MetricFamily
+			for _, family := range families {
+				if family.GetName() == "haproxy_operator_certificate_expiry_seconds" {
+					expiry = family
+				}
+			}
+			Ω(expiry).ShouldNot(BeNil())
+			Ω(expiry.GetMetric()).Should(HaveLen(1))
+			Ω(expiry.GetMetric()[0].GetGauge().GetValue()).Should(Equal(float64(notAfter.Unix())))
+
+			r.CertificateExpiryWarning = 24 * time.Hour
+			result, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(recorder.Events).ShouldNot(Receive())
+			Ω(result.RequeueAfter).Should(BeNumerically("~", time.Until(notAfter.Add(-24*time.Hour)), time.Minute))
+
+			Ω(cli.Delete(ctx, proxy)).ShouldNot(HaveOccurred())
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+			families, err = metrics.Registry.Gather()
+			Ω(err).ShouldNot(HaveOccurred())
+			for _, family := range families {
+				Ω(family.GetName()).ShouldNot(Equal("haproxy_operator_certificate_expiry_seconds"))
+			}
+		})
+		It("should replicate stick tables between the replicas", func() {
+			proxy.Spec.Replicas = 2
+			peers := &configv1alpha1.Peers{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mypeers",
+					Namespace: "foo",
+					Labels:    map[string]string{"label-test": "ok"},
+				},
+				Spec: configv1alpha1.PeersSpec{
+					Port:  10000,
+					Peers: []configv1alpha1.Peer{{Name: "remote", Address: "10.0.0.5", Port: 10000}},
+				},
+			}
+			backend.Spec.StickTable = &configv1alpha1.StickTable{Type: "ip", Size: 1000, Peers: "mypeers"}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, peers)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			Ω(proxy.Status.Phase).Should(Equal(proxyv1alpha1.InstancePhaseRunning))
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("peers mypeers\n" +
+				"  peer bar-foo-haproxy-0 bar-foo-haproxy-0.bar-foo-haproxy-peers.foo.svc:10000\n" +
+				"  peer bar-foo-haproxy-1 bar-foo-haproxy-1.bar-foo-haproxy-peers.foo.svc:10000\n" +
+				"  peer remote 10.0.0.5:10000\n"))
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("backend foo-back\n  stick-table type ip size 1000 peers mypeers\n"))
+
+			service := &corev1.Service{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-peers"}, service)).ShouldNot(HaveOccurred())
+			Ω(service.Spec.ClusterIP).Should(Equal(corev1.ClusterIPNone))
+			Ω(service.Spec.Ports).Should(HaveLen(1))
+			Ω(service.Spec.Ports[0].Port).Should(BeEquivalentTo(10000))
+
+			statefulSet := &appsv1.StatefulSet{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy"}, statefulSet)).ShouldNot(HaveOccurred())
+			Ω(statefulSet.Spec.ServiceName).Should(Equal("bar-foo-haproxy-peers"))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(peers), peers)).ShouldNot(HaveOccurred())
+			Ω(peers.Status.Phase).Should(Equal(configv1alpha1.StatusPhaseActive))
+		})
+		It("should create a new instance with peers when the configuration is validated", func() {
+			proxy.Spec.Replicas = 2
+			peers := &configv1alpha1.Peers{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mypeers",
+					Namespace: "foo",
+					Labels:    map[string]string{"label-test": "ok"},
+				},
+				Spec: configv1alpha1.PeersSpec{
+					Port: 10000,
+				},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, peers)...).Build()
+			validator := &validation.FakeValidator{}
+			r := instance.Reconciler{
+				Client:          cli,
+				Scheme:          scheme,
+				ConfigValidator: validator,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			Ω(proxy.Status.Phase).Should(Equal(proxyv1alpha1.InstancePhaseRunning))
+
+			Ω(string(validator.Files["haproxy.cfg"])).Should(ContainSubstring("peers mypeers\n" +
+				"  peer bar-foo-haproxy-0 127.0.0.1:10000\n" +
+				"  peer bar-foo-haproxy-1 127.0.0.1:10000\n"))
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("  peer bar-foo-haproxy-0 bar-foo-haproxy-0.bar-foo-haproxy-peers.foo.svc:10000\n"))
+
+			statefulSet := &appsv1.StatefulSet{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy"}, statefulSet)).ShouldNot(HaveOccurred())
+			Ω(statefulSet.Spec.ServiceName).Should(Equal("bar-foo-haproxy-peers"))
+			Ω(statefulSet.Spec.Template.Spec.InitContainers).Should(HaveLen(1))
+			Ω(statefulSet.Spec.Template.Spec.InitContainers[0].Name).Should(Equal("wait-for-peers"))
+			Ω(statefulSet.Spec.Template.Spec.InitContainers[0].Args[0]).Should(ContainSubstring("grep '\\.bar-foo-haproxy-peers\\.foo\\.svc$'"))
+		})
+		It("should resolve the passwords of userlists", func() {
+			userlist := &configv1alpha1.Userlist{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "admins",
+					Namespace: "foo",
+					Labels:    map[string]string{"label-test": "ok"},
+				},
+				Spec: configv1alpha1.UserlistSpec{
+					Groups: []string{"ops"},
+					Users: []configv1alpha1.User{
+						{
+							Name:     "alice",
+							Password: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"}, Key: "alice"},
+							Groups:   []string{"ops"},
+						},
+						{
+							Name:     "bob",
+							Password: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"}, Key: "bob"},
+							Insecure: true,
+						},
+					},
+				},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "foo"},
+				Data: map[string][]byte{
+					"alice": []byte("$6$salt$hash\n"),
+					"bob":   []byte("secret"),
+				},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, userlist, secret)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(proxy), proxy)).ShouldNot(HaveOccurred())
+			Ω(proxy.Status.Phase).Should(Equal(proxyv1alpha1.InstancePhaseRunning))
+
+			config := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, config)).ShouldNot(HaveOccurred())
+			Ω(string(config.Data["haproxy.cfg"])).Should(ContainSubstring("userlist admins\n" +
+				"  group ops\n" +
+				"  user alice password $6$salt$hash groups ops\n" +
+				"  user bob insecure-password secret\n"))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(userlist), userlist)).ShouldNot(HaveOccurred())
+			Ω(userlist.Status.Phase).Should(Equal(configv1alpha1.StatusPhaseActive))
+		})
+		It("should split the traffic of a frontend between backends", func() {
+			frontend.Spec.Mode = "http"
+			frontend.Spec.TrafficSplit = &configv1alpha1.TrafficSplit{
+				Backends: []configv1alpha1.WeightedBackend{{Name: "foo-back", Weight: 3}, {Name: "foo-back2", Weight: 1}},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, secret)).ShouldNot(HaveOccurred())
+			Ω(string(secret.Data["haproxy.cfg"])).Should(ContainSubstring("  use_backend %[var(txn.traffic_split),map_int(/usr/local/etc/haproxy/foo-front-traffic-split.map)]\n"))
+			lines := strings.Split(string(secret.Data["foo-front-traffic-split.map"]), "\n")
+			Ω(lines).Should(HaveLen(100))
+			Ω(lines[74]).Should(Equal("74 foo-back"))
+			Ω(lines[75]).Should(Equal("75 foo-back2"))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(frontend), frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Status.Phase).Should(Equal(configv1alpha1.StatusPhaseActive))
+			Ω(frontend.Status.TrafficSplit).Should(Equal([]configv1alpha1.WeightedBackend{{Name: "foo-back", Weight: 75}, {Name: "foo-back2", Weight: 25}}))
+		})
+		It("should not split the traffic to backends not selected by the instance", func() {
+			frontend.Spec.Mode = "http"
+			frontend.Spec.TrafficSplit = &configv1alpha1.TrafficSplit{
+				Backends: []configv1alpha1.WeightedBackend{{Name: "foo-back", Weight: 3}, {Name: "other", Weight: 1}},
+			}
+			proxy.Spec.Configuration.LabelSelector = *metav1.SetAsLabelSelector(map[string]string{"label-test": "ok"})
+			other := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: proxy.Namespace},
+			}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, other)...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: proxy.Name, Namespace: proxy.Namespace}})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(frontend), frontend)).ShouldNot(HaveOccurred())
+			Ω(frontend.Status.Phase).Should(Equal(configv1alpha1.StatusPhaseInternalError))
+			Ω(frontend.Status.Error).Should(Equal("traffic split backend foo/other is not selected by the instance"))
+		})
+		It("should render the config files without publishing them", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			r := instance.Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			data, err := r.Render(ctx, proxy)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(data["haproxy.cfg"])).Should(ContainSubstring("frontend foo-front\n"))
+			Ω(data).Should(HaveKey("cert_list.map"))
+
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy-config"}, &corev1.Secret{})).Should(Satisfy(errors.IsNotFound))
+		})
 	})
 })
+
+type fakeRuntimeClient struct {
+	address  string
+	commands map[string][]string
+	err      error
+}
+
+func (c *fakeRuntimeClient) Execute(_ context.Context, command string) (string, error) {
+	c.commands[c.address] = append(c.commands[c.address], command)
+	return "", c.err
+}
@@ -0,0 +1,30 @@
+package instance
+
+import (
+	"context"
+
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+)
+
+// Render generates the files of the configuration Secret of the instance without publishing them. The config
+// objects are listed with the client of the reconciler, which allows to render configurations offline using a fake
+// client.
+func (r *Reconciler) Render(ctx context.Context, instance *proxyv1alpha1.Instance) (map[string][]byte, error) {
+	listens, frontends, backends, resolvers, peers, userlists, err := r.listConfigObjects(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	data, _, err := r.generateConfigFiles(ctx, instance, listens, frontends, backends, resolvers, peers, userlists)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.ConfigValidator != nil {
+		if err := r.ConfigValidator.Validate(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,158 @@
+package instance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+
+	haproxy "github.com/haproxytech/client-native/v4/configuration/options"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/pkg/runtimeapi"
+	"github.com/six-group/haproxy-operator/pkg/utils"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// reloadChecksumFile is watched by the HAProxy pods if runtime sync is enabled. Its content only changes if the
+// configuration cannot be applied through the runtime API.
+const reloadChecksumFile = "reload.checksum"
+
+// planRuntimeSync returns the runtime API commands which move the running pods from the deployed to the new config
+// secret data, or false if the changes require a reload.
+func (r *Reconciler) planRuntimeSync(ctx context.Context, previous, current map[string][]byte, runtimeFiles map[string]string) ([]string, bool) {
+	if _, ok := previous[reloadChecksumFile]; !ok {
+		return nil, false
+	}
+
+	configFile := filepath.Base(haproxy.DefaultConfigurationFile)
+	skip := map[string]bool{configFile: true, reloadChecksumFile: true}
+	for file := range runtimeFiles {
+		skip[filepath.Base(file)] = true
+	}
+
+	for key := range current {
+		if !skip[key] && !bytes.Equal(previous[key], current[key]) {
+			return nil, false
+		}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok && !skip[key] {
+			return nil, false
+		}
+	}
+
+	previousFiles := map[string]string{}
+	for file := range runtimeFiles {
+		if data, ok := previous[filepath.Base(file)]; ok {
+			previousFiles[file] = string(data)
+		}
+	}
+
+	changes, err := runtimeapi.Diff(string(previous[configFile]), string(current[configFile]), previousFiles, runtimeFiles)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Unable to compare configurations, falling back to reload")
+		return nil, false
+	}
+
+	return changes.Commands, !changes.Reload
+}
+
+// syncRuntime applies the planned commands through the runtime API of all running pods once the config secret has
+// been written. If they cannot be applied, the reload checksum of the config secret is updated so that the pods reload
+// the configuration instead.
+func (r *Reconciler) syncRuntime(ctx context.Context, instance *proxyv1alpha1.Instance, configSecret *corev1.Secret, previous map[string][]byte, commands []string, checksum string) error {
+	logger := log.FromContext(ctx)
+
+	reloadChecksum := string(configSecret.Data[reloadChecksumFile])
+	if reloadChecksum != checksum {
+		if err := r.applyRuntimeCommands(ctx, instance, commands, checksum); err != nil {
+			logger.Error(err, "Unable to apply changes through the runtime API, falling back to reload")
+
+			configSecret.Data[reloadChecksumFile] = []byte(checksum)
+			if err := r.Update(ctx, configSecret); err != nil {
+				return err
+			}
+		} else if len(commands) > 0 {
+			logger.Info("Applied changes through the runtime API", "commands", len(commands))
+		}
+	}
+
+	if previousReloadChecksum, ok := previous[reloadChecksumFile]; ok && string(previousReloadChecksum) != string(configSecret.Data[reloadChecksumFile]) {
+		r.forgetPodConfigChecksums(ctx, instance)
+	}
+
+	return nil
+}
+
+// applyRuntimeCommands executes the commands on all running pods and records the checksum of the configuration on
+// the pods which applied them.
+func (r *Reconciler) applyRuntimeCommands(ctx context.Context, instance *proxyv1alpha1.Instance, commands []string, checksum string) error {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	pods, err := r.listPods(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	factory := r.RuntimeAPI
+	if factory == nil {
+		config, err := r.runtimeTLSConfig(ctx, instance)
+		if err != nil {
+			return err
+		}
+		factory = func(address string) runtimeapi.Client {
+			return runtimeapi.NewTLSClient(address, config)
+		}
+	}
+
+	port := fmt.Sprint(instance.Spec.Configuration.Global.RuntimeSync.GetPort())
+
+	var errs error
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+
+		if err := runtimeapi.Apply(ctx, factory(net.JoinHostPort(pod.Status.PodIP, port)), commands); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("pod %s: %w", pod.Name, err))
+			continue
+		}
+
+		if err := r.setPodConfigChecksum(ctx, pod, checksum); err != nil {
+			log.FromContext(ctx).Error(err, "Unable to annotate pod with the config checksum", "pod", pod.Name)
+		}
+	}
+
+	return errs
+}
+
+// forgetPodConfigChecksums removes the config checksum of all pods, which reload the configuration on their own.
+func (r *Reconciler) forgetPodConfigChecksums(ctx context.Context, instance *proxyv1alpha1.Instance) {
+	pods, err := r.listPods(ctx, instance)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Unable to list pods")
+		return
+	}
+
+	for i := range pods.Items {
+		if err := r.setPodConfigChecksum(ctx, &pods.Items[i], ""); err != nil {
+			log.FromContext(ctx).Error(err, "Unable to remove the config checksum of pod", "pod", pods.Items[i].Name)
+		}
+	}
+}
+
+func (r *Reconciler) listPods(ctx context.Context, instance *proxyv1alpha1.Instance) (*corev1.PodList, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(instance.Namespace), client.MatchingLabels(utils.GetAppSelectorLabels(instance))); err != nil {
+		return nil, err
+	}
+
+	return pods, nil
+}
@@ -0,0 +1,194 @@
+package instance
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"path/filepath"
+	"time"
+
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Keys of the runtime TLS Secret. The CA key is kept to tell apart a complete Secret from a partially written one.
+const (
+	runtimeTLSCACert     = "ca.crt"
+	runtimeTLSCAKey      = "ca.key"
+	runtimeTLSServerPEM  = "server.pem"
+	runtimeTLSClientCert = "client.crt"
+	runtimeTLSClientKey  = "client.key"
+)
+
+// runtimeAPIServerName is the name in the server certificate of the runtime API, which is reached by pod IP.
+const runtimeAPIServerName = "runtime-api"
+
+const runtimeTLSValidity = 10 * 365 * 24 * time.Hour
+
+// reconcileRuntimeTLS creates the CA of the runtime API of the instance with a server certificate for HAProxy and a
+// client certificate for the operator, and returns the Secret holding them. Existing certificates are kept.
+func (r *Reconciler) reconcileRuntimeTLS(ctx context.Context, instance *proxyv1alpha1.Instance) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.GetRuntimeTLSSecretName(instance),
+			Namespace: instance.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if err := controllerutil.SetOwnerReference(instance, secret, r.Scheme); err != nil {
+			return err
+		}
+
+		for _, key := range []string{runtimeTLSCACert, runtimeTLSCAKey, runtimeTLSServerPEM, runtimeTLSClientCert, runtimeTLSClientKey} {
+			if len(secret.Data[key]) == 0 {
+				data, err := generateRuntimeTLS()
+				if err != nil {
+					return err
+				}
+				secret.Data = data
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return secret, err
+}
+
+// runtimeTLSFiles returns the files of the configuration Secret which secure the runtime API.
+func runtimeTLSFiles(secret *corev1.Secret) map[string][]byte {
+	return map[string][]byte{
+		filepath.Base(proxyv1alpha1.RuntimeAPICertificateFile): secret.Data[runtimeTLSServerPEM],
+		filepath.Base(proxyv1alpha1.RuntimeAPICAFile):          secret.Data[runtimeTLSCACert],
+	}
+}
+
+// runtimeTLSConfig returns the TLS configuration of the operator to connect to the runtime API of the instance.
+func (r *Reconciler) runtimeTLSConfig(ctx context.Context, instance *proxyv1alpha1.Instance) (*tls.Config, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: utils.GetRuntimeTLSSecretName(instance)}, secret); err != nil {
+		return nil, err
+	}
+
+	certificate, err := tls.X509KeyPair(secret.Data[runtimeTLSClientCert], secret.Data[runtimeTLSClientKey])
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(secret.Data[runtimeTLSCACert])
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      pool,
+		ServerName:   runtimeAPIServerName,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func generateRuntimeTLS() (map[string][]byte, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	ca := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "haproxy-operator-runtime-api-ca"},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := signCertificate(ca, ca, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	ca, err = x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	serverCert, serverKey, err := issueCertificate(ca, caKey, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: runtimeAPIServerName},
+		DNSNames:    []string{runtimeAPIServerName},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	clientCert, clientKey, err := issueCertificate(ca, caKey, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "haproxy-operator"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	caKeyPEM, err := encodeKey(caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		runtimeTLSCACert:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		runtimeTLSCAKey:      caKeyPEM,
+		runtimeTLSServerPEM:  append(serverCert, serverKey...),
+		runtimeTLSClientCert: clientCert,
+		runtimeTLSClientKey:  clientKey,
+	}, nil
+}
+
+// issueCertificate signs the template with the CA and returns the PEM encoded certificate and key.
+func issueCertificate(ca *x509.Certificate, caKey *ecdsa.PrivateKey, template *x509.Certificate) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := signCertificate(template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+func signCertificate(template, parent *x509.Certificate, public *ecdsa.PublicKey, signer *ecdsa.PrivateKey) ([]byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(runtimeTLSValidity)
+
+	return x509.CreateCertificate(rand.Reader, template, parent, public, signer)
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
@@ -155,3 +155,58 @@ func (r *Reconciler) reconcileServiceEndpoints(ctx context.Context, instance *pr
 
 	return nil
 }
+
+// reconcilePeersService creates the headless service which provides the DNS names of the replicas in the peers
+// sections.
+func (r *Reconciler) reconcilePeersService(ctx context.Context, instance *proxyv1alpha1.Instance, peers *configv1alpha1.PeersList) error {
+	logger := log.FromContext(ctx)
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.GetPeersServiceName(instance),
+			Namespace: instance.Namespace,
+		},
+	}
+
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		if err := controllerutil.SetOwnerReference(instance, service, r.Scheme); err != nil {
+			return err
+		}
+
+		service.Labels = utils.GetAppSelectorLabels(instance)
+		service.Spec.Selector = utils.GetAppSelectorLabels(instance)
+		service.Spec.ClusterIP = corev1.ClusterIPNone
+		// the replicas must resolve each other before they are ready
+		service.Spec.PublishNotReadyAddresses = true
+
+		ports := map[int64]bool{}
+		service.Spec.Ports = []corev1.ServicePort{}
+		for _, peer := range peers.Items {
+			if ports[peer.Spec.Port] {
+				continue
+			}
+			ports[peer.Spec.Port] = true
+
+			service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+				Name:       fmt.Sprintf("peers-%d", peer.Spec.Port),
+				Port:       int32(peer.Spec.Port),
+				TargetPort: intstr.FromInt(int(peer.Spec.Port)),
+				Protocol:   corev1.ProtocolTCP,
+			})
+		}
+
+		sort.Slice(service.Spec.Ports, func(i, j int) bool {
+			return service.Spec.Ports[i].Name < service.Spec.Ports[j].Name
+		})
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		logger.Info(fmt.Sprintf("Object %s", result), "service", service.Name)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"path/filepath"
 	"sort"
 	"text/template"
 
@@ -14,6 +15,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -45,13 +47,48 @@ fi
 
 `
 
+// peersInitContainerScript waits until the DNS names of the replicas in the peers section resolve, as HAProxy fails to
+// start with unknown peers. The hosts are read from the configuration to not roll the pods on scaling. The wait is
+// bounded as a replica which cannot be scheduled never gets a DNS name.
+const peersInitContainerScript = `
+for host in $(awk '$1 == "peer" { sub(/:[0-9]+$/, "", $3); print $3 }' {{.File}} | grep '\.{{.Service}}\.{{.Namespace}}\.svc$')
+do
+  i=0
+  until getent hosts "$host" > /dev/null
+  do
+    i=$((i+1))
+    if [ "$i" -gt "60" ]
+      then echo "timeout waiting for peer $host, continuing"
+      break
+    fi
+    echo "waiting for peer $host to be resolvable..."
+    sleep 1
+  done
+done
+`
+
+type peersInitScriptData struct {
+	File      string
+	Service   string
+	Namespace string
+}
+
+// configChecksumAnnotation is set on the pod template if reload is disabled to roll the pods on configuration changes.
+const configChecksumAnnotation = "proxy.haproxy.com/config-checksum"
+
+// runtimeSyncPodLabel marks the pods exposing the runtime API, which lets a NetworkPolicy restrict access to it.
+const runtimeSyncPodLabel = "proxy.haproxy.com/runtime-sync"
+
 type initScriptData struct {
 	Host string
 	IP   string
 	File string
 }
 
-func (r *Reconciler) reconcileStatefulSet(ctx context.Context, instance *proxyv1alpha1.Instance) error {
+// reconcileStatefulSet creates or updates the StatefulSet of the instance. The checksum of the configuration published
+// by reconcileConfig rolls the pods on changes if reload is disabled. A requeue is returned while the StatefulSet is
+// recreated to change an immutable field.
+func (r *Reconciler) reconcileStatefulSet(ctx context.Context, instance *proxyv1alpha1.Instance, peers bool, checksum string) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
 	statefulset := &appsv1.StatefulSet{
@@ -61,11 +98,37 @@ func (r *Reconciler) reconcileStatefulSet(ctx context.Context, instance *proxyv1
 		},
 	}
 
+	if err := r.Get(ctx, client.ObjectKeyFromObject(statefulset), statefulset); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, err
+	}
+	// the orphaned pods are adopted by the new stateful set once the old one is gone
+	if statefulset.DeletionTimestamp != nil {
+		logger.Info("Waiting for stateful set to be deleted", "statefulset", statefulset.Name)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	// FIXME OSCP-4269 workaround to change podManagementPolicy
-	_ = r.Get(ctx, client.ObjectKeyFromObject(statefulset), statefulset)
-	if statefulset.Spec.PodManagementPolicy == appsv1.OrderedReadyPodManagement {
-		_ = r.Delete(ctx, statefulset)
+	if statefulset.ResourceVersion != "" && statefulset.Spec.PodManagementPolicy == appsv1.OrderedReadyPodManagement {
+		if err := r.Delete(ctx, statefulset, client.PropagationPolicy(metav1.DeletePropagationOrphan)); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
 		logger.Info("Delete stateful set to change podManagementPolicy")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// the pods need a DNS name in the headless peers service to replicate stick tables
+	var serviceName string
+	if peers {
+		serviceName = utils.GetPeersServiceName(instance)
+	}
+
+	// serviceName is immutable
+	if statefulset.ResourceVersion != "" && statefulset.Spec.ServiceName != serviceName {
+		if err := r.Delete(ctx, statefulset, client.PropagationPolicy(metav1.DeletePropagationOrphan)); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("Delete stateful set to change serviceName")
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// cannot avoid update triggered at startup
@@ -81,6 +144,7 @@ func (r *Reconciler) reconcileStatefulSet(ctx context.Context, instance *proxyv1
 				MatchLabels: utils.GetAppSelectorLabels(instance),
 			},
 			PodManagementPolicy: appsv1.ParallelPodManagement,
+			ServiceName:         serviceName,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      utils.GetPodLabels(instance),
@@ -194,6 +258,23 @@ func (r *Reconciler) reconcileStatefulSet(ctx context.Context, instance *proxyv1
 			}
 		}
 
+		if instance.Spec.UpdateStrategy != nil {
+			statefulset.Spec.UpdateStrategy = *instance.Spec.UpdateStrategy
+		}
+
+		if !instance.Spec.Configuration.Global.Reload {
+			statefulset.Spec.Template.Annotations[configChecksumAnnotation] = checksum
+		}
+
+		if instance.Spec.Configuration.Global.RuntimeSyncEnabled() {
+			statefulset.Spec.Template.Labels[runtimeSyncPodLabel] = "true"
+			for idx, env := range statefulset.Spec.Template.Spec.Containers[0].Env {
+				if env.Name == "WATCH_PATH" {
+					statefulset.Spec.Template.Spec.Containers[0].Env[idx].Value = filepath.Join("/usr/local/etc/haproxy", reloadChecksumFile)
+				}
+			}
+		}
+
 		if pointer.BoolDeref(instance.Spec.AllowPrivilegedPorts, false) {
 			statefulset.Spec.Template.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{
 				Privileged: pointer.Bool(true),
@@ -251,16 +332,46 @@ func (r *Reconciler) reconcileStatefulSet(ctx context.Context, instance *proxyv1
 			})
 		}
 
+		if peers {
+			tmpl, err := template.New("peersInitScript").Parse(peersInitContainerScript)
+			if err != nil {
+				return err
+			}
+			var script bytes.Buffer
+			data := peersInitScriptData{
+				File:      "/usr/local/etc/haproxy/haproxy.cfg",
+				Service:   utils.GetPeersServiceName(instance),
+				Namespace: instance.Namespace,
+			}
+			if err := tmpl.Execute(&script, data); err != nil {
+				return err
+			}
+
+			statefulset.Spec.Template.Spec.InitContainers = append(statefulset.Spec.Template.Spec.InitContainers, corev1.Container{
+				Name:            "wait-for-peers",
+				Image:           statefulset.Spec.Template.Spec.Containers[0].Image,
+				ImagePullPolicy: instance.Spec.ImagePullPolicy,
+				Command:         []string{"/bin/sh", "-c"},
+				Args:            []string{script.String()},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      "haproxy-config",
+						MountPath: "/usr/local/etc/haproxy",
+					},
+				},
+			})
+		}
+
 		return nil
 	})
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 	if result != controllerutil.OperationResultNone {
 		logger.Info(fmt.Sprintf("Object %s", result), "statefulset", statefulset.Name)
 	}
 
-	return nil
+	return ctrl.Result{}, nil
 }
 
 func hasLocalLoggingTarget(instance *proxyv1alpha1.Instance) bool {
@@ -8,11 +8,13 @@ import (
 	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
 	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -73,7 +75,7 @@ var _ = Describe("Reconcile", Label("controller"), func() {
 				Client: cli,
 				Scheme: scheme,
 			}
-			err := r.reconcileStatefulSet(ctx, proxy)
+			_, err := r.reconcileStatefulSet(ctx, proxy, false, "")
 			Ω(err).ShouldNot(HaveOccurred())
 
 			statefulSet := &appsv1.StatefulSet{}
@@ -88,5 +90,107 @@ var _ = Describe("Reconcile", Label("controller"), func() {
 				"    sleep 5\n  done\n\n  echo 'IP 10.158.182.27 assignment verified, waiting 5 seconds before continuing...'\n\n" +
 				"  sleep 5\n\n  echo -n \"BIND_ADDRESS=10.158.182.27\" > /var/lib/haproxy/run/env\n  cat /var/lib/haproxy/run/env\n  exit 0\nfi\n\nexit 1\n"))
 		})
+		It("should roll pods on config changes if reload is disabled", func() {
+			proxy.Spec.UpdateStrategy = &appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			r := Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			checksum := configChecksum(map[string][]byte{"haproxy.cfg": []byte("global\n")})
+			Ω(r.reconcileStatefulSet(ctx, proxy, false, checksum)).Should(BeZero())
+
+			statefulSet := &appsv1.StatefulSet{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy"}, statefulSet)).ShouldNot(HaveOccurred())
+			Ω(statefulSet.Spec.UpdateStrategy.Type).Should(Equal(appsv1.OnDeleteStatefulSetStrategyType))
+			Ω(statefulSet.Spec.Template.Annotations["proxy.haproxy.com/config-checksum"]).Should(Equal(checksum))
+
+			updated := configChecksum(map[string][]byte{"haproxy.cfg": []byte("global\n  maxconn 100\n")})
+			Ω(r.reconcileStatefulSet(ctx, proxy, false, updated)).Should(BeZero())
+
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy"}, statefulSet)).ShouldNot(HaveOccurred())
+			Ω(statefulSet.Spec.Template.Annotations["proxy.haproxy.com/config-checksum"]).Should(Equal(updated))
+			Ω(updated).ShouldNot(Equal(checksum))
+		})
+		It("should not roll pods on config changes if reload is enabled", func() {
+			proxy.Spec.Configuration.Global.Reload = true
+
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			r := Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			Ω(r.reconcileStatefulSet(ctx, proxy, false, "")).Should(BeZero())
+
+			statefulSet := &appsv1.StatefulSet{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy"}, statefulSet)).ShouldNot(HaveOccurred())
+			Ω(statefulSet.Spec.Template.Annotations).ShouldNot(HaveKey("proxy.haproxy.com/config-checksum"))
+		})
+		It("should recreate the statefulset to set the peers service", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+			r := Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			Ω(r.reconcileStatefulSet(ctx, proxy, false, "")).Should(BeZero())
+
+			statefulSet := &appsv1.StatefulSet{}
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy"}, statefulSet)).ShouldNot(HaveOccurred())
+			Ω(statefulSet.Spec.ServiceName).Should(BeEmpty())
+
+			Ω(r.reconcileStatefulSet(ctx, proxy, true, "")).Should(Equal(ctrl.Result{Requeue: true}))
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy"}, statefulSet)).Should(WithTransform(errors.IsNotFound, BeTrue()))
+
+			Ω(r.reconcileStatefulSet(ctx, proxy, true, "")).Should(BeZero())
+			Ω(cli.Get(ctx, client.ObjectKey{Namespace: proxy.Namespace, Name: "bar-foo-haproxy"}, statefulSet)).ShouldNot(HaveOccurred())
+			Ω(statefulSet.Spec.ServiceName).Should(Equal("bar-foo-haproxy-peers"))
+		})
+		It("should orphan the pods when recreating the statefulset", func() {
+			cli := &deleteRecorder{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()}
+			r := Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			Ω(r.reconcileStatefulSet(ctx, proxy, false, "")).Should(BeZero())
+			Ω(cli.deletes).Should(BeEmpty())
+
+			Ω(r.reconcileStatefulSet(ctx, proxy, true, "")).Should(Equal(ctrl.Result{Requeue: true}))
+			Ω(cli.deletes).Should(HaveLen(1))
+			Ω(cli.deletes[0].PropagationPolicy).Should(HaveValue(Equal(metav1.DeletePropagationOrphan)))
+		})
+		It("should wait until the statefulset is deleted", func() {
+			now := metav1.Now()
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "bar-foo-haproxy",
+					Namespace:         proxy.Namespace,
+					DeletionTimestamp: &now,
+					Finalizers:        []string{metav1.FinalizerOrphanDependents},
+				},
+			}
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(initObjs, statefulSet)...).Build()
+			r := Reconciler{
+				Client: cli,
+				Scheme: scheme,
+			}
+			Ω(r.reconcileStatefulSet(ctx, proxy, true, "")).Should(Equal(ctrl.Result{Requeue: true}))
+
+			Ω(cli.Get(ctx, client.ObjectKeyFromObject(statefulSet), statefulSet)).ShouldNot(HaveOccurred())
+			Ω(statefulSet.Spec.ServiceName).Should(BeEmpty())
+		})
 	})
 })
+
+// deleteRecorder records the options of the deletes.
+type deleteRecorder struct {
+	client.Client
+	deletes []client.DeleteOptions
+}
+
+func (d *deleteRecorder) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	options := client.DeleteOptions{}
+	options.ApplyOptions(opts)
+	d.deletes = append(d.deletes, options)
+	return d.Client.Delete(ctx, obj, opts...)
+}
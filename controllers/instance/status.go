@@ -0,0 +1,158 @@
+package instance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	haproxy "github.com/haproxytech/client-native/v4/configuration/options"
+	"github.com/pmezard/go-difflib/difflib"
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxEventMessageLength is the maximum length of the message of a config change event, longer diffs are truncated.
+const maxEventMessageLength = 1024
+
+// appliedGenerations returns the generations of the config objects in the published configuration.
+func appliedGenerations(listens *configv1alpha1.ListenList, frontends *configv1alpha1.FrontendList, backends *configv1alpha1.BackendList, resolvers *configv1alpha1.ResolverList, peers *configv1alpha1.PeersList, userlists *configv1alpha1.UserlistList) []proxyv1alpha1.AppliedGeneration {
+	var generations []proxyv1alpha1.AppliedGeneration
+	for i := range listens.Items {
+		generations = append(generations, appliedGeneration("Listen", &listens.Items[i]))
+	}
+	for i := range frontends.Items {
+		generations = append(generations, appliedGeneration("Frontend", &frontends.Items[i]))
+	}
+	for i := range backends.Items {
+		generations = append(generations, appliedGeneration("Backend", &backends.Items[i]))
+	}
+	for i := range resolvers.Items {
+		generations = append(generations, appliedGeneration("Resolver", &resolvers.Items[i]))
+	}
+	for i := range peers.Items {
+		generations = append(generations, appliedGeneration("Peers", &peers.Items[i]))
+	}
+	for i := range userlists.Items {
+		generations = append(generations, appliedGeneration("Userlist", &userlists.Items[i]))
+	}
+
+	return generations
+}
+
+func appliedGeneration(kind string, object client.Object) proxyv1alpha1.AppliedGeneration {
+	return proxyv1alpha1.AppliedGeneration{
+		Kind:       kind,
+		Name:       object.GetName(),
+		Generation: object.GetGeneration(),
+	}
+}
+
+// configDiff returns a unified diff of the changed files of the config secret data, starting with haproxy.cfg.
+func configDiff(previous, current map[string][]byte) string {
+	configFile := filepath.Base(haproxy.DefaultConfigurationFile)
+
+	files := map[string]bool{}
+	for file := range previous {
+		files[file] = true
+	}
+	for file := range current {
+		files[file] = true
+	}
+	delete(files, reloadChecksumFile)
+
+	var names []string
+	for file := range files {
+		if !bytes.Equal(previous[file], current[file]) {
+			names = append(names, file)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == configFile) != (names[j] == configFile) {
+			return names[i] == configFile
+		}
+		return names[i] < names[j]
+	})
+
+	var buf strings.Builder
+	for _, file := range names {
+		// OCSP responses are DER encoded
+		if !utf8.Valid(previous[file]) || !utf8.Valid(current[file]) {
+			fmt.Fprintf(&buf, "Binary files a/%s and b/%s differ\n", file, file)
+			continue
+		}
+
+		diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(previous[file])),
+			B:        difflib.SplitLines(string(current[file])),
+			FromFile: "a/" + file,
+			ToFile:   "b/" + file,
+			Context:  1,
+		})
+		buf.WriteString(diff)
+	}
+
+	return buf.String()
+}
+
+// configChangeMessage returns the message of the event published for a config change.
+func configChangeMessage(previousHash, hash, diff string) string {
+	message := fmt.Sprintf("Configuration changed from %s to %s\n%s", previousHash, hash, diff)
+	if len(message) > maxEventMessageLength {
+		message = message[:maxEventMessageLength-4] + "\n..."
+	}
+
+	return message
+}
+
+// podConfigStatus returns the configuration hash loaded by the pods of the instance. The hash is read from the
+// checksum annotation of the pods, which is set on the pod template if reload is disabled and by the operator after
+// it applied changes through the runtime API. Pods without the annotation reload the configuration on their own and
+// are omitted, as it is unknown which configuration they have loaded.
+func (r *Reconciler) podConfigStatus(ctx context.Context, instance *proxyv1alpha1.Instance) ([]proxyv1alpha1.PodConfigStatus, error) {
+	pods, err := r.listPods(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool { return pods.Items[i].Name < pods.Items[j].Name })
+
+	var statuses []proxyv1alpha1.PodConfigStatus
+	for _, pod := range pods.Items {
+		hash := pod.Annotations[configChecksumAnnotation]
+		if hash == "" {
+			continue
+		}
+		statuses = append(statuses, proxyv1alpha1.PodConfigStatus{
+			Name:       pod.Name,
+			ConfigHash: hash,
+			UpToDate:   hash == instance.Status.ConfigHash,
+		})
+	}
+
+	return statuses, nil
+}
+
+// setPodConfigChecksum sets the checksum annotation of a pod to the configuration it has loaded, or removes it if
+// the loaded configuration is unknown.
+func (r *Reconciler) setPodConfigChecksum(ctx context.Context, pod *corev1.Pod, checksum string) error {
+	if pod.Annotations[configChecksumAnnotation] == checksum {
+		return nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if checksum == "" {
+		delete(pod.Annotations, configChecksumAnnotation)
+	} else {
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[configChecksumAnnotation] = checksum
+	}
+
+	return r.Patch(ctx, pod, patch)
+}
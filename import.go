@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/six-group/haproxy-operator/pkg/importer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// runImport implements the import subcommand, which converts an haproxy.cfg into the YAML manifests of an Instance
+// and its config objects and reports the statements which could not be converted.
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	name := flags.String("name", "haproxy", "Name of the generated Instance.")
+	namespace := flags.String("namespace", "default", "Namespace of the generated objects.")
+	output := flags.String("o", "", "File to write the manifests to, instead of stdout.")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s import [flags] haproxy.cfg\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	config, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	result, err := importer.Import(string(config), importer.Options{Name: *name, Namespace: *namespace})
+	if err != nil {
+		return err
+	}
+
+	manifests, err := marshalManifests(result.Objects)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(manifests)
+	} else {
+		err = os.WriteFile(*output, manifests, 0o600)
+	}
+	if err != nil {
+		return err
+	}
+
+	writeLossyReport(os.Stderr, result.Lossy)
+
+	return nil
+}
+
+func marshalManifests(objects []client.Object) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+
+		// drop the fields managed by the API server and the operator
+		var manifest map[string]interface{}
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return nil, err
+		}
+		delete(manifest, "status")
+		if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "creationTimestamp")
+		}
+
+		data, err = yaml.Marshal(manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeLossyReport(w io.Writer, lossy []importer.LossySpot) {
+	if len(lossy) == 0 {
+		fmt.Fprintln(w, "All statements were imported.")
+		return
+	}
+
+	fmt.Fprintf(w, "%d statements could not be imported and must be migrated manually:\n", len(lossy))
+	for _, spot := range lossy {
+		fmt.Fprintf(w, "  %s\n", spot)
+	}
+}
@@ -2,15 +2,22 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	routev1 "github.com/openshift/api/route/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
 	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
 	"github.com/six-group/haproxy-operator/controllers/config"
+	"github.com/six-group/haproxy-operator/controllers/gateway"
+	"github.com/six-group/haproxy-operator/controllers/ingress"
 	"github.com/six-group/haproxy-operator/controllers/instance"
+	"github.com/six-group/haproxy-operator/pkg/utils"
+	"github.com/six-group/haproxy-operator/pkg/validation"
+	"github.com/six-group/haproxy-operator/webhooks"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -20,28 +27,55 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	crzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
-const envLeaderElect = "LEADER_ELECT"
+const (
+	envLeaderElect    = "LEADER_ELECT"
+	envEnableWebhooks = "ENABLE_WEBHOOKS"
+	envEnableIngress  = "ENABLE_INGRESS_CONTROLLER"
+	envEnableGateway  = "ENABLE_GATEWAY_CONTROLLER"
+)
 
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+// commands are the subcommands which run instead of the operator.
+var commands = map[string]func(args []string) error{
+	"import": runImport,
+	"render": runRender,
+}
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(configv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(proxyv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1alpha2.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	var metricsAddr string
 	var probeAddr string
+	var certificateExpiryWarning time.Duration
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.DurationVar(&certificateExpiryWarning, "certificate-expiry-warning", instance.DefaultCertificateExpiryWarning, "The time before the expiry of a certificate from which on warning events are published.")
 	flag.Parse()
 
 	setupLogging()
@@ -78,9 +112,17 @@ func main() {
 		}
 	}
 
+	var validator validation.ConfigValidator
+	if binary := utils.GetHAProxyBinary(); binary != "" {
+		validator = validation.NewHAProxyValidator(binary)
+	}
+
 	if err = (&instance.Reconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		ConfigValidator:          validator,
+		Recorder:                 mgr.GetEventRecorderFor("haproxy-operator"),
+		CertificateExpiryWarning: certificateExpiryWarning,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Instance")
 		os.Exit(1)
@@ -117,6 +159,70 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Resolver")
 		os.Exit(1)
 	}
+	if err = (&config.Reconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		Object: &configv1alpha1.Peers{},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Peers")
+		os.Exit(1)
+	}
+	if err = (&config.Reconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		Object: &configv1alpha1.Userlist{},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Userlist")
+		os.Exit(1)
+	}
+	if strings.EqualFold(os.Getenv(envEnableIngress), "true") {
+		if err = (&ingress.Reconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Ingress")
+			os.Exit(1)
+		}
+	}
+	if strings.EqualFold(os.Getenv(envEnableGateway), "true") {
+		found, err := utils.VerifyAPI(gatewayv1beta1.GroupName, gatewayv1beta1.GroupVersion.Version)
+		if err != nil {
+			setupLog.Error(err, "unable to verify Gateway API")
+			os.Exit(1)
+		}
+		if !found {
+			setupLog.Info("Gateway API not installed, gateway controller disabled")
+		} else {
+			if err = (&gateway.GatewayClassReconciler{
+				Client: mgr.GetClient(),
+				Scheme: mgr.GetScheme(),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "GatewayClass")
+				os.Exit(1)
+			}
+			if err = (&gateway.Reconciler{
+				Client: mgr.GetClient(),
+				Scheme: mgr.GetScheme(),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Gateway")
+				os.Exit(1)
+			}
+		}
+	}
+	if strings.EqualFold(os.Getenv(envEnableWebhooks), "true") {
+		if err = (&webhooks.ConfigWebhook{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Config")
+			os.Exit(1)
+		}
+		if err = (&webhooks.InstanceWebhook{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Instance")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,163 @@
+// Package importer converts an existing HAProxy configuration into the custom resources of the operator.
+package importer
+
+import (
+	"fmt"
+
+	parser "github.com/haproxytech/config-parser/v4"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InstanceLabel is set on all imported config objects and used by the imported Instance to select them.
+const InstanceLabel = "app.kubernetes.io/instance"
+
+// Options of an import.
+type Options struct {
+	// Name of the generated Instance.
+	Name string
+	// Namespace of the generated objects.
+	Namespace string
+}
+
+// Result of an import.
+type Result struct {
+	// Objects contains the Instance followed by the config objects selected by it.
+	Objects []client.Object
+	// Lossy lists the statements of the configuration which are not represented by the objects.
+	Lossy []LossySpot
+}
+
+// LossySpot is a statement of the imported configuration which is not represented by the generated objects.
+type LossySpot struct {
+	// Section the statement belongs to, e.g. 'backend app'.
+	Section string
+	// Statement as written in the configuration.
+	Statement string
+}
+
+func (l LossySpot) String() string {
+	return fmt.Sprintf("%s: %s", l.Section, l.Statement)
+}
+
+type renderer interface {
+	AddToParser(p parser.Parser) error
+}
+
+// Import converts the configuration into an Instance with its global and defaults sections and into Frontend,
+// Backend, Listen and Resolver objects. Statements which cannot be modelled are kept in the additional parameters
+// of the global and defaults sections if possible. All statements missing in the configuration rendered from the
+// objects are reported as lossy.
+func Import(config string, opts Options) (*Result, error) {
+	labels := map[string]string{InstanceLabel: opts.Name}
+
+	instance := &proxyv1alpha1.Instance{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: proxyv1alpha1.GroupVersion.String(),
+			Kind:       "Instance",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.Name,
+			Namespace: opts.Namespace,
+		},
+		Spec: proxyv1alpha1.InstanceSpec{
+			Replicas: 1,
+			Configuration: proxyv1alpha1.Configuration{
+				Defaults: proxyv1alpha1.DefaultsConfiguration{
+					Mode:     "tcp",
+					Timeouts: map[string]metav1.Duration{},
+				},
+				LabelSelector: metav1.LabelSelector{MatchLabels: labels},
+			},
+		},
+	}
+
+	result := &Result{Objects: []client.Object{instance}}
+	// the imported sections are verified against the configuration rendered from their objects
+	var imported []*section
+	objects := map[*section]renderer{}
+	defaultsImported := false
+
+	for _, s := range splitSections(config) {
+		var object client.Object
+		var err error
+
+		switch s.kind {
+		case parser.Global:
+			err = importGlobal(&instance.Spec.Configuration.Global, s)
+		case parser.Defaults:
+			if defaultsImported {
+				result.lossy(s, s.statements...)
+				continue
+			}
+			defaultsImported = true
+			err = importDefaults(&instance.Spec.Configuration.Defaults, s)
+		case parser.Frontends:
+			object, err = importFrontend(s, instance.Spec.Configuration.Defaults.Mode)
+		case parser.Backends:
+			object, err = importBackend(s, instance.Spec.Configuration.Defaults.Mode)
+		case parser.Listen:
+			object, err = importListen(s, instance.Spec.Configuration.Defaults.Mode)
+		case parser.Resolvers:
+			object, err = importResolver(s)
+		default:
+			result.lossy(s, s.statements...)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s, err)
+		}
+
+		imported = append(imported, s)
+		if object == nil {
+			objects[s] = instance
+			continue
+		}
+
+		object.SetNamespace(opts.Namespace)
+		object.SetLabels(labels)
+		result.Objects = append(result.Objects, object)
+		objects[s] = object.(renderer)
+	}
+
+	for _, s := range imported {
+		statements, err := render(objects[s])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s, err)
+		}
+
+		rendered := statements.of(s.renderedKinds()...)
+		for _, statement := range s.statements {
+			if !covered(statement, rendered) {
+				result.lossy(s, statement)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Result) lossy(s *section, statements ...string) {
+	for _, statement := range statements {
+		r.Lossy = append(r.Lossy, LossySpot{Section: s.String(), Statement: statement})
+	}
+}
+
+// render returns the statements of the configuration rendered from the object grouped by section kind.
+func render(object renderer) (renderedStatements, error) {
+	p, err := parser.New()
+	if err != nil {
+		return nil, err
+	}
+	if err := object.AddToParser(p); err != nil {
+		return nil, err
+	}
+
+	statements := renderedStatements{}
+	for _, s := range splitSections(p.String()) {
+		statements[s.kind] = append(statements[s.kind], s.statements...)
+	}
+
+	return statements, nil
+}
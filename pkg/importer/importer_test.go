@@ -0,0 +1,279 @@
+package importer_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/pkg/importer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Import", Label("importer"), func() {
+	var opts importer.Options
+
+	BeforeEach(func() {
+		opts = importer.Options{Name: "edge", Namespace: "proxy"}
+	})
+
+	importObject := func(config string) client.Object {
+		result, err := importer.Import(config, opts)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result.Lossy).Should(BeEmpty())
+		Ω(result.Objects).Should(HaveLen(2))
+		Ω(result.Objects[1].GetNamespace()).Should(Equal("proxy"))
+		Ω(result.Objects[1].GetLabels()).Should(Equal(map[string]string{importer.InstanceLabel: "edge"}))
+
+		return result.Objects[1]
+	}
+
+	It("should select the imported objects", func() {
+		result, err := importer.Import("backend app\n  server a1 10.0.0.1:80\n", opts)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		instance := result.Objects[0].(*proxyv1alpha1.Instance)
+		Ω(instance.Name).Should(Equal("edge"))
+		Ω(instance.Namespace).Should(Equal("proxy"))
+		Ω(instance.Spec.Configuration.LabelSelector.MatchLabels).Should(Equal(map[string]string{importer.InstanceLabel: "edge"}))
+	})
+
+	DescribeTable("global and defaults",
+		func(config string, global proxyv1alpha1.GlobalConfiguration, defaults proxyv1alpha1.DefaultsConfiguration) {
+			result, err := importer.Import(config, opts)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result.Lossy).Should(BeEmpty())
+			Ω(result.Objects).Should(HaveLen(1))
+
+			instance := result.Objects[0].(*proxyv1alpha1.Instance)
+			Ω(instance.Spec.Configuration.Global).Should(Equal(global))
+			Ω(instance.Spec.Configuration.Defaults).Should(Equal(defaults))
+		},
+		Entry("typed global parameters",
+			"global\n  maxconn 2000\n  nbthread 4\n  hard-stop-after 30s\n",
+			proxyv1alpha1.GlobalConfiguration{
+				Maxconn:       pointer.Int64(2000),
+				Nbthread:      pointer.Int64(4),
+				HardStopAfter: durationPtr(30 * time.Second),
+			},
+			proxyv1alpha1.DefaultsConfiguration{Mode: "tcp", Timeouts: map[string]metav1.Duration{}},
+		),
+		Entry("global logging",
+			"global\n  log 127.0.0.1:514 local0 info\n  log-send-hostname edge\n",
+			proxyv1alpha1.GlobalConfiguration{
+				Logging: &proxyv1alpha1.GlobalLoggingConfiguration{
+					Enabled:      true,
+					Address:      "127.0.0.1:514",
+					Facility:     "local0",
+					Level:        "info",
+					SendHostname: pointer.Bool(true),
+					Hostname:     pointer.String("edge"),
+				},
+			},
+			proxyv1alpha1.DefaultsConfiguration{Mode: "tcp", Timeouts: map[string]metav1.Duration{}},
+		),
+		Entry("additional global parameters",
+			"global\n  maxconn 2000\n  ulimit-n 65536\n",
+			proxyv1alpha1.GlobalConfiguration{
+				Maxconn:              pointer.Int64(2000),
+				AdditionalParameters: "ulimit-n 65536",
+			},
+			proxyv1alpha1.DefaultsConfiguration{Mode: "tcp", Timeouts: map[string]metav1.Duration{}},
+		),
+		Entry("defaults",
+			"defaults\n  mode http\n  timeout connect 5s\n  timeout client 30000\n  log global\n  option httplog\n  option dontlognull\n",
+			proxyv1alpha1.GlobalConfiguration{},
+			proxyv1alpha1.DefaultsConfiguration{
+				Mode: "http",
+				Timeouts: map[string]metav1.Duration{
+					"connect": {Duration: 5 * time.Second},
+					"client":  {Duration: 30 * time.Second},
+				},
+				Logging:              &proxyv1alpha1.DefaultsLoggingConfiguration{Enabled: true, HTTPLog: pointer.Bool(true)},
+				AdditionalParameters: "option dontlognull",
+			},
+		),
+	)
+
+	DescribeTable("frontends",
+		func(config string, spec configv1alpha1.FrontendSpec) {
+			frontend, ok := importObject(config).(*configv1alpha1.Frontend)
+			Ω(ok).Should(BeTrue())
+			Ω(frontend.Name).Should(Equal("web"))
+			Ω(frontend.Spec).Should(Equal(spec))
+		},
+		Entry("binds and default backend",
+			"frontend web\n  bind :80\n  bind 10.0.0.1:443 name https accept-proxy\n  default_backend app\n",
+			configv1alpha1.FrontendSpec{
+				BaseSpec: configv1alpha1.BaseSpec{Mode: "tcp", Timeouts: map[string]metav1.Duration{}},
+				Binds: []configv1alpha1.Bind{
+					{Name: ":80", Port: 80},
+					{Name: "https", Address: "10.0.0.1", Port: 443, AcceptProxy: pointer.Bool(true)},
+				},
+				DefaultBackend: corev1.LocalObjectReference{Name: "app"},
+			},
+		),
+		Entry("acls, rules and backend switching",
+			"frontend web\n  mode http\n  bind :80\n  acl api path_beg /api /v1\n  http-request set-header X-Forwarded-Proto http\n  use_backend api if api\n  timeout client 10s\n",
+			configv1alpha1.FrontendSpec{
+				BaseSpec: configv1alpha1.BaseSpec{
+					Mode:     "http",
+					Timeouts: map[string]metav1.Duration{"client": {Duration: 10 * time.Second}},
+					ACL:      []configv1alpha1.ACL{{Name: "api", Criterion: "path_beg", Values: []string{"/api", "/v1"}}},
+					HTTPRequest: &configv1alpha1.HTTPRequestRules{
+						Rules: []configv1alpha1.HTTPRequestRule{{
+							SetHeader: &configv1alpha1.HTTPHeaderRule{Name: "X-Forwarded-Proto", Value: configv1alpha1.HTTPHeaderValue{Str: pointer.String("http")}},
+						}},
+					},
+				},
+				Binds: []configv1alpha1.Bind{{Name: ":80", Port: 80}},
+				BackendSwitching: []configv1alpha1.BackendSwitchingRule{{
+					Rule:    configv1alpha1.Rule{ConditionType: "if", Condition: "api"},
+					Backend: configv1alpha1.BackendReference{Name: pointer.String("api")},
+				}},
+			},
+		),
+	)
+
+	DescribeTable("backends",
+		func(config string, spec configv1alpha1.BackendSpec) {
+			backend, ok := importObject(config).(*configv1alpha1.Backend)
+			Ω(ok).Should(BeTrue())
+			Ω(backend.Name).Should(Equal("app"))
+			Ω(backend.Spec).Should(Equal(spec))
+		},
+		Entry("servers",
+			"backend app\n  server a1 10.0.0.1:8080 check inter 2s weight 10\n  server a2 10.0.0.2:8080 backup\n",
+			configv1alpha1.BackendSpec{
+				BaseSpec: configv1alpha1.BaseSpec{Mode: "tcp", Timeouts: map[string]metav1.Duration{}},
+				Servers: []configv1alpha1.Server{
+					{
+						Name:    "a1",
+						Address: "10.0.0.1",
+						Port:    8080,
+						ServerParams: configv1alpha1.ServerParams{
+							Weight: pointer.Int64(10),
+							Check:  &configv1alpha1.Check{Enabled: true, Inter: &metav1.Duration{Duration: 2 * time.Second}},
+						},
+					},
+					{Name: "a2", Address: "10.0.0.2", Port: 8080, ServerParams: configv1alpha1.ServerParams{Backup: true}},
+				},
+			},
+		),
+		Entry("balance, redispatch and timeouts",
+			"backend app\n  mode http\n  balance roundrobin\n  option redispatch\n  retries 3\n  timeout server 1m\n  server a1 10.0.0.1:80\n",
+			configv1alpha1.BackendSpec{
+				BaseSpec: configv1alpha1.BaseSpec{
+					Mode:     "http",
+					Timeouts: map[string]metav1.Duration{"server": {Duration: time.Minute}},
+				},
+				Balance:    &configv1alpha1.Balance{Algorithm: "roundrobin"},
+				Redispatch: pointer.Bool(true),
+				Retries:    pointer.Int64(3),
+				Servers:    []configv1alpha1.Server{{Name: "a1", Address: "10.0.0.1", Port: 80}},
+			},
+		),
+	)
+
+	DescribeTable("listens",
+		func(config string, spec configv1alpha1.ListenSpec) {
+			listen, ok := importObject(config).(*configv1alpha1.Listen)
+			Ω(ok).Should(BeTrue())
+			Ω(listen.Name).Should(Equal("stats"))
+			Ω(listen.Spec).Should(Equal(spec))
+		},
+		Entry("binds and servers",
+			"listen stats\n  bind 0.0.0.0:9000\n  server s1 10.0.0.2:9000\n",
+			configv1alpha1.ListenSpec{
+				BaseSpec: configv1alpha1.BaseSpec{Mode: "tcp", Timeouts: map[string]metav1.Duration{}},
+				Binds:    []configv1alpha1.Bind{{Name: "0.0.0.0:9000", Address: "0.0.0.0", Port: 9000}},
+				Servers:  []configv1alpha1.Server{{Name: "s1", Address: "10.0.0.2", Port: 9000}},
+			},
+		),
+		Entry("tcp-request rules and timeouts of both sides",
+			"listen stats\n  bind :9000\n  tcp-request content reject if { src 10.0.0.0/8 }\n  timeout client 5s\n  timeout server 10s\n  server s1 10.0.0.2:9000\n",
+			configv1alpha1.ListenSpec{
+				BaseSpec: configv1alpha1.BaseSpec{
+					Mode: "tcp",
+					Timeouts: map[string]metav1.Duration{
+						"client": {Duration: 5 * time.Second},
+						"server": {Duration: 10 * time.Second},
+					},
+					TCPRequest: []configv1alpha1.TCPRequestRule{{
+						Rule:   configv1alpha1.Rule{ConditionType: "if", Condition: "{ src 10.0.0.0/8 }"},
+						Type:   "content",
+						Action: pointer.String("reject"),
+					}},
+				},
+				Binds:   []configv1alpha1.Bind{{Name: ":9000", Port: 9000}},
+				Servers: []configv1alpha1.Server{{Name: "s1", Address: "10.0.0.2", Port: 9000}},
+			},
+		),
+	)
+
+	DescribeTable("resolvers",
+		func(config string, spec configv1alpha1.ResolverSpec) {
+			resolver, ok := importObject(config).(*configv1alpha1.Resolver)
+			Ω(ok).Should(BeTrue())
+			Ω(resolver.Name).Should(Equal("dns"))
+			Ω(resolver.Spec).Should(Equal(spec))
+		},
+		Entry("nameservers",
+			"resolvers dns\n  nameserver ns1 10.0.0.53:53\n  nameserver ns2 10.0.1.53:53\n",
+			configv1alpha1.ResolverSpec{
+				ParseResolvConf: pointer.Bool(false),
+				Timeouts:        &configv1alpha1.Timeouts{},
+				Nameservers: []configv1alpha1.Nameserver{
+					{Name: "ns1", Address: "10.0.0.53", Port: 53},
+					{Name: "ns2", Address: "10.0.1.53", Port: 53},
+				},
+			},
+		),
+		Entry("retries, timeouts and hold periods",
+			"resolvers dns\n  nameserver ns1 10.0.0.53:53\n  resolve_retries 3\n  timeout retry 1s\n  hold valid 10s\n",
+			configv1alpha1.ResolverSpec{
+				ParseResolvConf: pointer.Bool(false),
+				ResolveRetries:  pointer.Int64(3),
+				Timeouts:        &configv1alpha1.Timeouts{Retry: &metav1.Duration{Duration: time.Second}},
+				Hold:            &configv1alpha1.Hold{Valid: &metav1.Duration{Duration: 10 * time.Second}},
+				Nameservers:     []configv1alpha1.Nameserver{{Name: "ns1", Address: "10.0.0.53", Port: 53}},
+			},
+		),
+	)
+
+	DescribeTable("lossy report",
+		func(config string, lossy []importer.LossySpot) {
+			result, err := importer.Import(config, opts)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(result.Lossy).Should(Equal(lossy))
+		},
+		Entry("unsupported sections",
+			"userlist admins\n  user admin insecure-password secret\n",
+			[]importer.LossySpot{{Section: "userlist admins", Statement: "user admin insecure-password secret"}},
+		),
+		Entry("second defaults section",
+			"defaults\n  mode http\ndefaults\n  mode tcp\n",
+			[]importer.LossySpot{{Section: "defaults", Statement: "mode tcp"}},
+		),
+		Entry("servers without port",
+			"backend app\n  server a1 10.0.0.1:80\n  server a2 app.local check\n",
+			[]importer.LossySpot{{Section: "backend app", Statement: "server a2 app.local check"}},
+		),
+		Entry("unsupported rules",
+			"frontend web\n  mode http\n  bind :80\n  http-request capture req.hdr(Host) len 64\n",
+			[]importer.LossySpot{{Section: "frontend web", Statement: "http-request capture req.hdr(Host) len 64"}},
+		),
+		Entry("certificate files",
+			"frontend web\n  bind :443 ssl crt /etc/haproxy/web.pem\n",
+			[]importer.LossySpot{{Section: "frontend web", Statement: "bind :443 ssl crt /etc/haproxy/web.pem"}},
+		),
+	)
+})
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"strconv"
+	"strings"
+
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// importGlobal maps the statements of the global section to the global configuration of the instance, all other
+// statements are kept as additional parameters.
+func importGlobal(global *proxyv1alpha1.GlobalConfiguration, s *section) error {
+	var additional []string
+	var sendHostname *string
+
+	for _, statement := range s.statements {
+		fields := strings.Fields(statement)
+
+		switch {
+		case fields[0] == "maxconn" && len(fields) == 2:
+			value, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			global.Maxconn = pointer.Int64(value)
+		case fields[0] == "nbthread" && len(fields) == 2:
+			value, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return err
+			}
+			global.Nbthread = pointer.Int64(value)
+		case fields[0] == "hard-stop-after" && len(fields) == 2:
+			value, err := parseDuration(fields[1])
+			if err != nil {
+				return err
+			}
+			global.HardStopAfter = &value
+		case fields[0] == "log" && len(fields) >= 3 && len(fields) <= 4 && global.Logging == nil:
+			global.Logging = &proxyv1alpha1.GlobalLoggingConfiguration{
+				Enabled:  true,
+				Address:  fields[1],
+				Facility: fields[2],
+			}
+			if len(fields) == 4 {
+				global.Logging.Level = fields[3]
+			}
+		case fields[0] == "log-send-hostname" && len(fields) <= 2:
+			sendHostname = pointer.String(strings.Join(fields[1:], ""))
+		default:
+			additional = append(additional, statement)
+		}
+	}
+
+	if sendHostname != nil {
+		if global.Logging == nil {
+			additional = append(additional, strings.TrimSpace("log-send-hostname "+*sendHostname))
+		} else {
+			global.Logging.SendHostname = pointer.Bool(true)
+			if *sendHostname != "" {
+				global.Logging.Hostname = sendHostname
+			}
+		}
+	}
+
+	global.AdditionalParameters = strings.Join(additional, "\n")
+
+	return nil
+}
+
+// importDefaults maps the statements of the defaults section to the defaults configuration of the instance, all
+// other statements are kept as additional parameters.
+func importDefaults(defaults *proxyv1alpha1.DefaultsConfiguration, s *section) error {
+	var additional []string
+
+	for _, statement := range s.statements {
+		fields := strings.Fields(statement)
+
+		switch {
+		case fields[0] == "mode" && len(fields) == 2 && (fields[1] == "http" || fields[1] == "tcp"):
+			defaults.Mode = fields[1]
+		case fields[0] == "timeout" && len(fields) == 3 && defaultsTimeouts[fields[1]]:
+			value, err := parseDuration(fields[2])
+			if err != nil {
+				return err
+			}
+			defaults.Timeouts[fields[1]] = metav1.Duration{Duration: value}
+		case statement == "log global":
+			defaultsLogging(defaults).Enabled = true
+		case statement == "option httplog":
+			defaultsLogging(defaults).HTTPLog = pointer.Bool(true)
+		case statement == "option tcplog":
+			defaultsLogging(defaults).TCPLog = pointer.Bool(true)
+		default:
+			additional = append(additional, statement)
+		}
+	}
+
+	defaults.AdditionalParameters = strings.Join(additional, "\n")
+
+	return nil
+}
+
+var defaultsTimeouts = map[string]bool{
+	"check":           true,
+	"client":          true,
+	"client-fin":      true,
+	"connect":         true,
+	"http-keep-alive": true,
+	"http-request":    true,
+	"queue":           true,
+	"server":          true,
+	"server-fin":      true,
+	"tunnel":          true,
+}
+
+func defaultsLogging(defaults *proxyv1alpha1.DefaultsConfiguration) *proxyv1alpha1.DefaultsLoggingConfiguration {
+	if defaults.Logging == nil {
+		defaults.Logging = &proxyv1alpha1.DefaultsLoggingConfiguration{}
+	}
+
+	return defaults.Logging
+}
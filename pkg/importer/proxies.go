@@ -0,0 +1,478 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/haproxytech/client-native/v4/configuration"
+	"github.com/haproxytech/client-native/v4/models"
+	parser "github.com/haproxytech/config-parser/v4"
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func importFrontend(s *section, defaultMode string) (client.Object, error) {
+	spec, err := frontendSpec(s, defaultMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configv1alpha1.Frontend{
+		TypeMeta:   metav1.TypeMeta{APIVersion: configv1alpha1.GroupVersion.String(), Kind: "Frontend"},
+		ObjectMeta: metav1.ObjectMeta{Name: s.name},
+		Spec:       spec,
+	}, nil
+}
+
+func importBackend(s *section, defaultMode string) (client.Object, error) {
+	spec, err := backendSpec(s, defaultMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &configv1alpha1.Backend{
+		TypeMeta:   metav1.TypeMeta{APIVersion: configv1alpha1.GroupVersion.String(), Kind: "Backend"},
+		ObjectMeta: metav1.ObjectMeta{Name: s.name},
+		Spec:       spec,
+	}, nil
+}
+
+// importListen reads the listen section once as frontend and once as backend, as the operator renders listens.
+func importListen(s *section, defaultMode string) (client.Object, error) {
+	frontend, err := frontendSpec(s, defaultMode)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := backendSpec(s, defaultMode)
+	if err != nil {
+		return nil, err
+	}
+
+	base := frontend.BaseSpec
+	for name, timeout := range backend.Timeouts {
+		base.Timeouts[name] = timeout
+	}
+
+	return &configv1alpha1.Listen{
+		TypeMeta:   metav1.TypeMeta{APIVersion: configv1alpha1.GroupVersion.String(), Kind: "Listen"},
+		ObjectMeta: metav1.ObjectMeta{Name: s.name},
+		Spec: configv1alpha1.ListenSpec{
+			BaseSpec:           base,
+			Binds:              frontend.Binds,
+			Servers:            backend.Servers,
+			Balance:            backend.Balance,
+			Redispatch:         backend.Redispatch,
+			RedispatchInterval: backend.RedispatchInterval,
+			Retries:            backend.Retries,
+			HTTPReuse:          backend.HTTPReuse,
+		},
+	}, nil
+}
+
+func importResolver(s *section) (client.Object, error) {
+	p, err := s.parse(parser.Resolvers)
+	if err != nil {
+		return nil, err
+	}
+
+	model := models.Resolver{Name: s.name}
+	if err := configuration.ParseResolverSection(p, &model); err != nil {
+		return nil, err
+	}
+
+	spec := configv1alpha1.ResolverSpec{
+		ParseResolvConf: pointer.Bool(model.ParseResolvConf),
+		Timeouts:        &configv1alpha1.Timeouts{},
+	}
+	hold := configv1alpha1.Hold{
+		Nx:       duration(model.HoldNx),
+		Obsolete: duration(model.HoldObsolete),
+		Other:    duration(model.HoldOther),
+		Refused:  duration(model.HoldRefused),
+		Timeout:  duration(model.HoldTimeout),
+		Valid:    duration(model.HoldValid),
+	}
+	if hold != (configv1alpha1.Hold{}) {
+		spec.Hold = &hold
+	}
+	if model.AcceptedPayloadSize != 0 {
+		spec.AcceptedPayloadSize = pointer.Int64(model.AcceptedPayloadSize)
+	}
+	if model.ResolveRetries != 0 {
+		spec.ResolveRetries = pointer.Int64(model.ResolveRetries)
+	}
+	if model.TimeoutResolve != 0 {
+		spec.Timeouts.Resolve = duration(pointer.Int64(model.TimeoutResolve))
+	}
+	if model.TimeoutRetry != 0 {
+		spec.Timeouts.Retry = duration(pointer.Int64(model.TimeoutRetry))
+	}
+
+	nameservers, err := configuration.ParseNameservers(s.name, p)
+	if err != nil {
+		return nil, err
+	}
+	for _, nameserver := range nameservers {
+		spec.Nameservers = append(spec.Nameservers, configv1alpha1.Nameserver{
+			Name:    nameserver.Name,
+			Address: pointer.StringDeref(nameserver.Address, ""),
+			Port:    pointer.Int64Deref(nameserver.Port, 0),
+		})
+	}
+
+	return &configv1alpha1.Resolver{
+		TypeMeta:   metav1.TypeMeta{APIVersion: configv1alpha1.GroupVersion.String(), Kind: "Resolver"},
+		ObjectMeta: metav1.ObjectMeta{Name: s.name},
+		Spec:       spec,
+	}, nil
+}
+
+func frontendSpec(s *section, defaultMode string) (configv1alpha1.FrontendSpec, error) {
+	var spec configv1alpha1.FrontendSpec
+
+	p, err := s.parse(parser.Frontends)
+	if err != nil {
+		return spec, err
+	}
+
+	model := models.Frontend{}
+	if err := configuration.ParseSection(&model, parser.Frontends, s.name, p); err != nil {
+		return spec, err
+	}
+
+	spec.BaseSpec, err = baseSpec(p, parser.Frontends, s.name, modeOrDefault(model.Mode, defaultMode), model.Forwardfor)
+	if err != nil {
+		return spec, err
+	}
+	addTimeout(spec.Timeouts, "client", model.ClientTimeout)
+	addTimeout(spec.Timeouts, "http-keep-alive", model.HTTPKeepAliveTimeout)
+	addTimeout(spec.Timeouts, "http-request", model.HTTPRequestTimeout)
+
+	spec.DefaultBackend = corev1.LocalObjectReference{Name: model.DefaultBackend}
+
+	binds, err := configuration.ParseBinds(string(parser.Frontends), s.name, p)
+	if err != nil {
+		return spec, err
+	}
+	for _, bind := range binds {
+		spec.Binds = append(spec.Binds, importBind(bind))
+	}
+
+	rules, err := configuration.ParseBackendSwitchingRules(s.name, p)
+	if err != nil {
+		return spec, err
+	}
+	for _, rule := range rules {
+		spec.BackendSwitching = append(spec.BackendSwitching, configv1alpha1.BackendSwitchingRule{
+			Rule:    configv1alpha1.Rule{ConditionType: rule.Cond, Condition: rule.CondTest},
+			Backend: configv1alpha1.BackendReference{Name: pointer.String(rule.Name)},
+		})
+	}
+
+	return spec, nil
+}
+
+func backendSpec(s *section, defaultMode string) (configv1alpha1.BackendSpec, error) {
+	var spec configv1alpha1.BackendSpec
+
+	p, err := s.parse(parser.Backends)
+	if err != nil {
+		return spec, err
+	}
+
+	model := models.Backend{}
+	if err := configuration.ParseSection(&model, parser.Backends, s.name, p); err != nil {
+		return spec, err
+	}
+
+	spec.BaseSpec, err = baseSpec(p, parser.Backends, s.name, modeOrDefault(model.Mode, defaultMode), model.Forwardfor)
+	if err != nil {
+		return spec, err
+	}
+	addTimeout(spec.Timeouts, "check", model.CheckTimeout)
+	addTimeout(spec.Timeouts, "connect", model.ConnectTimeout)
+	addTimeout(spec.Timeouts, "http-keep-alive", model.HTTPKeepAliveTimeout)
+	addTimeout(spec.Timeouts, "http-request", model.HTTPRequestTimeout)
+	addTimeout(spec.Timeouts, "queue", model.QueueTimeout)
+	addTimeout(spec.Timeouts, "server", model.ServerTimeout)
+	addTimeout(spec.Timeouts, "tunnel", model.TunnelTimeout)
+
+	if model.Balance != nil && model.Balance.Algorithm != nil {
+		spec.Balance = &configv1alpha1.Balance{Algorithm: *model.Balance.Algorithm}
+	}
+
+	if model.Redispatch != nil && pointer.StringDeref(model.Redispatch.Enabled, "") == models.RedispatchEnabledEnabled {
+		spec.Redispatch = pointer.Bool(true)
+		if model.Redispatch.Interval != 0 {
+			spec.RedispatchInterval = pointer.Int64(model.Redispatch.Interval)
+		}
+	}
+	spec.Retries = model.Retries
+	spec.HTTPReuse = model.HTTPReuse
+
+	servers, err := configuration.ParseServers(string(parser.Backends), s.name, p)
+	if err != nil {
+		return spec, err
+	}
+	for _, server := range servers {
+		// servers without port use the port of the client connection, which cannot be modelled, and are reported as lossy
+		if server.Port == nil {
+			continue
+		}
+		spec.Servers = append(spec.Servers, configv1alpha1.Server{
+			ServerParams: importServerParams(server.ServerParams),
+			Name:         server.Name,
+			Address:      server.Address,
+			Port:         pointer.Int64Deref(server.Port, 0),
+		})
+	}
+
+	return spec, nil
+}
+
+func baseSpec(p parser.Parser, kind parser.Section, name, mode string, forwardfor *models.Forwardfor) (configv1alpha1.BaseSpec, error) {
+	spec := configv1alpha1.BaseSpec{
+		Mode:     mode,
+		Timeouts: map[string]metav1.Duration{},
+	}
+
+	if forwardfor != nil {
+		spec.Forwardfor = &configv1alpha1.Forwardfor{
+			Enabled: pointer.StringDeref(forwardfor.Enabled, "") == models.ForwardforEnabledEnabled,
+			Except:  forwardfor.Except,
+			Header:  forwardfor.Header,
+			Ifnone:  forwardfor.Ifnone,
+		}
+	}
+
+	acls, err := configuration.ParseACLs(kind, name, p)
+	if err != nil {
+		return spec, err
+	}
+	for _, acl := range acls {
+		spec.ACL = append(spec.ACL, configv1alpha1.ACL{
+			Name:      acl.ACLName,
+			Criterion: acl.Criterion,
+			Values:    strings.Fields(acl.Value),
+		})
+	}
+
+	httpRequestRules, err := configuration.ParseHTTPRequestRules(string(kind), name, p)
+	if err != nil {
+		return spec, err
+	}
+	var rules []configv1alpha1.HTTPRequestRule
+	for _, model := range httpRequestRules {
+		if rule := importHTTPRequestRule(model); rule != nil {
+			rules = append(rules, *rule)
+		}
+	}
+	if len(rules) > 0 {
+		spec.HTTPRequest = &configv1alpha1.HTTPRequestRules{Rules: rules}
+	}
+
+	tcpRequestRules, err := configuration.ParseTCPRequestRules(string(kind), name, p)
+	if err != nil {
+		return spec, err
+	}
+	for _, model := range tcpRequestRules {
+		rule := configv1alpha1.TCPRequestRule{
+			Rule: configv1alpha1.Rule{ConditionType: model.Cond, Condition: model.CondTest},
+			Type: model.Type,
+		}
+		if model.Action != "" {
+			rule.Action = pointer.String(model.Action)
+		}
+		rule.Timeout = duration(model.Timeout)
+
+		// rules with action parameters are not modelled
+		if _, err := rule.Model(); err == nil && tcpRequestActions[model.Action] {
+			spec.TCPRequest = append(spec.TCPRequest, rule)
+		}
+	}
+
+	return spec, nil
+}
+
+// tcpRequestActions are the tcp-request actions without parameters.
+var tcpRequestActions = map[string]bool{
+	"":                     true,
+	"accept":               true,
+	"reject":               true,
+	"silent-drop":          true,
+	"expect-proxy":         true,
+	"expect-netscaler-cip": true,
+}
+
+// importHTTPRequestRule returns nil for rules whose action is not modelled.
+func importHTTPRequestRule(model *models.HTTPRequestRule) *configv1alpha1.HTTPRequestRule {
+	rule := configv1alpha1.Rule{ConditionType: model.Cond, Condition: model.CondTest}
+
+	switch model.Type {
+	case "set-header":
+		return &configv1alpha1.HTTPRequestRule{SetHeader: &configv1alpha1.HTTPHeaderRule{Rule: rule, Name: model.HdrName, Value: configv1alpha1.HTTPHeaderValue{Str: pointer.String(model.HdrFormat)}}}
+	case "add-header":
+		return &configv1alpha1.HTTPRequestRule{AddHeader: &configv1alpha1.HTTPHeaderRule{Rule: rule, Name: model.HdrName, Value: configv1alpha1.HTTPHeaderValue{Str: pointer.String(model.HdrFormat)}}}
+	case "del-header":
+		return &configv1alpha1.HTTPRequestRule{DelHeader: &configv1alpha1.HTTPDelHeaderRule{Rule: rule, Name: model.HdrName, Method: model.HdrMethod}}
+	case "replace-header":
+		return &configv1alpha1.HTTPRequestRule{ReplaceHeader: &configv1alpha1.HTTPReplaceHeaderRule{Rule: rule, Name: model.HdrName, Match: model.HdrMatch, Format: model.HdrFormat}}
+	case "set-path":
+		return &configv1alpha1.HTTPRequestRule{SetPath: &configv1alpha1.HTTPPathRule{Rule: rule, Value: model.PathFmt}}
+	case "replace-path":
+		return &configv1alpha1.HTTPRequestRule{ReplacePath: &configv1alpha1.HTTPReplaceRule{Rule: rule, Match: model.PathMatch, Format: model.PathFmt}}
+	case "replace-uri":
+		return &configv1alpha1.HTTPRequestRule{ReplaceURI: &configv1alpha1.HTTPReplaceRule{Rule: rule, Match: model.URIMatch, Format: model.URIFmt}}
+	case "set-query":
+		return &configv1alpha1.HTTPRequestRule{SetQuery: &configv1alpha1.HTTPFormatRule{Rule: rule, Format: firstNonEmpty(model.QueryFmt, model.HdrFormat)}}
+	case "set-uri":
+		return &configv1alpha1.HTTPRequestRule{SetURI: &configv1alpha1.HTTPFormatRule{Rule: rule, Format: firstNonEmpty(model.URIFmt, model.HdrFormat)}}
+	case "set-method":
+		return &configv1alpha1.HTTPRequestRule{SetMethod: &configv1alpha1.HTTPFormatRule{Rule: rule, Format: model.MethodFmt}}
+	case "set-var":
+		if model.VarExpr == "" {
+			return nil
+		}
+		return &configv1alpha1.HTTPRequestRule{SetVar: &configv1alpha1.HTTPSetVarRule{Rule: rule, Scope: model.VarScope, Name: model.VarName, Expression: model.VarExpr}}
+	case "unset-var":
+		return &configv1alpha1.HTTPRequestRule{UnsetVar: &configv1alpha1.HTTPUnsetVarRule{Rule: rule, Scope: model.VarScope, Name: model.VarName}}
+	case "deny":
+		return &configv1alpha1.HTTPRequestRule{Deny: &configv1alpha1.HTTPDenyRule{Rule: rule, Status: model.DenyStatus}}
+	case "tarpit":
+		return &configv1alpha1.HTTPRequestRule{Tarpit: &configv1alpha1.HTTPDenyRule{Rule: rule, Status: model.DenyStatus}}
+	case "silent-drop":
+		return &configv1alpha1.HTTPRequestRule{SilentDrop: &rule}
+	case "use-service":
+		return &configv1alpha1.HTTPRequestRule{UseService: &configv1alpha1.HTTPNameRule{Rule: rule, Name: model.ServiceName}}
+	case "redirect":
+		if model.RedirOption != "" {
+			return nil
+		}
+		redirect := &configv1alpha1.Redirect{Rule: rule, Code: model.RedirCode, Value: model.RedirValue}
+		switch model.RedirType {
+		case models.HTTPRequestRuleRedirTypeLocation:
+			redirect.Type.Location = true
+		case models.HTTPRequestRuleRedirTypePrefix:
+			redirect.Type.Prefix = true
+		case models.HTTPRequestRuleRedirTypeScheme:
+			redirect.Type.Scheme = true
+		}
+		return &configv1alpha1.HTTPRequestRule{Redirect: redirect}
+	}
+
+	return nil
+}
+
+func importBind(model *models.Bind) configv1alpha1.Bind {
+	bind := configv1alpha1.Bind{
+		Name:         model.Name,
+		Address:      model.Address,
+		Port:         pointer.Int64Deref(model.Port, 0),
+		PortRangeEnd: model.PortRangeEnd,
+		Transparent:  model.Transparent,
+	}
+	if bind.Name == "" {
+		bind.Name = fmt.Sprintf("%s:%d", bind.Address, bind.Port)
+	}
+	if model.AcceptProxy {
+		bind.AcceptProxy = pointer.Bool(true)
+	}
+	if model.Ssl {
+		bind.SSL = &configv1alpha1.SSL{
+			Enabled:    true,
+			Verify:     model.Verify,
+			MinVersion: model.SslMinVer,
+		}
+	}
+
+	return bind
+}
+
+func importServerParams(model models.ServerParams) configv1alpha1.ServerParams {
+	params := configv1alpha1.ServerParams{
+		Weight:         model.Weight,
+		InitAddr:       model.InitAddr,
+		VerifyHost:     model.Verifyhost,
+		Maxconn:        model.Maxconn,
+		Maxqueue:       model.Maxqueue,
+		OnMarkedDown:   model.OnMarkedDown,
+		OnMarkedUp:     model.OnMarkedUp,
+		Source:         model.Source,
+		PoolMaxConn:    model.PoolMaxConn,
+		PoolPurgeDelay: duration(model.PoolPurgeDelay),
+		Slowstart:      duration(model.Slowstart),
+		Backup:         model.Backup == models.ServerParamsBackupEnabled,
+		Disabled:       model.Maintenance == models.ServerParamsMaintenanceEnabled,
+	}
+
+	if model.SendProxy == models.ServerParamsSendProxyEnabled {
+		params.SendProxy = pointer.Bool(true)
+	}
+
+	if model.Ssl == models.ServerParamsSslEnabled {
+		params.SSL = &configv1alpha1.SSL{
+			Enabled:    true,
+			Verify:     model.Verify,
+			MinVersion: model.SslMinVer,
+			SNI:        model.Sni,
+		}
+		if model.Alpn != "" {
+			params.SSL.Alpn = strings.Split(model.Alpn, ",")
+		}
+	}
+
+	if model.Check == models.ServerParamsCheckEnabled {
+		params.Check = &configv1alpha1.Check{
+			Enabled:   true,
+			Inter:     duration(model.Inter),
+			Fastinter: duration(model.Fastinter),
+			Downinter: duration(model.Downinter),
+			Rise:      model.Rise,
+			Fall:      model.Fall,
+			Port:      model.HealthCheckPort,
+			Address:   model.HealthCheckAddress,
+		}
+	}
+
+	if model.Resolvers != "" {
+		params.Resolvers = &corev1.LocalObjectReference{Name: model.Resolvers}
+	}
+
+	return params
+}
+
+func modeOrDefault(mode, defaultMode string) string {
+	if mode == "" {
+		return defaultMode
+	}
+
+	return mode
+}
+
+func addTimeout(timeouts map[string]metav1.Duration, name string, milliseconds *int64) {
+	if milliseconds != nil {
+		timeouts[name] = *duration(milliseconds)
+	}
+}
+
+func duration(milliseconds *int64) *metav1.Duration {
+	if milliseconds == nil {
+		return nil
+	}
+
+	return &metav1.Duration{Duration: time.Duration(*milliseconds) * time.Millisecond}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
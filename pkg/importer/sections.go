@@ -0,0 +1,213 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	parser "github.com/haproxytech/config-parser/v4"
+	"github.com/haproxytech/config-parser/v4/options"
+)
+
+var sectionKinds = map[string]parser.Section{
+	string(parser.Global):     parser.Global,
+	string(parser.Defaults):   parser.Defaults,
+	string(parser.Frontends):  parser.Frontends,
+	string(parser.Backends):   parser.Backends,
+	string(parser.Listen):     parser.Listen,
+	string(parser.Resolvers):  parser.Resolvers,
+	string(parser.Peers):      parser.Peers,
+	string(parser.UserList):   parser.UserList,
+	string(parser.Mailers):    parser.Mailers,
+	string(parser.Cache):      parser.Cache,
+	string(parser.Program):    parser.Program,
+	string(parser.HTTPErrors): parser.HTTPErrors,
+	string(parser.Ring):       parser.Ring,
+	string(parser.LogForward): parser.LogForward,
+	string(parser.FCGIApp):    parser.FCGIApp,
+}
+
+// section holds the statements of a configuration section without comments and blank lines.
+type section struct {
+	kind       parser.Section
+	name       string
+	statements []string
+}
+
+func (s *section) String() string {
+	if s.name == "" {
+		return string(s.kind)
+	}
+
+	return fmt.Sprintf("%s %s", s.kind, s.name)
+}
+
+// parse returns a parser holding the statements in a section of the given kind, which allows to read a listen
+// section once as frontend and once as backend.
+func (s *section) parse(kind parser.Section) (parser.Parser, error) {
+	header := string(kind)
+	if s.name != "" && kind != parser.Global {
+		header = fmt.Sprintf("%s %s", kind, s.name)
+	}
+
+	return parser.New(options.String(fmt.Sprintf("%s\n  %s\n", header, strings.Join(s.statements, "\n  "))))
+}
+
+// renderedKinds returns the kinds of the sections the operator renders for the section.
+func (s *section) renderedKinds() []parser.Section {
+	if s.kind == parser.Listen {
+		return []parser.Section{parser.Frontends, parser.Backends}
+	}
+
+	return []parser.Section{s.kind}
+}
+
+func splitSections(config string) []*section {
+	var sections []*section
+	var current *section
+
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(stripComment(line))
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if kind, ok := sectionKinds[fields[0]]; ok {
+			current = &section{kind: kind}
+			if len(fields) > 1 {
+				current.name = fields[1]
+			}
+			sections = append(sections, current)
+			continue
+		}
+
+		if current != nil {
+			current.statements = append(current.statements, strings.Join(fields, " "))
+		}
+	}
+
+	return sections
+}
+
+func stripComment(line string) string {
+	quoted := false
+	for i, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == '#' && !quoted && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+type renderedStatements map[parser.Section][]string
+
+func (r renderedStatements) of(kinds ...parser.Section) []string {
+	var statements []string
+	for _, kind := range kinds {
+		statements = append(statements, r[kind]...)
+	}
+
+	return statements
+}
+
+// unorderedParams are the keywords whose parameters may be rendered in a different order, mapped to the number of
+// leading fields which identify the statement.
+var unorderedParams = map[string]int{
+	"bind":            2,
+	"server":          3,
+	"server-template": 4,
+	"nameserver":      3,
+}
+
+// covered returns true if the statement is contained in one of the rendered statements. Durations are compared in
+// milliseconds, and rendered statements may add parameters which are implicit in the original statement.
+func covered(statement string, rendered []string) bool {
+	want := normalize(statement)
+	for _, r := range rendered {
+		if contains(normalize(r), want) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(fields, want []string) bool {
+	if len(want) == 0 || len(fields) == 0 || fields[0] != want[0] {
+		return false
+	}
+
+	if n, ok := unorderedParams[want[0]]; ok {
+		if len(fields) < n || len(want) < n {
+			return false
+		}
+		for i := 0; i < n; i++ {
+			if fields[i] != want[i] {
+				return false
+			}
+		}
+
+		return isSubset(fields[n:], want[n:])
+	}
+
+	i := 0
+	for _, field := range fields {
+		if i < len(want) && field == want[i] {
+			i++
+		}
+	}
+
+	return i == len(want)
+}
+
+func isSubset(fields, want []string) bool {
+	counts := map[string]int{}
+	for _, field := range fields {
+		counts[field]++
+	}
+	for _, field := range want {
+		if counts[field] == 0 {
+			return false
+		}
+		counts[field]--
+	}
+
+	return true
+}
+
+var durationPattern = regexp.MustCompile(`^([0-9]+)(us|ms|s|m|h|d)?$`)
+
+var durationUnits = map[string]time.Duration{"": time.Millisecond, "us": time.Microsecond, "ms": time.Millisecond, "s": time.Second, "m": time.Minute, "h": time.Hour, "d": 24 * time.Hour}
+
+// parseDuration parses an HAProxy time value, which is in milliseconds if no unit is given.
+func parseDuration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid duration %s", value)
+	}
+	n, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(n) * durationUnits[match[2]], nil
+}
+
+func normalize(statement string) []string {
+	fields := strings.Fields(statement)
+	for i, field := range fields {
+		if match := durationPattern.FindStringSubmatch(field); match != nil && match[2] != "" {
+			d, _ := parseDuration(field)
+			fields[i] = strconv.FormatInt(d.Milliseconds(), 10)
+		}
+	}
+
+	return fields
+}
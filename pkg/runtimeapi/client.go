@@ -0,0 +1,125 @@
+package runtimeapi
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// successResponses lists the non-empty responses of commands which completed successfully.
+var successResponses = map[string]bool{
+	"New server registered.": true,
+	"Server deleted.":        true,
+	"Done.":                  true,
+	"OCSP Response updated!": true,
+}
+
+// successResponsePrefixes lists the beginnings of the responses of the ssl commands which completed successfully.
+var successResponsePrefixes = []string{
+	"Transaction created for certificate",
+	"Transaction updated for certificate",
+}
+
+func isSuccessResponse(response string) bool {
+	for _, prefix := range successResponsePrefixes {
+		if strings.HasPrefix(response, prefix) {
+			return true
+		}
+	}
+
+	// commit ssl cert reports its progress before the result
+	return strings.HasPrefix(response, "Committing") && strings.HasSuffix(response, "Success!")
+}
+
+// Client executes commands on the runtime API of a single HAProxy process.
+type Client interface {
+	Execute(ctx context.Context, command string) (string, error)
+}
+
+// Factory creates a Client for the runtime API listening on the given address.
+type Factory func(address string) Client
+
+type tcpClient struct {
+	address   string
+	timeout   time.Duration
+	tlsConfig *tls.Config
+}
+
+// NewClient returns a client for a runtime API without TLS. The runtime API of the instances requires TLS, see
+// NewTLSClient.
+func NewClient(address string) Client {
+	return &tcpClient{
+		address: address,
+		timeout: defaultTimeout,
+	}
+}
+
+// NewTLSClient returns a client for a runtime API which requires TLS, e.g. with client certificates.
+func NewTLSClient(address string, config *tls.Config) Client {
+	return &tcpClient{
+		address:   address,
+		timeout:   defaultTimeout,
+		tlsConfig: config,
+	}
+}
+
+func (c *tcpClient) Execute(ctx context.Context, command string) (string, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return "", err
+	}
+
+	if _, err := conn.Write([]byte(command + "\n")); err != nil {
+		return "", err
+	}
+
+	data, err := io.ReadAll(conn)
+	if err != nil {
+		return "", err
+	}
+
+	// the runtime API terminates each response with an empty line, a connection closed without any response was
+	// rejected, e.g. by a TLS endpoint receiving plain text
+	if len(data) == 0 {
+		return "", fmt.Errorf("runtime api command '%s' failed: connection closed without response", command)
+	}
+
+	response := strings.TrimSpace(string(data))
+	if response != "" && !successResponses[response] && !isSuccessResponse(response) {
+		return response, fmt.Errorf("runtime api command '%s' failed: %s", command, response)
+	}
+
+	return response, nil
+}
+
+func (c *tcpClient) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	if c.tlsConfig == nil {
+		return dialer.DialContext(ctx, "tcp", c.address)
+	}
+
+	tlsDialer := &tls.Dialer{NetDialer: dialer, Config: c.tlsConfig}
+	return tlsDialer.DialContext(ctx, "tcp", c.address)
+}
+
+// Apply executes the commands in order and stops at the first failure.
+func Apply(ctx context.Context, client Client, commands []string) error {
+	for _, command := range commands {
+		if _, err := client.Execute(ctx, command); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
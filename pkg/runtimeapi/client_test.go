@@ -0,0 +1,100 @@
+package runtimeapi_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/six-group/haproxy-operator/pkg/runtimeapi"
+)
+
+// certificate returns a certificate signed by the parent, or a self-signed CA without parent.
+func certificate(template *x509.Certificate, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	issuer, signer := template, any(key)
+	if parent != nil {
+		issuer, signer = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	Ω(err).ShouldNot(HaveOccurred())
+	leaf, err := x509.ParseCertificate(der)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+var _ = Describe("Client", Label("runtimeapi"), func() {
+	var (
+		address  string
+		commands chan string
+		config   *tls.Config
+	)
+
+	BeforeEach(func() {
+		ca := certificate(&x509.Certificate{Subject: pkix.Name{CommonName: "ca"}, IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}, nil)
+		server := certificate(&x509.Certificate{Subject: pkix.Name{CommonName: "runtime-api"}, DNSNames: []string{"runtime-api"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}, &ca)
+		client := certificate(&x509.Certificate{Subject: pkix.Name{CommonName: "operator"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}, &ca)
+
+		pool := x509.NewCertPool()
+		pool.AddCert(ca.Leaf)
+		config = &tls.Config{Certificates: []tls.Certificate{client}, RootCAs: pool, ServerName: "runtime-api", MinVersion: tls.VersionTLS12}
+
+		// like the runtime API of the instances, the listener requires TLS with a client certificate
+		listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+			Certificates: []tls.Certificate{server},
+			ClientCAs:    pool,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			MinVersion:   tls.VersionTLS12,
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+		DeferCleanup(listener.Close)
+		address = listener.Addr().String()
+
+		commands = make(chan string, 10)
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				if command, err := bufio.NewReader(conn).ReadString('\n'); err == nil {
+					commands <- command
+					_, _ = conn.Write([]byte("\n"))
+				}
+				conn.Close()
+			}
+		}()
+	})
+
+	It("should execute commands on a TLS runtime API", func() {
+		response, err := runtimeapi.NewTLSClient(address, config).Execute(context.Background(), "set server app/a weight 10")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(response).Should(BeEmpty())
+		Ω(commands).Should(Receive(Equal("set server app/a weight 10\n")))
+	})
+	It("should fail without TLS on a TLS runtime API", func() {
+		_, err := runtimeapi.NewClient(address).Execute(context.Background(), "set server app/a weight 10")
+		Ω(err).Should(HaveOccurred())
+		Ω(commands).ShouldNot(Receive())
+	})
+	It("should fail without the client certificate", func() {
+		_, err := runtimeapi.NewTLSClient(address, &tls.Config{RootCAs: config.RootCAs, ServerName: "runtime-api", MinVersion: tls.VersionTLS12}).Execute(context.Background(), "show info")
+		Ω(err).Should(HaveOccurred())
+		Ω(commands).ShouldNot(Receive())
+	})
+})
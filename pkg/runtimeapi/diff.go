@@ -0,0 +1,358 @@
+package runtimeapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	parser "github.com/haproxytech/config-parser/v4"
+	"github.com/haproxytech/config-parser/v4/options"
+	"github.com/haproxytech/config-parser/v4/params"
+	"github.com/haproxytech/config-parser/v4/types"
+)
+
+// dynamicServerKeywords contains the server keywords supported by the 'add server' command.
+var dynamicServerKeywords = map[string]bool{
+	"agent-addr": true, "agent-check": true, "agent-inter": true, "agent-port": true, "agent-send": true,
+	"allow-0rtt": true, "alpn": true, "backup": true, "ca-file": true, "check": true, "check-alpn": true,
+	"check-proto": true, "check-send-proxy": true, "check-sni": true, "check-ssl": true, "ciphers": true,
+	"ciphersuites": true, "cookie": true, "crl-file": true, "crt": true, "disabled": true, "downinter": true,
+	"enabled": true, "error-limit": true, "fall": true, "fastinter": true, "force-sslv3": true, "force-tlsv10": true,
+	"force-tlsv11": true, "force-tlsv12": true, "force-tlsv13": true, "id": true, "inter": true, "maxconn": true,
+	"maxqueue": true, "minconn": true, "no-ssl-reuse": true, "no-sslv3": true, "no-tls-tickets": true,
+	"no-tlsv10": true, "no-tlsv11": true, "no-tlsv12": true, "no-tlsv13": true, "npn": true, "observe": true,
+	"on-error": true, "on-marked-down": true, "on-marked-up": true, "pool-low-conn": true, "pool-max-conn": true,
+	"pool-purge-delay": true, "port": true, "proto": true, "proxy-v2-options": true, "rise": true,
+	"send-proxy": true, "send-proxy-v2": true, "send-proxy-v2-ssl": true, "send-proxy-v2-ssl-cn": true,
+	"slowstart": true, "sni": true, "source": true, "ssl": true, "ssl-max-ver": true, "ssl-min-ver": true,
+	"tfo": true, "track": true, "verify": true, "verifyhost": true, "weight": true, "ws": true,
+}
+
+// Changes contains the runtime API commands required to move from one configuration to another.
+type Changes struct {
+	// Commands to be executed in order on every HAProxy process.
+	Commands []string
+	// Reload is set if the configurations differ in a way which cannot be applied through the runtime API.
+	Reload bool
+}
+
+// Diff compares two rendered configurations and their map, certificate or OCSP response files keyed by file path.
+// Server changes and changes of the file contents are translated into runtime API commands, any other change requires
+// a reload.
+func Diff(previous, current string, previousFiles, currentFiles map[string]string) (Changes, error) {
+	var changes Changes
+
+	previousServers, previousStructure, _, err := splitServers(previous)
+	if err != nil {
+		return changes, err
+	}
+	currentServers, currentStructure, defaultServers, err := splitServers(current)
+	if err != nil {
+		return changes, err
+	}
+
+	if previousStructure != currentStructure || len(previousFiles) != len(currentFiles) {
+		changes.Reload = true
+		return changes, nil
+	}
+
+	for _, backend := range sortedKeys(currentServers) {
+		commands, ok := diffServers(backend, previousServers[backend], currentServers[backend], defaultServers[backend])
+		if !ok {
+			changes.Reload = true
+			return changes, nil
+		}
+		changes.Commands = append(changes.Commands, commands...)
+	}
+
+	for _, file := range sortedKeys(currentFiles) {
+		data, ok := previousFiles[file]
+		if !ok {
+			changes.Reload = true
+			return changes, nil
+		}
+		if data == currentFiles[file] {
+			continue
+		}
+
+		var commands []string
+		switch filepath.Ext(file) {
+		case ".map":
+			commands, ok = diffMap(file, data, currentFiles[file])
+		case ".pem":
+			commands, ok = updateCertificate(file, currentFiles[file]), true
+		case ".ocsp":
+			commands, ok = updateOCSPResponse(currentFiles[file]), true
+		default:
+			ok = false
+		}
+		if !ok {
+			changes.Reload = true
+			return changes, nil
+		}
+		changes.Commands = append(changes.Commands, commands...)
+	}
+
+	return changes, nil
+}
+
+// splitServers returns the servers by backend, the configuration without the servers and the backends whose servers
+// inherit default-server settings of the backend or of a defaults section.
+func splitServers(config string) (map[string]map[string]types.Server, string, map[string]bool, error) {
+	p, err := parser.New(options.String(config))
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	backends, err := p.SectionsGet(parser.Backends)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	defaults, err := p.SectionsGet(parser.Defaults)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	var inherited bool
+	for _, section := range defaults {
+		if _, err := p.Get(parser.Defaults, section, "default-server"); err == nil {
+			inherited = true
+		}
+	}
+
+	servers := map[string]map[string]types.Server{}
+	defaultServers := map[string]bool{}
+	for _, backend := range backends {
+		servers[backend] = map[string]types.Server{}
+
+		_, err := p.Get(parser.Backends, backend, "default-server")
+		defaultServers[backend] = inherited || err == nil
+
+		data, err := p.Get(parser.Backends, backend, "server")
+		if err != nil {
+			continue
+		}
+		for _, server := range data.([]types.Server) {
+			servers[backend][server.Name] = server
+		}
+
+		if err := p.Set(parser.Backends, backend, "server", nil); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	return servers, p.String(), defaultServers, nil
+}
+
+// diffServers returns the commands to update the servers of a backend. Added servers would not inherit the
+// default-server settings, so they require a reload if the backend has any.
+func diffServers(backend string, previous, current map[string]types.Server, defaultServer bool) ([]string, bool) {
+	var commands []string
+
+	for _, name := range sortedKeys(previous) {
+		if _, ok := current[name]; !ok {
+			commands = append(commands, deleteServer(backend, name)...)
+		}
+	}
+
+	for _, name := range sortedKeys(current) {
+		server := current[name]
+
+		old, ok := previous[name]
+		if ok && old.Address == server.Address && staticParams(old) == staticParams(server) {
+			if weight(old) != weight(server) {
+				commands = append(commands, fmt.Sprintf("set server %s/%s weight %d", backend, name, weight(server)))
+			}
+			if disabled(old) != disabled(server) {
+				commands = append(commands, fmt.Sprintf("set server %s/%s state %s", backend, name, state(server)))
+			}
+			continue
+		}
+
+		if defaultServer {
+			return nil, false
+		}
+		for _, param := range server.Params {
+			if !dynamicServerKeywords[keyword(param)] {
+				return nil, false
+			}
+		}
+
+		if ok {
+			commands = append(commands, deleteServer(backend, name)...)
+		}
+		commands = append(commands, addServer(backend, server)...)
+	}
+
+	return commands, true
+}
+
+func deleteServer(backend, name string) []string {
+	return []string{
+		fmt.Sprintf("set server %s/%s state maint", backend, name),
+		fmt.Sprintf("shutdown sessions server %s/%s", backend, name),
+		fmt.Sprintf("del server %s/%s", backend, name),
+	}
+}
+
+// addServer registers a dynamic server. New servers start in maintenance and without active checks.
+func addServer(backend string, server types.Server) []string {
+	var opts []params.ServerOption
+	for _, param := range server.Params {
+		if keyword(param) != "disabled" {
+			opts = append(opts, param)
+		}
+	}
+
+	commands := []string{
+		strings.TrimSpace(fmt.Sprintf("add server %s/%s %s %s", backend, server.Name, server.Address, params.ServerOptionsString(opts))),
+	}
+
+	for _, param := range opts {
+		switch keyword(param) {
+		case "check":
+			commands = append(commands, fmt.Sprintf("enable health %s/%s", backend, server.Name))
+		case "agent-check":
+			commands = append(commands, fmt.Sprintf("enable agent %s/%s", backend, server.Name))
+		}
+	}
+
+	if !disabled(server) {
+		commands = append(commands, fmt.Sprintf("set server %s/%s state ready", backend, server.Name))
+	}
+
+	return commands
+}
+
+func diffMap(file, previous, current string) ([]string, bool) {
+	previousKeys, previousValues := parseMap(previous)
+	currentKeys, currentValues := parseMap(current)
+
+	var commands []string
+	var kept []string
+
+	for _, key := range previousKeys {
+		if _, ok := currentValues[key]; !ok {
+			commands = append(commands, fmt.Sprintf("del map %s %s", file, key))
+			continue
+		}
+		kept = append(kept, key)
+	}
+
+	// entries added at runtime are appended, the order of the remaining entries must not change as the first
+	// matching entry wins
+	for idx, key := range kept {
+		if idx >= len(currentKeys) || currentKeys[idx] != key {
+			return nil, false
+		}
+		if previousValues[key] != currentValues[key] {
+			commands = append(commands, fmt.Sprintf("set map %s %s %s", file, key, currentValues[key]))
+		}
+	}
+
+	for _, key := range currentKeys[len(kept):] {
+		if _, ok := previousValues[key]; ok {
+			return nil, false
+		}
+		commands = append(commands, fmt.Sprintf("add map %s %s %s", file, key, currentValues[key]))
+	}
+
+	return commands, true
+}
+
+func parseMap(data string) ([]string, map[string]string) {
+	var keys []string
+	values := map[string]string{}
+
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if _, ok := values[fields[0]]; ok {
+			continue
+		}
+		keys = append(keys, fields[0])
+		values[fields[0]] = strings.Join(fields[1:], " ")
+	}
+
+	return keys, values
+}
+
+// updateCertificate replaces a loaded certificate with the PEM bundle in a transaction. The payload is terminated by
+// the empty line appended to every command.
+func updateCertificate(file, current string) []string {
+	return []string{
+		fmt.Sprintf("set ssl cert %s <<\n%s\n", file, strings.TrimSpace(current)),
+		fmt.Sprintf("commit ssl cert %s", file),
+	}
+}
+
+// updateOCSPResponse replaces the OCSP response of the certificate it was issued for, which HAProxy looks up by the
+// certificate ID in the response.
+func updateOCSPResponse(current string) []string {
+	return []string{
+		fmt.Sprintf("set ssl ocsp-response <<\n%s\n", base64.StdEncoding.EncodeToString([]byte(current))),
+	}
+}
+
+func keyword(param params.ServerOption) string {
+	return strings.SplitN(param.String(), " ", 2)[0]
+}
+
+// staticParams returns the server parameters which cannot be changed on an existing server.
+func staticParams(server types.Server) string {
+	var opts []params.ServerOption
+	for _, param := range server.Params {
+		switch keyword(param) {
+		case "weight", "disabled":
+		default:
+			opts = append(opts, param)
+		}
+	}
+
+	return params.ServerOptionsString(opts)
+}
+
+func weight(server types.Server) int64 {
+	for _, param := range server.Params {
+		if value, ok := param.(*params.ServerOptionValue); ok && value.Name == "weight" {
+			if weight, err := strconv.ParseInt(value.Value, 10, 64); err == nil {
+				return weight
+			}
+		}
+	}
+
+	return 1
+}
+
+func disabled(server types.Server) bool {
+	for _, param := range server.Params {
+		if keyword(param) == "disabled" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func state(server types.Server) string {
+	if disabled(server) {
+		return "maint"
+	}
+
+	return "ready"
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,224 @@
+package runtimeapi_test
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/haproxytech/config-parser/v4/params"
+	"github.com/haproxytech/config-parser/v4/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/six-group/haproxy-operator/pkg/runtimeapi"
+)
+
+var baseConfig = `
+frontend web
+  bind :80
+  default_backend app
+
+backend app
+  mode http
+  server a 10.0.0.1:80 check weight 10
+  server b 10.0.0.2:80 check
+`
+
+func server(name, address string, options ...params.ServerOption) types.Server {
+	return types.Server{Name: name, Address: address, Params: options}
+}
+
+var _ = Describe("Diff", Label("runtimeapi"), func() {
+	It("should return no commands for identical configurations", func() {
+		changes, err := runtimeapi.Diff(baseConfig, baseConfig, nil, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(changes.Reload).Should(BeFalse())
+		Ω(changes.Commands).Should(BeEmpty())
+	})
+	It("should translate server changes into commands", func() {
+		current := `
+frontend web
+  bind :80
+  default_backend app
+
+backend app
+  mode http
+  server a 10.0.0.1:80 check weight 20
+  server c 10.0.0.3:80 check
+`
+		changes, err := runtimeapi.Diff(baseConfig, current, nil, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(changes.Reload).Should(BeFalse())
+		Ω(changes.Commands).Should(Equal([]string{
+			"set server app/b state maint",
+			"shutdown sessions server app/b",
+			"del server app/b",
+			"set server app/a weight 20",
+			"add server app/c 10.0.0.3:80 check",
+			"enable health app/c",
+			"set server app/c state ready",
+		}))
+	})
+	It("should require a reload to add servers to backends with default-server settings", func() {
+		current := `
+frontend web
+  bind :80
+  default_backend app
+
+backend app
+  mode http
+  default-server check
+  server a 10.0.0.1:80 check weight 10
+  server b 10.0.0.2:80 check
+  server c 10.0.0.3:80 check
+`
+		previous := strings.Replace(current, "  server c 10.0.0.3:80 check\n", "", 1)
+		changes, err := runtimeapi.Diff(previous, current, nil, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(changes.Reload).Should(BeTrue())
+
+		changes, err = runtimeapi.Diff("defaults\n  default-server check\n"+baseConfig, "defaults\n  default-server check\n"+strings.Replace(baseConfig, "server b 10.0.0.2:80 check", "server c 10.0.0.3:80 check", 1), nil, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(changes.Reload).Should(BeTrue())
+	})
+	It("should require a reload if anything but the servers changes", func() {
+		current := `
+frontend web
+  bind :8080
+  default_backend app
+
+backend app
+  mode http
+  server a 10.0.0.1:80 check weight 10
+  server b 10.0.0.2:80 check
+`
+		changes, err := runtimeapi.Diff(baseConfig, current, nil, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(changes.Reload).Should(BeTrue())
+		Ω(changes.Commands).Should(BeEmpty())
+	})
+	It("should require a reload if files are added or removed", func() {
+		changes, err := runtimeapi.Diff(baseConfig, baseConfig, nil, map[string]string{"/etc/haproxy/hosts.map": "a b\n"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(changes.Reload).Should(BeTrue())
+
+		changes, err = runtimeapi.Diff(baseConfig, baseConfig, map[string]string{"/etc/haproxy/a.map": ""}, map[string]string{"/etc/haproxy/b.map": ""})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(changes.Reload).Should(BeTrue())
+	})
+	It("should require a reload if other files are changed", func() {
+		changes, err := runtimeapi.Diff(baseConfig, baseConfig, map[string]string{"/etc/haproxy/allow.txt": "10.0.0.0/8\n"}, map[string]string{"/etc/haproxy/allow.txt": "192.168.0.0/16\n"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(changes.Reload).Should(BeTrue())
+	})
+	It("should update changed files", func() {
+		previousFiles := map[string]string{
+			"/etc/haproxy/hosts.map":  "foo.com app\n",
+			"/etc/haproxy/cert.pem":   "old",
+			"/etc/haproxy/cert.ocsp":  "old",
+			"/etc/haproxy/static.map": "a b\n",
+		}
+		currentFiles := map[string]string{
+			"/etc/haproxy/hosts.map":  "foo.com app\nbar.com app\n",
+			"/etc/haproxy/cert.pem":   "new\n",
+			"/etc/haproxy/cert.ocsp":  "new",
+			"/etc/haproxy/static.map": "a b\n",
+		}
+		changes, err := runtimeapi.Diff(baseConfig, baseConfig, previousFiles, currentFiles)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(changes.Reload).Should(BeFalse())
+		Ω(changes.Commands).Should(Equal([]string{
+			"set ssl ocsp-response <<\n" + base64.StdEncoding.EncodeToString([]byte("new")) + "\n",
+			"set ssl cert /etc/haproxy/cert.pem <<\nnew\n",
+			"commit ssl cert /etc/haproxy/cert.pem",
+			"add map /etc/haproxy/hosts.map bar.com app",
+		}))
+	})
+})
+
+var _ = Describe("DiffServers", Label("runtimeapi"), func() {
+	It("should add new servers in maintenance if they are disabled", func() {
+		commands, ok := runtimeapi.DiffServers("app", nil, map[string]types.Server{
+			"a": server("a", "10.0.0.1:80", &params.ServerOptionWord{Name: "disabled"}),
+		}, false)
+		Ω(ok).Should(BeTrue())
+		Ω(commands).Should(Equal([]string{"add server app/a 10.0.0.1:80"}))
+	})
+	It("should change the state of existing servers", func() {
+		commands, ok := runtimeapi.DiffServers("app",
+			map[string]types.Server{"a": server("a", "10.0.0.1:80")},
+			map[string]types.Server{"a": server("a", "10.0.0.1:80", &params.ServerOptionWord{Name: "disabled"})},
+			false,
+		)
+		Ω(ok).Should(BeTrue())
+		Ω(commands).Should(Equal([]string{"set server app/a state maint"}))
+	})
+	It("should recreate servers with a changed address", func() {
+		commands, ok := runtimeapi.DiffServers("app",
+			map[string]types.Server{"a": server("a", "10.0.0.1:80")},
+			map[string]types.Server{"a": server("a", "10.0.0.2:80")},
+			false,
+		)
+		Ω(ok).Should(BeTrue())
+		Ω(commands).Should(Equal([]string{
+			"set server app/a state maint",
+			"shutdown sessions server app/a",
+			"del server app/a",
+			"add server app/a 10.0.0.2:80",
+			"set server app/a state ready",
+		}))
+	})
+	It("should enable agent checks of added servers", func() {
+		commands, ok := runtimeapi.DiffServers("app", nil, map[string]types.Server{
+			"a": server("a", "10.0.0.1:80", &params.ServerOptionWord{Name: "agent-check"}),
+		}, false)
+		Ω(ok).Should(BeTrue())
+		Ω(commands).Should(ContainElement("enable agent app/a"))
+	})
+	It("should reject keywords not supported by dynamic servers", func() {
+		_, ok := runtimeapi.DiffServers("app", nil, map[string]types.Server{
+			"a": server("a", "10.0.0.1:80", &params.ServerOptionValue{Name: "resolvers", Value: "dns"}),
+		}, false)
+		Ω(ok).Should(BeFalse())
+	})
+	It("should not add servers which would miss the default-server settings", func() {
+		_, ok := runtimeapi.DiffServers("app", nil, map[string]types.Server{"a": server("a", "10.0.0.1:80")}, true)
+		Ω(ok).Should(BeFalse())
+
+		commands, ok := runtimeapi.DiffServers("app",
+			map[string]types.Server{"a": server("a", "10.0.0.1:80")},
+			map[string]types.Server{"a": server("a", "10.0.0.1:80", &params.ServerOptionWord{Name: "disabled"})},
+			true,
+		)
+		Ω(ok).Should(BeTrue())
+		Ω(commands).Should(Equal([]string{"set server app/a state maint"}))
+	})
+})
+
+var _ = Describe("DiffMap", Label("runtimeapi"), func() {
+	It("should add, change and remove entries", func() {
+		commands, ok := runtimeapi.DiffMap("/hosts.map", "a 1\nb 2\nc 3\n", "# comment\na 1\nc 4\nd 5\n")
+		Ω(ok).Should(BeTrue())
+		Ω(commands).Should(Equal([]string{
+			"del map /hosts.map b",
+			"set map /hosts.map c 4",
+			"add map /hosts.map d 5",
+		}))
+	})
+	It("should keep values with spaces", func() {
+		commands, ok := runtimeapi.DiffMap("/hosts.map", "a 1\n", "a 1 2\n")
+		Ω(ok).Should(BeTrue())
+		Ω(commands).Should(Equal([]string{"set map /hosts.map a 1 2"}))
+	})
+	It("should ignore duplicate keys as the first entry wins", func() {
+		commands, ok := runtimeapi.DiffMap("/hosts.map", "a 1\n", "a 1\na 2\n")
+		Ω(ok).Should(BeTrue())
+		Ω(commands).Should(BeEmpty())
+	})
+	It("should reject reordered entries", func() {
+		_, ok := runtimeapi.DiffMap("/hosts.map", "a 1\nb 2\n", "b 2\na 1\n")
+		Ω(ok).Should(BeFalse())
+	})
+	It("should reject entries inserted before existing ones", func() {
+		_, ok := runtimeapi.DiffMap("/hosts.map", "a 1\nb 2\n", "c 3\na 1\nb 2\n")
+		Ω(ok).Should(BeFalse())
+	})
+})
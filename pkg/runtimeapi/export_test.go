@@ -0,0 +1,6 @@
+package runtimeapi
+
+var (
+	DiffServers = diffServers
+	DiffMap     = diffMap
+)
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// aclValuePattern matches the values which can be used unquoted as ACL patterns.
+var aclValuePattern = regexp.MustCompile(`^[A-Za-z0-9/._~:%@!*+,;=-]+$`)
+
+// ValidACLValue reports whether the value can be quoted by QuoteACLValue. Single quotes cannot be escaped within
+// single quotes and control characters would end the configuration line.
+func ValidACLValue(value string) bool {
+	return !strings.ContainsAny(value, "'") && strings.IndexFunc(value, unicode.IsControl) == -1
+}
+
+// QuoteACLValue quotes values containing white spaces, braces, quotes or other characters interpreted by the HAProxy
+// configuration parser. Within single quotes neither escape sequences nor environment variables are expanded.
+func QuoteACLValue(value string) string {
+	if aclValuePattern.MatchString(value) {
+		return value
+	}
+
+	return "'" + value + "'"
+}
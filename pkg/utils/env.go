@@ -3,8 +3,9 @@ package utils
 import "os"
 
 const (
-	HelperImageEnv  = "HELPER_IMAGE"
-	RsyslogImageEnv = "RSYSLOG_IMAGE"
+	HelperImageEnv   = "HELPER_IMAGE"
+	RsyslogImageEnv  = "RSYSLOG_IMAGE"
+	HAProxyBinaryEnv = "HAPROXY_BINARY"
 )
 
 func GetHelperImage() string {
@@ -14,3 +15,7 @@ func GetHelperImage() string {
 func GetRsyslogImage() string {
 	return os.Getenv(RsyslogImageEnv)
 }
+
+func GetHAProxyBinary() string {
+	return os.Getenv(HAProxyBinaryEnv)
+}
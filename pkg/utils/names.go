@@ -15,6 +15,12 @@ func GetServiceName(instance *proxyv1alpha1.Instance) string {
 	return fmt.Sprintf("%s-haproxy", instance.Name)
 }
 
+// GetPeersServiceName returns the name of the headless Service which gives the replicas of the instance stable DNS
+// names to reach each other as peers.
+func GetPeersServiceName(instance *proxyv1alpha1.Instance) string {
+	return fmt.Sprintf("%s-haproxy-peers", instance.Name)
+}
+
 func GetRouteName(frontend *configv1alpha1.Frontend, bind configv1alpha1.Bind) string {
 	if bind.Name != "" {
 		return fmt.Sprintf("%s-%s-haproxy", frontend.Name, bind.Name)
@@ -22,3 +28,9 @@ func GetRouteName(frontend *configv1alpha1.Frontend, bind configv1alpha1.Bind) s
 
 	return fmt.Sprintf("%s-haproxy", frontend.Name)
 }
+
+// GetRuntimeTLSSecretName returns the name of the Secret holding the CA and the certificates which secure the runtime
+// API exposed for runtime sync.
+func GetRuntimeTLSSecretName(instance *proxyv1alpha1.Instance) string {
+	return fmt.Sprintf("%s-haproxy-runtime-tls", instance.Name)
+}
@@ -0,0 +1,13 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SecretKeySelector selects the key of the Secret with the given name in the namespace of the referencing object.
+func SecretKeySelector(name, key string) *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		Key:                  key,
+	}
+}
@@ -0,0 +1,14 @@
+package validation
+
+import "context"
+
+// FakeValidator is a ConfigValidator for tests. It records the validated files and returns Err.
+type FakeValidator struct {
+	Err   error
+	Files map[string][]byte
+}
+
+func (v *FakeValidator) Validate(_ context.Context, files map[string][]byte) error {
+	v.Files = files
+	return v.Err
+}
@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	configDir  = "/usr/local/etc/haproxy"
+	configFile = "haproxy.cfg"
+	envFile    = "env"
+)
+
+var alertRegex = regexp.MustCompile(`\[ALERT\][^:]*:\s*(?:config\s*:\s*)?(.*)`)
+var parsingRegex = regexp.MustCompile(`parsing \[[^\]]*:(\d+)\]\s*:\s*(.*)`)
+
+// ConfigValidator checks a complete set of configuration files before they are published. The files are keyed by
+// their name in the configuration directory.
+type ConfigValidator interface {
+	Validate(ctx context.Context, files map[string][]byte) error
+}
+
+// ConfigError describes why a configuration was rejected.
+type ConfigError struct {
+	// Line is the number of the failing line in haproxy.cfg, zero if the error is not related to a single line.
+	Line int
+	// Text is the content of the failing line.
+	Text    string
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("invalid configuration at line %d '%s': %s", e.Line, e.Text, e.Message)
+	}
+
+	return fmt.Sprintf("invalid configuration: %s", e.Message)
+}
+
+type haproxyValidator struct {
+	binary string
+}
+
+// NewHAProxyValidator returns a ConfigValidator which runs the given HAProxy binary in check mode.
+func NewHAProxyValidator(binary string) ConfigValidator {
+	return &haproxyValidator{binary: binary}
+}
+
+func (v *haproxyValidator) Validate(ctx context.Context, files map[string][]byte) error {
+	dir, err := os.MkdirTemp("", "haproxy-config-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// files reference each other by absolute path, e.g. in crt-lists
+	for name, data := range files {
+		data = bytes.ReplaceAll(data, []byte(configDir+"/"), []byte(dir+"/"))
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			return err
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, v.binary, "-c", "-q", "-f", filepath.Join(dir, configFile)) //#nosec
+	cmd.Env = append(os.Environ(), environment(files[envFile])...)
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*exec.ExitError); !ok {
+		return err
+	}
+
+	return parseOutput(strings.ReplaceAll(string(out), dir, configDir), string(files[configFile]))
+}
+
+// environment returns the variables of the env file, which HAProxy expands in the configuration.
+func environment(data []byte) []string {
+	var envs []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, "=") {
+			envs = append(envs, line)
+		}
+	}
+
+	return envs
+}
+
+func parseOutput(output, config string) *ConfigError {
+	var alerts []string
+	for _, line := range strings.Split(output, "\n") {
+		if match := parsingRegex.FindStringSubmatch(line); match != nil {
+			number, _ := strconv.Atoi(match[1])
+
+			var text string
+			if lines := strings.Split(config, "\n"); number > 0 && number <= len(lines) {
+				text = strings.TrimSpace(lines[number-1])
+			}
+
+			return &ConfigError{Line: number, Text: text, Message: strings.TrimSpace(match[2])}
+		}
+
+		if match := alertRegex.FindStringSubmatch(line); match != nil {
+			alerts = append(alerts, strings.TrimSpace(match[1]))
+		}
+	}
+
+	if len(alerts) == 0 {
+		return &ConfigError{Message: strings.TrimSpace(output)}
+	}
+
+	return &ConfigError{Message: strings.Join(alerts, "; ")}
+}
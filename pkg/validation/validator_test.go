@@ -0,0 +1,80 @@
+package validation_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/six-group/haproxy-operator/pkg/validation"
+)
+
+var _ = Describe("HAProxyValidator", func() {
+	var ctx context.Context
+
+	// validator returns a validator running a shell script in place of HAProxy. The script is called with
+	// the arguments -c -q -f <config>.
+	validator := func(script string) validation.ConfigValidator {
+		binary := filepath.Join(GinkgoT().TempDir(), "haproxy")
+		Ω(os.WriteFile(binary, []byte("#!/bin/sh\n"+script), 0o700)).ShouldNot(HaveOccurred())
+
+		return validation.NewHAProxyValidator(binary)
+	}
+
+	config := map[string][]byte{
+		"haproxy.cfg": []byte("global\n  maxconn 100\nfrontend web\n  foo bar\n"),
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should accept a valid configuration", func() {
+		Ω(validator("exit 0").Validate(ctx, config)).ShouldNot(HaveOccurred())
+	})
+	It("should report the failing line of the configuration", func() {
+		err := validator(`echo "[ALERT]    (1) : config : parsing [$4:4] : unknown keyword 'foo' in 'frontend' section"
+echo "[ALERT]    (1) : config : Error(s) found in configuration file : $4"
+exit 1
+`).Validate(ctx, config)
+		Ω(err).Should(Equal(&validation.ConfigError{Line: 4, Text: "foo bar", Message: "unknown keyword 'foo' in 'frontend' section"}))
+		Ω(err).Should(MatchError("invalid configuration at line 4 'foo bar': unknown keyword 'foo' in 'frontend' section"))
+	})
+	It("should report the alerts not related to a line", func() {
+		err := validator(`echo "[NOTICE]   (1) : haproxy version is 2.8.3"
+echo "[ALERT]    (1) : config : backend 'app' has no server available"
+echo "[ALERT]    (1) : config : Fatal errors found in configuration."
+exit 1
+`).Validate(ctx, config)
+		Ω(err).Should(MatchError("invalid configuration: backend 'app' has no server available; Fatal errors found in configuration."))
+	})
+	It("should report the output without alerts", func() {
+		err := validator("echo 'Segmentation fault'\nexit 139\n").Validate(ctx, config)
+		Ω(err).Should(MatchError("invalid configuration: Segmentation fault"))
+	})
+	It("should replace the path of the temporary directory by the configuration directory", func() {
+		files := map[string][]byte{
+			"haproxy.cfg":  []byte("frontend web\n  bind :443 ssl crt-list /usr/local/etc/haproxy/web.crt-list\n"),
+			"web.crt-list": []byte("/usr/local/etc/haproxy/web.crt\n"),
+		}
+		err := validator(`dir=$(dirname "$4")
+grep -q "$dir/web.crt" "$dir/web.crt-list" || exit 0
+echo "[ALERT]    (1) : config : cannot open file $dir/web.crt"
+exit 1
+`).Validate(ctx, files)
+		Ω(err).Should(MatchError("invalid configuration: cannot open file /usr/local/etc/haproxy/web.crt"))
+	})
+	It("should set the variables of the env file", func() {
+		files := map[string][]byte{
+			"haproxy.cfg": config["haproxy.cfg"],
+			"env":         []byte("FOO=foo\nBAR=bar=baz"),
+		}
+		script := `[ "$FOO" = "foo" ] && [ "$BAR" = "bar=baz" ] && exit 0
+echo "[ALERT]    (1) : config : FOO=$FOO BAR=$BAR"
+exit 1
+`
+		Ω(validator(script).Validate(ctx, files)).ShouldNot(HaveOccurred())
+		Ω(validator(script).Validate(ctx, config)).Should(MatchError("invalid configuration: FOO= BAR="))
+	})
+})
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/controllers/instance"
+	"github.com/six-group/haproxy-operator/pkg/utils"
+	"github.com/six-group/haproxy-operator/pkg/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// stringsFlag collects the values of a repeatable flag.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// runRender implements the render subcommand, which loads an Instance and its config objects from YAML manifests and
+// generates the configuration files as the operator would, without a cluster.
+func runRender(args []string) error {
+	var files stringsFlag
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	flags.Var(&files, "f", "Manifest file or directory of manifests to load, may be repeated.")
+	namespace := flags.String("namespace", "default", "Namespace of the objects without a namespace.")
+	name := flags.String("instance", "", "Name of the Instance to render, all Instances are rendered if empty.")
+	output := flags.String("o", "", "Directory to write the files to, instead of stdout.")
+	validate := flags.Bool("validate", false, "Validate the configuration with the haproxy binary.")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s render [flags] -f manifests\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if len(files) == 0 || flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	objects, err := loadManifests(files, *namespace)
+	if err != nil {
+		return err
+	}
+
+	var instances []*proxyv1alpha1.Instance
+	for _, object := range objects {
+		if proxy, ok := object.(*proxyv1alpha1.Instance); ok && (*name == "" || proxy.Name == *name) {
+			instances = append(instances, proxy)
+		}
+	}
+	if len(instances) == 0 {
+		return errors.New("no Instance found in the manifests")
+	}
+
+	r := &instance.Reconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Scheme: scheme,
+	}
+	if *validate {
+		binary := utils.GetHAProxyBinary()
+		if binary == "" {
+			return errors.New("haproxy binary not found")
+		}
+		r.ConfigValidator = validation.NewHAProxyValidator(binary)
+	}
+
+	for _, proxy := range instances {
+		data, err := r.Render(context.Background(), proxy)
+		if err != nil {
+			return fmt.Errorf("instance %s/%s: %w", proxy.Namespace, proxy.Name, err)
+		}
+
+		if *output == "" {
+			writeRenderedFiles(os.Stdout, proxy.Name, data)
+			continue
+		}
+
+		dir := filepath.Join(*output, proxy.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		for file, content := range data {
+			if err := os.WriteFile(filepath.Join(dir, file), content, 0o600); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadManifests decodes the objects in the given files and directories. Objects of unknown kinds are skipped.
+func loadManifests(paths []string, namespace string) ([]client.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	var objects []client.Object
+	for _, path := range paths {
+		files, err := manifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+
+			reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+			for {
+				document, err := reader.Read()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", file, err)
+				}
+				if len(bytes.TrimSpace(document)) == 0 {
+					continue
+				}
+
+				decoded, gvk, err := decoder.Decode(document, nil, nil)
+				if runtime.IsNotRegisteredError(err) {
+					fmt.Fprintf(os.Stderr, "%s: skipping object of unknown kind %s\n", file, gvk)
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", file, err)
+				}
+
+				object, ok := decoded.(client.Object)
+				if !ok {
+					continue
+				}
+				if object.GetNamespace() == "" {
+					object.SetNamespace(namespace)
+				}
+				if defaulter, ok := object.(interface{ Default() }); ok {
+					defaulter.Default()
+				}
+				objects = append(objects, object)
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+func manifestFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch filepath.Ext(file) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				files = append(files, file)
+			}
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+// writeRenderedFiles writes the files of an instance with haproxy.cfg first and the others sorted by name.
+func writeRenderedFiles(w io.Writer, name string, data map[string][]byte) {
+	files := make([]string, 0, len(data))
+	for file := range data {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if (files[i] == "haproxy.cfg") != (files[j] == "haproxy.cfg") {
+			return files[i] == "haproxy.cfg"
+		}
+		return files[i] < files[j]
+	})
+
+	for _, file := range files {
+		fmt.Fprintf(w, "# %s/%s\n", name, file)
+		content := data[file]
+		w.Write(content) //nolint:errcheck
+		if len(content) > 0 && content[len(content)-1] != '\n' {
+			fmt.Fprintln(w)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	parser "github.com/haproxytech/config-parser/v4"
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:webhook:path=/mutate-config-haproxy-com-v1alpha1-listen,mutating=true,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=listens,verbs=create;update,versions=v1alpha1,name=mlisten.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-config-haproxy-com-v1alpha1-listen,mutating=false,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=listens,verbs=create;update,versions=v1alpha1,name=vlisten.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/mutate-config-haproxy-com-v1alpha1-frontend,mutating=true,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=frontends,verbs=create;update,versions=v1alpha1,name=mfrontend.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-config-haproxy-com-v1alpha1-frontend,mutating=false,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=frontends,verbs=create;update,versions=v1alpha1,name=vfrontend.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/mutate-config-haproxy-com-v1alpha1-backend,mutating=true,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=backends,verbs=create;update,versions=v1alpha1,name=mbackend.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-config-haproxy-com-v1alpha1-backend,mutating=false,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=backends,verbs=create;update,versions=v1alpha1,name=vbackend.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/mutate-config-haproxy-com-v1alpha1-resolver,mutating=true,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=resolvers,verbs=create;update,versions=v1alpha1,name=mresolver.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-config-haproxy-com-v1alpha1-resolver,mutating=false,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=resolvers,verbs=create;update,versions=v1alpha1,name=vresolver.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/mutate-config-haproxy-com-v1alpha1-peers,mutating=true,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=peers,verbs=create;update,versions=v1alpha1,name=mpeers.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-config-haproxy-com-v1alpha1-peers,mutating=false,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=peers,verbs=create;update,versions=v1alpha1,name=vpeers.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/mutate-config-haproxy-com-v1alpha1-userlist,mutating=true,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=userlists,verbs=create;update,versions=v1alpha1,name=muserlist.config.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-config-haproxy-com-v1alpha1-userlist,mutating=false,failurePolicy=fail,sideEffects=None,groups=config.haproxy.com,resources=userlists,verbs=create;update,versions=v1alpha1,name=vuserlist.config.haproxy.com,admissionReviewVersions=v1
+
+// ConfigWebhook defaults and validates the objects of the config.haproxy.com API.
+type ConfigWebhook struct {
+	client.Client
+}
+
+type defaulter interface {
+	Default()
+}
+
+// SetupWithManager registers the webhooks of all config.haproxy.com kinds with the Manager.
+func (w *ConfigWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	for _, object := range []configv1alpha1.Object{&configv1alpha1.Listen{}, &configv1alpha1.Frontend{}, &configv1alpha1.Backend{}, &configv1alpha1.Resolver{}, &configv1alpha1.Peers{}, &configv1alpha1.Userlist{}} {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(object).WithDefaulter(w).WithValidator(w).Complete(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *ConfigWebhook) Default(_ context.Context, obj runtime.Object) error {
+	object, ok := obj.(defaulter)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	object.Default()
+
+	return nil
+}
+
+func (w *ConfigWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return w.validate(ctx, obj)
+}
+
+func (w *ConfigWebhook) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return w.validate(ctx, newObj)
+}
+
+func (w *ConfigWebhook) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (w *ConfigWebhook) validate(ctx context.Context, obj runtime.Object) error {
+	object, ok := obj.(configv1alpha1.Object)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+
+	p, err := parser.New()
+	if err != nil {
+		return err
+	}
+	if err := object.DeepCopyObject().(configv1alpha1.Object).AddToParser(p); err != nil {
+		return err
+	}
+
+	return validateConfigName(ctx, w.Client, object)
+}
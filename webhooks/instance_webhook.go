@@ -0,0 +1,69 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	parser "github.com/haproxytech/config-parser/v4"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:webhook:path=/mutate-proxy-haproxy-com-v1alpha1-instance,mutating=true,failurePolicy=fail,sideEffects=None,groups=proxy.haproxy.com,resources=instances,verbs=create;update,versions=v1alpha1,name=minstance.proxy.haproxy.com,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-proxy-haproxy-com-v1alpha1-instance,mutating=false,failurePolicy=fail,sideEffects=None,groups=proxy.haproxy.com,resources=instances,verbs=create;update,versions=v1alpha1,name=vinstance.proxy.haproxy.com,admissionReviewVersions=v1
+
+// InstanceWebhook defaults and validates the objects of the proxy.haproxy.com API.
+type InstanceWebhook struct {
+	client.Client
+}
+
+// SetupWithManager registers the Instance webhooks with the Manager.
+func (w *InstanceWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&proxyv1alpha1.Instance{}).WithDefaulter(w).WithValidator(w).Complete()
+}
+
+func (w *InstanceWebhook) Default(_ context.Context, obj runtime.Object) error {
+	instance, ok := obj.(*proxyv1alpha1.Instance)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	instance.Default()
+
+	return nil
+}
+
+func (w *InstanceWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return w.validate(ctx, obj)
+}
+
+func (w *InstanceWebhook) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return w.validate(ctx, newObj)
+}
+
+func (w *InstanceWebhook) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (w *InstanceWebhook) validate(ctx context.Context, obj runtime.Object) error {
+	instance, ok := obj.(*proxyv1alpha1.Instance)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+
+	p, err := parser.New()
+	if err != nil {
+		return err
+	}
+	if err := instance.DeepCopy().AddToParser(p); err != nil {
+		return err
+	}
+	if instance.Spec.Metrics != nil {
+		if err := instance.Spec.Metrics.AddToParser(p); err != nil {
+			return err
+		}
+	}
+
+	return validateInstanceName(ctx, w.Client, instance)
+}
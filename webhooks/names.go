@@ -0,0 +1,129 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// validateConfigName rejects a config object if its name is already used by the instance or another kind of config
+// object selected by the same instance. Such collisions would otherwise fail the reconciliation of the instance.
+func validateConfigName(ctx context.Context, c client.Client, object client.Object) error {
+	instances := &proxyv1alpha1.InstanceList{}
+	if err := c.List(ctx, instances, client.InNamespace(object.GetNamespace())); err != nil {
+		return err
+	}
+
+	for i := range instances.Items {
+		instance := &instances.Items[i]
+
+		selector, err := metav1.LabelSelectorAsSelector(&instance.Spec.Configuration.LabelSelector)
+		if err != nil {
+			return err
+		}
+		if !selector.Matches(labels.Set(object.GetLabels())) {
+			continue
+		}
+
+		if instance.Name == object.GetName() {
+			return fmt.Errorf("name %s already used by resource of kind Instance", object.GetName())
+		}
+
+		objects, err := listConfigObjects(ctx, c, instance)
+		if err != nil {
+			return err
+		}
+		for _, other := range objects {
+			if other.GetName() == object.GetName() && kind(other) != kind(object) {
+				return fmt.Errorf("name %s already used by resource of kind %s", object.GetName(), kind(other))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateInstanceName rejects an instance if its name is already used by one of the selected config objects.
+func validateInstanceName(ctx context.Context, c client.Client, instance *proxyv1alpha1.Instance) error {
+	objects, err := listConfigObjects(ctx, c, instance)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		if object.GetName() == instance.Name {
+			return fmt.Errorf("name %s already used by resource of kind %s", instance.Name, kind(object))
+		}
+	}
+
+	return nil
+}
+
+func listConfigObjects(ctx context.Context, c client.Client, instance *proxyv1alpha1.Instance) ([]client.Object, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&instance.Spec.Configuration.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []client.Object
+
+	listens := &configv1alpha1.ListenList{}
+	if err := c.List(ctx, listens, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	for i := range listens.Items {
+		objects = append(objects, &listens.Items[i])
+	}
+
+	frontends := &configv1alpha1.FrontendList{}
+	if err := c.List(ctx, frontends, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	for i := range frontends.Items {
+		objects = append(objects, &frontends.Items[i])
+	}
+
+	backends := &configv1alpha1.BackendList{}
+	if err := c.List(ctx, backends, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	for i := range backends.Items {
+		objects = append(objects, &backends.Items[i])
+	}
+
+	resolvers := &configv1alpha1.ResolverList{}
+	if err := c.List(ctx, resolvers, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	for i := range resolvers.Items {
+		objects = append(objects, &resolvers.Items[i])
+	}
+
+	peers := &configv1alpha1.PeersList{}
+	if err := c.List(ctx, peers, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	for i := range peers.Items {
+		objects = append(objects, &peers.Items[i])
+	}
+
+	userlists := &configv1alpha1.UserlistList{}
+	if err := c.List(ctx, userlists, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	for i := range userlists.Items {
+		objects = append(objects, &userlists.Items[i])
+	}
+
+	return objects, nil
+}
+
+func kind(object client.Object) string {
+	return reflect.Indirect(reflect.ValueOf(object)).Type().Name()
+}
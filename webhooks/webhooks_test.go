@@ -0,0 +1,139 @@
+package webhooks_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1alpha1 "github.com/six-group/haproxy-operator/apis/config/v1alpha1"
+	proxyv1alpha1 "github.com/six-group/haproxy-operator/apis/proxy/v1alpha1"
+	"github.com/six-group/haproxy-operator/webhooks"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Webhooks", Label("webhook"), func() {
+	var (
+		ctx      context.Context
+		scheme   *runtime.Scheme
+		proxy    *proxyv1alpha1.Instance
+		frontend *configv1alpha1.Frontend
+		labels   map[string]string
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Ω(clientgoscheme.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Ω(configv1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+		Ω(proxyv1alpha1.AddToScheme(scheme)).ShouldNot(HaveOccurred())
+
+		ctx = context.Background()
+
+		labels = map[string]string{"proxy.haproxy.com/instance": "bar"}
+
+		proxy = &proxyv1alpha1.Instance{
+			ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"},
+			Spec: proxyv1alpha1.InstanceSpec{
+				Configuration: proxyv1alpha1.Configuration{
+					LabelSelector: metav1.LabelSelector{MatchLabels: labels},
+				},
+			},
+		}
+
+		frontend = &configv1alpha1.Frontend{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "foo", Labels: labels},
+		}
+	})
+
+	Context("ConfigWebhook", func() {
+		It("should accept a valid backend", func() {
+			w := &webhooks.ConfigWebhook{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy, frontend).Build()}
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "foo", Labels: labels},
+				Spec: configv1alpha1.BackendSpec{
+					Servers: []configv1alpha1.Server{{Name: "app", Address: "localhost", Port: 8080}},
+				},
+			}
+			Ω(w.ValidateCreate(ctx, backend)).ShouldNot(HaveOccurred())
+		})
+		It("should reject mutually exclusive cookie modes", func() {
+			w := &webhooks.ConfigWebhook{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "foo"},
+				Spec: configv1alpha1.BackendSpec{
+					Cookie: &configv1alpha1.Cookie{
+						Name: "session",
+						Mode: configv1alpha1.CookieMode{Insert: true, Prefix: true},
+					},
+				},
+			}
+			Ω(w.ValidateCreate(ctx, backend)).Should(MatchError("you can only select one cookie mode"))
+		})
+		It("should reject mutually exclusive redirect types", func() {
+			w := &webhooks.ConfigWebhook{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			frontend.Spec.HTTPRequest = &configv1alpha1.HTTPRequestRules{
+				Redirect: []configv1alpha1.Redirect{
+					{Type: configv1alpha1.RedirectType{Location: true, Scheme: true}, Value: "https"},
+				},
+			}
+			Ω(w.ValidateUpdate(ctx, frontend, frontend)).Should(HaveOccurred())
+		})
+		It("should reject a name used by another kind of the same instance", func() {
+			w := &webhooks.ConfigWebhook{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy, frontend).Build()}
+			backend := &configv1alpha1.Backend{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "foo", Labels: labels},
+			}
+			Ω(w.ValidateCreate(ctx, backend)).Should(MatchError("name web already used by resource of kind Frontend"))
+
+			backend.Labels = nil
+			Ω(w.ValidateCreate(ctx, backend)).ShouldNot(HaveOccurred())
+		})
+		It("should reject the name of the instance", func() {
+			w := &webhooks.ConfigWebhook{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(proxy).Build()}
+			resolver := &configv1alpha1.Resolver{
+				ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo", Labels: labels},
+			}
+			Ω(w.ValidateCreate(ctx, resolver)).Should(MatchError("name bar already used by resource of kind Instance"))
+		})
+		It("should set defaults", func() {
+			w := &webhooks.ConfigWebhook{}
+			resolver := &configv1alpha1.Resolver{}
+			Ω(w.Default(ctx, resolver)).ShouldNot(HaveOccurred())
+			Ω(resolver.Spec.ResolveRetries).Should(Equal(pointer.Int64(3)))
+			Ω(resolver.Spec.ParseResolvConf).Should(Equal(pointer.Bool(false)))
+
+			backend := &configv1alpha1.Backend{
+				Spec: configv1alpha1.BackendSpec{
+					Servers: []configv1alpha1.Server{{Name: "app"}},
+					Cookie:  &configv1alpha1.Cookie{Secure: pointer.Bool(true)},
+				},
+			}
+			Ω(w.Default(ctx, backend)).ShouldNot(HaveOccurred())
+			Ω(backend.Spec.Servers[0].SendProxy).Should(Equal(pointer.Bool(false)))
+			Ω(backend.Spec.Cookie.Secure).Should(Equal(pointer.Bool(true)))
+			Ω(backend.Spec.Cookie.Indirect).Should(Equal(pointer.Bool(false)))
+		})
+	})
+
+	Context("InstanceWebhook", func() {
+		It("should reject a name used by a config object", func() {
+			frontend.Name = "bar"
+			w := &webhooks.InstanceWebhook{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(frontend).Build()}
+			Ω(w.ValidateCreate(ctx, proxy)).Should(MatchError("name bar already used by resource of kind Frontend"))
+		})
+		It("should reject an invalid global configuration", func() {
+			proxy.Spec.Configuration.Global.Logging = &proxyv1alpha1.GlobalLoggingConfiguration{Enabled: true, Address: "127.0.0.1", Facility: "unknown"}
+			w := &webhooks.InstanceWebhook{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+			Ω(w.ValidateCreate(ctx, proxy)).Should(HaveOccurred())
+		})
+		It("should set defaults", func() {
+			proxy.Spec.Metrics = &proxyv1alpha1.Metrics{Enabled: true, Port: 8404}
+			w := &webhooks.InstanceWebhook{}
+			Ω(w.Default(ctx, proxy)).ShouldNot(HaveOccurred())
+			Ω(proxy.Spec.Metrics.Address).Should(Equal(pointer.String("0.0.0.0")))
+		})
+	})
+})